@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/crawler"
+	"CrawlerLinkedin/internal/logging"
+)
+
+func crawlCmd(args []string) error {
+	fs := newFlags("crawl", "--query Q (--credentials-stdin | --credentials-file F | LINKEDIN_EMAIL/LINKEDIN_PASSWORD) [flags]",
+		"Faz login no LinkedIn, busca, captura as páginas e grava o CSV e o summary.json na pasta de saída.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	s := addSessionFlags(fs, &cfg)
+	query := fs.String("query", "", "Texto da busca (ex: \"software engineer\")")
+	// com padrão vindo da configuração
+	fs.IntVar(&cfg.Defaults.MaxPages, "max-pages", cfg.Defaults.MaxPages, "Número máximo de páginas para capturar (>=1)")
+	fs.StringVar(&cfg.Defaults.Geo, "geo", cfg.Defaults.Geo, "IDs geoUrn de localidade separados por vírgula (padrão: São Paulo; vazio = qualquer lugar)")
+	fs.BoolVar(&cfg.Defaults.FirstCompany, "first-company", cfg.Defaults.FirstCompany, "Aplicar o 1º item do filtro 'Empresa atual'")
+	fs.BoolVar(&cfg.Output.DumpHTML, "dump-html", cfg.Output.DumpHTML, "Salvar HTML da página de resultados para depuração")
+	fs.TextVar(&cfg.Throttle.PageDelay, "page-delay", cfg.Throttle.PageDelay, "Pausa sorteada entre páginas (mínimo-máximo)")
+	fs.Parse(args)
+	if cfg.Defaults.MaxPages < 1 {
+		cfg.Defaults.MaxPages = 1
+	}
+	if *query == "" {
+		fs.Usage()
+		return errors.New("falta --query")
+	}
+	opts, err := s.options(fs, cfg)
+	if err != nil {
+		return err
+	}
+	opts.Query = *query
+	if err := crawler.Run(cfg, opts); err != nil {
+		os.Exit(1) // Run já registrou o erro e gravou o resumo
+	}
+	return nil
+}
+
+// sessionFlags são as flags de quem faz login no LinkedIn (crawl e invites):
+// credenciais, sessão do Chromium, desafios e integração com o servidor web.
+type sessionFlags struct {
+	email, credsFile, userDataDir, jobID, account *string
+	credsStdin, interactive, metrics              *bool
+}
+
+func addSessionFlags(fs *flag.FlagSet, cfg *config.Config) sessionFlags {
+	s := sessionFlags{
+		email:       fs.String("email", "", "Email do LinkedIn (a senha vem de --credentials-*, ou LINKEDIN_PASSWORD)"),
+		credsFile:   fs.String("credentials-file", "", "Arquivo JSON com {\"email\",\"password\"}"),
+		credsStdin:  fs.Bool("credentials-stdin", false, "Ler credenciais JSON ({\"email\",\"password\"}) da primeira linha do stdin"),
+		interactive: fs.Bool("interactive", false, "Desafios (captcha/checkpoint/2FA) resolvidos pela UI web: screenshots no stdout, comandos no stdin"),
+		userDataDir: fs.String("user-data-dir", "", "Pasta de perfil do Chromium para reaproveitar a sessão (cookies) entre execuções"),
+		metrics:     fs.Bool("metrics", false, "Reportar métricas como eventos no stdout (usado pelo servidor web para o /metrics)"),
+		jobID:       fs.String("job-id", "", "ID do job no servidor web (vai em todo registro de log)"),
+		account:     fs.String("account", "", "Nome da conta no histórico de convites (padrão: o email)"),
+	}
+	fs.BoolVar(&cfg.Browser.Headless, "headless", cfg.Browser.Headless, "Rodar Chromium em modo headless")
+	fs.StringVar(&cfg.Browser.UserAgent, "user-agent", cfg.Browser.UserAgent, "User agent do Chromium")
+	fs.StringVar(&cfg.Browser.Lang, "lang", cfg.Browser.Lang, "Idioma do Chromium")
+	fs.StringVar(&cfg.Output.Dir, "out-dir", cfg.Output.Dir, "Diretório de saída (CSV, relatórios e resumo)")
+	fs.DurationVar(&cfg.Timeouts.Run, "timeout", cfg.Timeouts.Run, "Tempo máximo da execução inteira")
+	fs.TextVar(&cfg.Throttle.InviteDelay, "invite-delay", cfg.Throttle.InviteDelay, "Pausa sorteada entre convites (mínimo-máximo)")
+	fs.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "Formato do log no stderr: text ou json")
+	fs.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "Navegar e capturar sem clicar no que muda algo (convites, filtros): o clique vai para o log e um screenshot com o botão destacado")
+	return s
+}
+
+// options valida a configuração, liga o log e lê as credenciais (depois do Parse).
+func (s sessionFlags) options(fs *flag.FlagSet, cfg config.Config) (crawler.Options, error) {
+	if err := cfg.Validate(); err != nil {
+		return crawler.Options{}, fmt.Errorf("configuração inválida: %w", err)
+	}
+	if err := logging.Setup(cfg.LogFormat); err != nil {
+		return crawler.Options{}, err
+	}
+	if *s.jobID != "" {
+		slog.SetDefault(slog.With(logging.KeyJobID, *s.jobID))
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	creds, err := crawler.LoadCredentials(stdin, *s.email, *s.credsFile, *s.credsStdin)
+	if err != nil {
+		return crawler.Options{}, fmt.Errorf("credenciais: %w", err)
+	}
+	if creds.Email == "" || creds.Password == "" {
+		fs.Usage()
+		return crawler.Options{}, errors.New("faltam credenciais")
+	}
+	return crawler.Options{
+		Credentials: creds,
+		UserDataDir: *s.userDataDir,
+		Interactive: *s.interactive,
+		Metrics:     *s.metrics,
+		Account:     *s.account,
+		JobID:       *s.jobID,
+		Stdin:       stdin,
+		Events:      os.Stdout,
+	}, nil
+}
+
+// addInviteFlags registra as flags de quem envia convites (invites send) e
+// devolve o caminho do modelo da nota.
+func addInviteFlags(fs *flag.FlagSet, cfg *config.Config) *string {
+	fs.StringVar(&cfg.Invites.Ledger, "ledger", cfg.Invites.Ledger, "Histórico de convites (JSONL): quem já foi convidado não é convidado de novo")
+	fs.StringVar(&cfg.Invites.Suppress, "suppress", cfg.Invites.Suppress, "Lista de não contatar: URL de perfil ou \"empresa: Nome\" por linha (vazio = nenhuma)")
+	fs.IntVar(&cfg.Invites.DailyQuota, "daily-quota", cfg.Invites.DailyQuota, "Convites enviados por conta e dia, contados no histórico (0 = sem cota)")
+	fs.IntVar(&cfg.Invites.WeeklyQuota, "weekly-quota", cfg.Invites.WeeklyQuota, "Convites enviados por conta em 7 dias corridos (0 = sem cota)")
+	return fs.String("note-template", "", "Modelo text/template da nota dos convites ({{.FirstName}}, {{.Company}}, {{.Title}}); vazio = sem nota")
+}
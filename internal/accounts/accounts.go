@@ -0,0 +1,300 @@
+// Package accounts é o registro de contas do LinkedIn usadas pelo crawler:
+// credencial no cofre, pasta de sessão do Chromium, orçamentos diários e
+// cooldown depois de desafios. O contador de páginas fica persistido aqui; os
+// convites são contados no histórico de convites (ledger), o mesmo que o
+// crawler usa para respeitar as cotas.
+package accounts
+
+import (
+	"cmp"
+	"errors"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/jsonfile"
+	"CrawlerLinkedin/internal/ledger"
+)
+
+var ErrNotFound = errors.New("conta não encontrada")
+
+const (
+	DefaultDailyPages    = 30
+	DefaultCooldownHours = 24
+
+	usageRetention = 14 * 24 * time.Hour
+)
+
+type Account struct {
+	Alias         string    `json:"alias"`
+	Owner         string    `json:"owner"`
+	Credential    string    `json:"credential"`  // alias no cofre
+	SessionDir    string    `json:"session_dir"` // --user-data-dir do Chromium
+	DailyPages    int       `json:"daily_pages"`
+	DailyInvites  int       `json:"daily_invites"`  // 0 = a invites.daily_quota da configuração
+	WeeklyInvites int       `json:"weekly_invites"` // em 7 dias; 0 = a invites.weekly_quota da configuração
+	CooldownHours int       `json:"cooldown_hours"`
+	CooldownUntil time.Time `json:"cooldown_until,omitzero"`
+}
+
+// InviteQuota é a cota de convites que vale para a conta: a dela, ou a de
+// def (a da configuração) onde ela tem 0. Na cota final 0 = sem limite, como
+// no crawler (--daily-quota/--weekly-quota).
+func (a Account) InviteQuota(def ledger.Quota) ledger.Quota {
+	return ledger.Quota{
+		Daily:  cmp.Or(a.DailyInvites, def.Daily),
+		Weekly: cmp.Or(a.WeeklyInvites, def.Weekly),
+	}
+}
+
+// Usage é o consumo de páginas de uma conta num dia (data local, AAAA-MM-DD).
+type Usage struct {
+	Owner string `json:"owner"`
+	Alias string `json:"alias"`
+	Date  string `json:"date"`
+	Pages int    `json:"pages"`
+}
+
+// Status junta conta, uso de hoje (e da semana) e orçamento restante para a UI.
+type Status struct {
+	Account
+	PagesToday   int `json:"pages_today"`
+	InvitesToday int `json:"invites_today"` // enviados, segundo o histórico
+	InvitesWeek  int `json:"invites_week"`  // nos últimos 7 dias corridos
+	// cotas que valem (as da conta ou as da configuração); 0 = sem cota
+	DailyInviteQuota  int  `json:"daily_invite_quota"`
+	WeeklyInviteQuota int  `json:"weekly_invite_quota"`
+	RemainingPages    int  `json:"remaining_pages"`
+	RemainingInvites  int  `json:"remaining_invites"` // o menor entre dia e semana; -1 = sem cota; 0 se pausada
+	CoolingDown       bool `json:"cooling_down"`
+	InvitesPaused     bool `json:"invites_paused"`
+	// InvitesPausedUntil: o LinkedIn avisou do limite semanal; sem convites até lá
+	InvitesPausedUntil time.Time `json:"invites_paused_until,omitzero"`
+}
+
+// History devolve o histórico de convites de owner (o mesmo passado ao
+// crawler em --ledger).
+type History func(owner string) (*ledger.Checker, error)
+
+type fileData struct {
+	Accounts []*Account `json:"accounts"`
+	Usage    []*Usage   `json:"usage"`
+}
+
+type Registry struct {
+	mu      sync.Mutex
+	path    string
+	data    fileData
+	quota   ledger.Quota // invites.daily_quota/weekly_quota
+	history History
+}
+
+func Open(path string) (*Registry, error) {
+	r := &Registry{path: path}
+	if err := jsonfile.Load(path, &r.data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UseLedger liga as contagens de convites ao histórico: def é a cota da
+// configuração (vale onde a conta tem 0). Sem isso nenhuma conta tem
+// convites restantes.
+func (r *Registry) UseLedger(def ledger.Quota, history History) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quota, r.history = def, history
+}
+
+// Put cria ou atualiza a conta; campos de orçamento zerados recebem os defaults.
+func (r *Registry) Put(a Account) error {
+	a.Alias = strings.TrimSpace(a.Alias)
+	if a.Alias == "" {
+		return errors.New("alias vazio")
+	}
+	if a.Credential == "" {
+		a.Credential = a.Alias
+	}
+	if a.DailyPages <= 0 {
+		a.DailyPages = DefaultDailyPages
+	}
+	if a.DailyInvites < 0 {
+		a.DailyInvites = 0
+	}
+	if a.WeeklyInvites < 0 {
+		a.WeeklyInvites = 0
+	}
+	if a.CooldownHours <= 0 {
+		a.CooldownHours = DefaultCooldownHours
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if cur := r.findLocked(a.Owner, a.Alias); cur != nil {
+		a.CooldownUntil = cur.CooldownUntil
+		*cur = a
+	} else {
+		r.data.Accounts = append(r.data.Accounts, &a)
+	}
+	return r.saveLocked()
+}
+
+func (r *Registry) Get(owner, alias string) (Account, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a := r.findLocked(owner, alias)
+	if a == nil {
+		return Account{}, ErrNotFound
+	}
+	return *a, nil
+}
+
+func (r *Registry) Delete(owner, alias string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, a := range r.data.Accounts {
+		if a.Owner == owner && a.Alias == alias {
+			r.data.Accounts = append(r.data.Accounts[:i], r.data.Accounts[i+1:]...)
+			return r.saveLocked()
+		}
+	}
+	return ErrNotFound
+}
+
+// List devolve as contas de owner com o consumo do dia de now.
+func (r *Registry) List(owner string, now time.Time) []Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := []Status{}
+	hist := r.historyLocked(owner)
+	for _, a := range r.data.Accounts {
+		if a.Owner == owner {
+			out = append(out, r.statusLocked(a, now, hist))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Alias < out[j].Alias })
+	return out
+}
+
+// Status devolve orçamento restante e situação de cooldown da conta.
+func (r *Registry) Status(owner, alias string, now time.Time) (Status, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a := r.findLocked(owner, alias)
+	if a == nil {
+		return Status{}, ErrNotFound
+	}
+	return r.statusLocked(a, now, r.historyLocked(owner)), nil
+}
+
+// RecordUsage soma páginas ao contador do dia.
+func (r *Registry) RecordUsage(owner, alias string, now time.Time, pages int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u := r.usageLocked(owner, alias, dateKey(now), true)
+	u.Pages += pages
+
+	cutoff := dateKey(now.Add(-usageRetention))
+	kept := r.data.Usage[:0]
+	for _, u := range r.data.Usage {
+		if u.Date >= cutoff {
+			kept = append(kept, u)
+		}
+	}
+	r.data.Usage = kept
+	return r.saveLocked()
+}
+
+// StartCooldown tira a conta de circulação por CooldownHours a partir de now.
+func (r *Registry) StartCooldown(owner, alias string, now time.Time) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a := r.findLocked(owner, alias)
+	if a == nil {
+		return time.Time{}, ErrNotFound
+	}
+	a.CooldownUntil = now.Add(time.Duration(a.CooldownHours) * time.Hour)
+	return a.CooldownUntil, r.saveLocked()
+}
+
+// historyLocked carrega o histórico de convites de owner; nil (sem
+// convites) se não houver ou não abrir.
+func (r *Registry) historyLocked(owner string) *ledger.Checker {
+	if r.history == nil {
+		return nil
+	}
+	hist, err := r.history(owner)
+	if err != nil {
+		slog.Warn("histórico de convites ilegível: contas sem convites", "owner", owner, "err", err)
+		return nil
+	}
+	return hist
+}
+
+// statusLocked monta a situação da conta. Os convites vêm de hist, contados
+// e limitados como no crawler (ledger.Quota.Remaining); hist nil = nenhum
+// convite restante.
+func (r *Registry) statusLocked(a *Account, now time.Time, hist *ledger.Checker) Status {
+	st := Status{
+		Account:     *a,
+		CoolingDown: now.Before(a.CooldownUntil),
+	}
+	if u := r.usageLocked(a.Owner, a.Alias, dateKey(now), false); u != nil {
+		st.PagesToday = u.Pages
+	}
+	st.RemainingPages = max(0, a.DailyPages-st.PagesToday)
+	q := a.InviteQuota(r.quota)
+	st.DailyInviteQuota, st.WeeklyInviteQuota = q.Daily, q.Weekly
+	if hist == nil {
+		return st
+	}
+	u := hist.Usage(a.Alias, now)
+	st.InvitesToday, st.InvitesWeek = u.Today, u.Week
+	if !u.LimitAt.IsZero() {
+		st.InvitesPaused = true
+		st.InvitesPausedUntil = u.LimitAt.Add(ledger.Week)
+	}
+	if left, err := q.Remaining(u); err == nil {
+		st.RemainingInvites = left
+	}
+	return st
+}
+
+func (r *Registry) usageLocked(owner, alias, date string, create bool) *Usage {
+	for _, u := range r.data.Usage {
+		if u.Owner == owner && u.Alias == alias && u.Date == date {
+			return u
+		}
+	}
+	if !create {
+		return nil
+	}
+	u := &Usage{Owner: owner, Alias: alias, Date: date}
+	r.data.Usage = append(r.data.Usage, u)
+	return u
+}
+
+func (r *Registry) findLocked(owner, alias string) *Account {
+	for _, a := range r.data.Accounts {
+		if a.Owner == owner && a.Alias == alias {
+			return a
+		}
+	}
+	return nil
+}
+
+func (r *Registry) saveLocked() error {
+	return jsonfile.Save(r.path, r.data)
+}
+
+func dateKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
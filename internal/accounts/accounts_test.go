@@ -0,0 +1,206 @@
+package accounts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CrawlerLinkedin/internal/ledger"
+)
+
+func openRegistry(t *testing.T) (*Registry, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, path
+}
+
+// historyOf devolve um History com as mesmas entradas para qualquer dono.
+func historyOf(entries ...ledger.Entry) History {
+	return func(string) (*ledger.Checker, error) {
+		return ledger.NewChecker(ledger.Suppression{}, entries), nil
+	}
+}
+
+func TestPutDefaultsAndOwners(t *testing.T) {
+	r, path := openRegistry(t)
+	if err := r.Put(Account{Alias: " rec1 ", Owner: "ana"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Put(Account{Alias: "rec1", Owner: "bia", DailyPages: 5}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Put(Account{Alias: " ", Owner: "ana"}); err == nil {
+		t.Error("alias vazio aceito")
+	}
+
+	a, err := r.Get("ana", "rec1")
+	if err != nil || a.Credential != "rec1" || a.DailyPages != DefaultDailyPages || a.CooldownHours != DefaultCooldownHours {
+		t.Errorf("defaults = %+v, %v", a, err)
+	}
+	if _, err := r.Get("caio", "rec1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("conta de outro dono: %v", err)
+	}
+	if l := r.List("bia", time.Now()); len(l) != 1 || l[0].DailyPages != 5 {
+		t.Errorf("List(bia) = %+v", l)
+	}
+
+	if err := r.Delete("bia", "rec1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Delete("bia", "rec1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete de novo: %v", err)
+	}
+	again, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l := again.List("ana", time.Now()); len(l) != 1 || l[0].Alias != "rec1" {
+		t.Errorf("reaberto: List(ana) = %+v", l)
+	}
+	if l := again.List("bia", time.Now()); len(l) != 0 {
+		t.Errorf("reaberto: List(bia) = %+v", l)
+	}
+}
+
+func TestPagesAndCooldown(t *testing.T) {
+	r, _ := openRegistry(t)
+	if err := r.Put(Account{Alias: "rec1", DailyPages: 10, CooldownHours: 2}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	if err := r.RecordUsage("", "rec1", now, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RecordUsage("", "rec1", now.Add(time.Hour), 3); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := r.Status("", "rec1", now); st.PagesToday != 7 || st.RemainingPages != 3 {
+		t.Errorf("hoje = %+v", st)
+	}
+	// o contador é por dia: no dia seguinte o orçamento volta inteiro
+	if st, _ := r.Status("", "rec1", now.AddDate(0, 0, 1)); st.PagesToday != 0 || st.RemainingPages != 10 {
+		t.Errorf("amanhã = %+v", st)
+	}
+	if err := r.RecordUsage("", "rec1", now, 20); err != nil {
+		t.Fatal(err)
+	}
+	if st, _ := r.Status("", "rec1", now); st.RemainingPages != 0 {
+		t.Errorf("estourado = %+v", st)
+	}
+
+	until, err := r.StartCooldown("", "rec1", now)
+	if err != nil || !until.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("StartCooldown = %v, %v", until, err)
+	}
+	if st, _ := r.Status("", "rec1", now.Add(time.Hour)); !st.CoolingDown {
+		t.Error("sem cooldown dentro da janela")
+	}
+	if st, _ := r.Status("", "rec1", until); st.CoolingDown {
+		t.Error("cooldown depois da janela")
+	}
+	// atualizar a conta não apaga o cooldown em curso
+	if err := r.Put(Account{Alias: "rec1", DailyPages: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := r.Get("", "rec1"); !a.CooldownUntil.Equal(until) {
+		t.Errorf("Put apagou o cooldown: %+v", a)
+	}
+	if _, err := r.StartCooldown("", "nada", now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("StartCooldown de conta inexistente: %v", err)
+	}
+}
+
+func TestInviteQuota(t *testing.T) {
+	r, _ := openRegistry(t)
+	if err := r.Put(Account{Alias: "rec1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Put(Account{Alias: "rec2", DailyInvites: 2}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	// sem histórico ligado, nenhuma conta tem convites
+	if st, _ := r.Status("", "rec1", now); st.RemainingInvites != 0 {
+		t.Errorf("sem UseLedger = %+v", st)
+	}
+
+	r.UseLedger(ledger.Quota{Daily: 5, Weekly: 8}, historyOf(
+		ledger.Entry{At: now.Add(-time.Hour), Account: "rec1", Status: "sent"},
+		ledger.Entry{At: now.Add(-2 * time.Hour), Account: "rec1", Status: "failed"},
+		ledger.Entry{At: now.AddDate(0, 0, -3), Account: "rec1", Status: "sent"},
+		ledger.Entry{At: now.AddDate(0, 0, -3), Account: "rec1", Status: "sent"},
+		ledger.Entry{At: now.AddDate(0, 0, -9), Account: "rec1", Status: "sent"},
+		ledger.Entry{At: now.Add(-time.Hour), Account: "rec2", Status: "sent"},
+	))
+	st, _ := r.Status("", "rec1", now)
+	if st.InvitesToday != 1 || st.InvitesWeek != 3 || st.DailyInviteQuota != 5 || st.WeeklyInviteQuota != 8 || st.RemainingInvites != 4 {
+		t.Errorf("rec1 = %+v", st)
+	}
+	// a cota da conta vale onde não é 0
+	st, _ = r.Status("", "rec2", now)
+	if st.DailyInviteQuota != 2 || st.WeeklyInviteQuota != 8 || st.RemainingInvites != 1 {
+		t.Errorf("rec2 = %+v", st)
+	}
+	// na virada do dia a cota diária volta, a semanal continua contando
+	st, _ = r.Status("", "rec1", now.AddDate(0, 0, 1))
+	if st.InvitesToday != 0 || st.InvitesWeek != 3 || st.RemainingInvites != 5 {
+		t.Errorf("rec1 amanhã = %+v", st)
+	}
+
+	r.UseLedger(ledger.Quota{Weekly: 3}, r.history)
+	if st, _ := r.Status("", "rec1", now); st.RemainingInvites != 0 {
+		t.Errorf("cota semanal esgotada = %+v", st)
+	}
+	r.UseLedger(ledger.Quota{}, r.history)
+	if st, _ := r.Status("", "rec1", now); st.RemainingInvites != -1 {
+		t.Errorf("sem cota = %+v", st)
+	}
+}
+
+func TestWeeklyLimitPause(t *testing.T) {
+	r, _ := openRegistry(t)
+	if err := r.Put(Account{Alias: "rec1"}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	limitAt := now.AddDate(0, 0, -2)
+	r.UseLedger(ledger.Quota{}, historyOf(ledger.Entry{At: limitAt, Account: "rec1", Status: ledger.StatusWeeklyLimit}))
+
+	st, _ := r.Status("", "rec1", now)
+	if !st.InvitesPaused || !st.InvitesPausedUntil.Equal(limitAt.Add(ledger.Week)) || st.RemainingInvites != 0 {
+		t.Errorf("pausada = %+v", st)
+	}
+	st, _ = r.Status("", "rec1", limitAt.Add(ledger.Week))
+	if st.InvitesPaused || st.RemainingInvites != -1 {
+		t.Errorf("depois da pausa = %+v", st)
+	}
+}
+
+func TestUnreadableLedger(t *testing.T) {
+	r, _ := openRegistry(t)
+	if err := r.Put(Account{Alias: "rec1", DailyInvites: 10}); err != nil {
+		t.Fatal(err)
+	}
+	var owners []string
+	r.UseLedger(ledger.Quota{}, func(owner string) (*ledger.Checker, error) {
+		owners = append(owners, owner)
+		return nil, errors.New("arquivo corrompido")
+	})
+	now := time.Now()
+	st, err := r.Status("", "rec1", now)
+	if err != nil || st.RemainingInvites != 0 || st.DailyInviteQuota != 10 || st.RemainingPages != DefaultDailyPages {
+		t.Errorf("Status = %+v, %v", st, err)
+	}
+	if l := r.List("", now); len(l) != 1 || l[0].RemainingInvites != 0 {
+		t.Errorf("List = %+v", l)
+	}
+	if len(owners) != 2 {
+		t.Errorf("histórico consultado %d vezes", len(owners))
+	}
+}
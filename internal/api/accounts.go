@@ -0,0 +1,198 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/totp"
+)
+
+// Account é a conta com o consumo do dia e da semana; a senha nunca sai do servidor.
+type Account struct {
+	Alias              string    `json:"alias"`
+	Email              string    `json:"email"`
+	HasTOTP            bool      `json:"has_totp"`
+	DailyPages         int       `json:"daily_pages"`
+	DailyInvites       int       `json:"daily_invites"`
+	WeeklyInvites      int       `json:"weekly_invites"`
+	CooldownHours      int       `json:"cooldown_hours"`
+	CooldownUntil      time.Time `json:"cooldown_until,omitzero"`
+	CoolingDown        bool      `json:"cooling_down"`
+	InvitesPausedUntil time.Time `json:"invites_paused_until,omitzero"`
+	InvitesPaused      bool      `json:"invites_paused"`
+	PagesToday         int       `json:"pages_today"`
+	InvitesToday       int       `json:"invites_today"`
+	InvitesWeek        int       `json:"invites_week"`
+	DailyInviteQuota   int       `json:"daily_invite_quota"`
+	WeeklyInviteQuota  int       `json:"weekly_invite_quota"`
+	RemainingPages     int       `json:"remaining_pages"`
+	RemainingInvites   int       `json:"remaining_invites"`
+}
+
+func (s *Server) accountView(st accounts.Status) Account {
+	a := Account{
+		Alias:              st.Alias,
+		DailyPages:         st.DailyPages,
+		DailyInvites:       st.DailyInvites,
+		WeeklyInvites:      st.WeeklyInvites,
+		CooldownHours:      st.CooldownHours,
+		CooldownUntil:      st.CooldownUntil,
+		CoolingDown:        st.CoolingDown,
+		InvitesPausedUntil: st.InvitesPausedUntil,
+		InvitesPaused:      st.InvitesPaused,
+		PagesToday:         st.PagesToday,
+		InvitesToday:       st.InvitesToday,
+		InvitesWeek:        st.InvitesWeek,
+		DailyInviteQuota:   st.DailyInviteQuota,
+		WeeklyInviteQuota:  st.WeeklyInviteQuota,
+		RemainingPages:     st.RemainingPages,
+		RemainingInvites:   st.RemainingInvites,
+	}
+	if c, err := s.Vault.Get(st.Owner, st.Credential); err == nil {
+		a.Email = c.Email
+		a.HasTOTP = c.TOTPSecret != ""
+	}
+	return a
+}
+
+// AccountRequest cria ou atualiza uma conta. Credenciais vazias mantêm as do
+// cofre; orçamentos omitidos mantêm o valor atual (ou o padrão).
+type AccountRequest struct {
+	Email         string `json:"email"`
+	Password      string `json:"password"`
+	TOTPSecret    string `json:"totp_secret"`
+	DailyPages    *int   `json:"daily_pages"`
+	DailyInvites  *int   `json:"daily_invites"`  // 0 = a cota da configuração
+	WeeklyInvites *int   `json:"weekly_invites"` // 0 = a cota da configuração
+	CooldownHours *int   `json:"cooldown_hours"`
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	list := s.Accounts.List(owner(r), time.Now())
+	out := List[Account]{Items: make([]Account, 0, len(list)), Total: len(list)}
+	for _, st := range list {
+		out.Items = append(out.Items, s.accountView(st))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) handleAccount(w http.ResponseWriter, r *http.Request) {
+	user, alias := owner(r), r.PathValue("alias")
+	switch r.Method {
+	case http.MethodGet:
+		st, err := s.Accounts.Status(user, alias, time.Now())
+		if err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.accountView(st))
+	case http.MethodPut:
+		s.putAccount(w, r, user, alias)
+	case http.MethodDelete:
+		if err := s.Accounts.Delete(user, alias); err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		_ = s.Vault.Delete(user, alias)
+		if st := s.Settings.Get(user); st.Account == alias {
+			st.Account = ""
+			_ = s.Settings.Put(user, st)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (s *Server) putAccount(w http.ResponseWriter, r *http.Request, user, alias string) {
+	var in AccountRequest
+	if !decode(w, r, &in) {
+		return
+	}
+	in.Email = strings.TrimSpace(in.Email)
+	in.TOTPSecret = strings.TrimSpace(in.TOTPSecret)
+
+	cur, err := s.Accounts.Get(user, alias)
+	exists := err == nil
+	if !exists {
+		cur = accounts.Account{
+			Alias:         alias,
+			Owner:         user,
+			Credential:    alias,
+			SessionDir:    s.sessionDir(user, alias),
+			DailyPages:    accounts.DefaultDailyPages,
+			CooldownHours: accounts.DefaultCooldownHours,
+		}
+	}
+	cred, credErr := s.Vault.Get(user, cur.Credential)
+
+	var v validator
+	v.check(validAlias(alias), "alias", "use só letras, números, '.', '-', '_' ou '@' (até 64)")
+	if in.Email != "" || in.Password != "" {
+		v.check(strings.Contains(in.Email, "@"), "email", "email inválido")
+		v.check(in.Password != "", "password", "obrigatório junto com o email")
+	} else {
+		v.check(credErr == nil, "email", "informe email e senha da conta")
+	}
+	if in.TOTPSecret != "" {
+		_, err := totp.DecodeSecret(in.TOTPSecret)
+		v.check(err == nil, "totp_secret", "segredo TOTP deve ser base32")
+	}
+	if in.DailyPages != nil {
+		v.check(*in.DailyPages >= 1 && *in.DailyPages <= 1000, "daily_pages", "deve estar entre 1 e 1000")
+		cur.DailyPages = *in.DailyPages
+	}
+	if in.DailyInvites != nil {
+		v.check(*in.DailyInvites >= 0 && *in.DailyInvites <= 200, "daily_invites", "deve estar entre 0 e 200 (0 = a cota da configuração)")
+		cur.DailyInvites = *in.DailyInvites
+	}
+	if in.WeeklyInvites != nil {
+		v.check(*in.WeeklyInvites >= 0 && *in.WeeklyInvites <= 1000, "weekly_invites", "deve estar entre 0 e 1000 (0 = a cota da configuração)")
+		cur.WeeklyInvites = *in.WeeklyInvites
+	}
+	if in.CooldownHours != nil {
+		v.check(*in.CooldownHours >= 1 && *in.CooldownHours <= 720, "cooldown_hours", "deve estar entre 1 e 720")
+		cur.CooldownHours = *in.CooldownHours
+	}
+	if !v.valid() {
+		writeValidation(w, v.errs)
+		return
+	}
+
+	if in.Email != "" || in.TOTPSecret != "" {
+		if in.Email != "" {
+			cred.Email, cred.Password = in.Email, in.Password
+		}
+		if in.TOTPSecret != "" {
+			cred.TOTPSecret = in.TOTPSecret
+		}
+		if err := s.Vault.Put(user, cur.Credential, cred); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+	}
+	if err := s.Accounts.Put(cur); err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	if s.AccountsChanged != nil {
+		s.AccountsChanged()
+	}
+
+	st, err := s.Accounts.Status(user, alias, time.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	status := http.StatusOK
+	if !exists {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, s.accountView(st))
+}
@@ -0,0 +1,256 @@
+// Package api é a API REST versionada (/api/v1) usada pela UI e por
+// integrações: jobs, resultados, perfis, exports, contas, buscas salvas,
+// histórico, supressão e prévia de notas de convites, notificações e
+// configurações.
+// Erros sempre saem como {"error": {"code", "message", "details"}}.
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/auth"
+	"CrawlerLinkedin/internal/classify"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/notify"
+	"CrawlerLinkedin/internal/searches"
+	"CrawlerLinkedin/internal/settings"
+	"CrawlerLinkedin/internal/vault"
+)
+
+const Prefix = "/api/v1"
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Códigos de erro estáveis; a mensagem é para humanos e pode mudar.
+const (
+	CodeInvalidJSON      = "invalid_json"
+	CodeValidation       = "validation_failed"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeConflict         = "conflict"
+	CodeInternal         = "internal_error"
+)
+
+// Server liga a API aos stores do servidor.
+type Server struct {
+	Jobs     *jobs.Store
+	Accounts *accounts.Registry
+	Vault    *vault.Vault
+	Settings *settings.Store
+	Searches *searches.Store
+	// Classify preenche senioridade e área de CSVs antigos e diz quais
+	// rótulos os filtros aceitam; nil = dicionários embutidos.
+	Classify *classify.Rules
+
+	Notifications *notify.Store
+	// TestNotification enfileira um ping para o destino (notify.Notifier.Test).
+	TestNotification func(owner, id string) (notify.Delivery, error)
+	// EmailEnabled libera destinos do tipo email (SMTP configurado).
+	EmailEnabled bool
+
+	// UserDir é a pasta de dados do usuário (jobs e sessões do Chromium).
+	UserDir func(owner string) string
+	// Require protege as rotas; normalmente auth.Authenticator.RequireWith(h, Fail).
+	Require func(http.Handler) http.Handler
+	// JobCreated e AccountsChanged avisam o servidor (fila/agendador).
+	JobCreated      func(j jobs.Job)
+	AccountsChanged func()
+	// CancelJob tira o job da fila ou interrompe a execução; false = já terminou.
+	CancelJob func(id string) bool
+}
+
+// Routes registra a API em mux. O documento OpenAPI é público.
+func (s *Server) Routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+Prefix+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPISpec)
+	})
+
+	api := http.NewServeMux()
+	api.HandleFunc(Prefix+"/jobs", s.handleJobs)
+	api.HandleFunc(Prefix+"/jobs/{id}", s.handleJob)
+	api.HandleFunc(Prefix+"/jobs/{id}/results", s.handleJobResults)
+	api.HandleFunc(Prefix+"/jobs/{id}/quality", s.handleJobQuality)
+	api.HandleFunc(Prefix+"/jobs/{id}/cancel", s.handleJobCancel)
+	api.HandleFunc(Prefix+"/profiles", s.handleProfiles)
+	api.HandleFunc(Prefix+"/exports", s.handleExports)
+	api.HandleFunc(Prefix+"/exports/{id}", s.handleExport)
+	api.HandleFunc(Prefix+"/accounts", s.handleAccounts)
+	api.HandleFunc(Prefix+"/accounts/{alias}", s.handleAccount)
+	api.HandleFunc(Prefix+"/searches", s.handleSearches)
+	api.HandleFunc(Prefix+"/searches/{id}", s.handleSearch)
+	api.HandleFunc(Prefix+"/searches/{id}/run", s.handleSearchRun)
+	api.HandleFunc(Prefix+"/notifications", s.handleNotifications)
+	api.HandleFunc(Prefix+"/notifications/{id}", s.handleNotification)
+	api.HandleFunc(Prefix+"/notifications/{id}/deliveries", s.handleNotificationDeliveries)
+	api.HandleFunc(Prefix+"/notifications/{id}/test", s.handleNotificationTest)
+	api.HandleFunc(Prefix+"/invites", s.handleInvites)
+	api.HandleFunc(Prefix+"/invites/export", s.handleInvitesExport)
+	api.HandleFunc(Prefix+"/invites/suppression", s.handleSuppression)
+	api.HandleFunc(Prefix+"/invites/preview", s.handleNotePreview)
+	api.HandleFunc(Prefix+"/settings", s.handleSettings)
+	api.HandleFunc(Prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "rota não encontrada")
+	})
+
+	var h http.Handler = api
+	if s.Require != nil {
+		h = s.Require(h)
+	}
+	mux.Handle(Prefix+"/", h)
+}
+
+// Fail é o auth.FailFunc da API: 401/403 em JSON.
+func Fail(w http.ResponseWriter, r *http.Request, status int, msg string) {
+	code := CodeUnauthorized
+	if status == http.StatusForbidden {
+		code = CodeForbidden
+	}
+	writeError(w, status, code, msg)
+}
+
+// =============== Respostas ===============
+
+// Error é o corpo de toda resposta de erro.
+type Error struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// FieldError aponta o campo inválido de uma requisição.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// List é o envelope das coleções.
+type List[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+// Page é o envelope das coleções paginadas; Total conta os itens que passaram
+// nos filtros, antes do corte em Offset/Limit.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	writeJSON(w, status, map[string]Error{"error": {Code: code, Message: msg}})
+}
+
+func writeValidation(w http.ResponseWriter, errs []FieldError) {
+	writeJSON(w, http.StatusBadRequest, map[string]Error{"error": {
+		Code:    CodeValidation,
+		Message: "requisição inválida",
+		Details: errs,
+	}})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, CodeMethodNotAllowed, "método não permitido")
+}
+
+// decode lê o corpo JSON em v, recusando campos desconhecidos. Em caso de
+// erro já respondeu e devolve false.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(io.LimitReader(r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		msg := "JSON inválido"
+		if errors.Is(err, io.EOF) {
+			msg = "corpo vazio"
+		} else if strings.HasPrefix(err.Error(), "json: unknown field") {
+			msg = "campo desconhecido: " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+		}
+		writeError(w, http.StatusBadRequest, CodeInvalidJSON, msg)
+		return false
+	}
+	return true
+}
+
+// =============== Validação ===============
+
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) check(ok bool, field, msg string) {
+	if !ok {
+		v.errs = append(v.errs, FieldError{Field: field, Message: msg})
+	}
+}
+
+func (v *validator) valid() bool { return len(v.errs) == 0 }
+
+// intParam lê um inteiro da query string dentro de [lo, hi]; ausente = def.
+func (v *validator) intParam(r *http.Request, name string, def, lo, hi int) int {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < lo || n > hi {
+		v.check(false, name, "deve ser inteiro entre "+strconv.Itoa(lo)+" e "+strconv.Itoa(hi))
+		return def
+	}
+	return n
+}
+
+// labelsParam lê uma lista de rótulos separados por vírgula, todos de known.
+func (v *validator) labelsParam(r *http.Request, name string, known []string) string {
+	raw := r.URL.Query().Get(name)
+	for _, l := range strings.Split(raw, ",") {
+		if l = strings.TrimSpace(l); l != "" && !slices.Contains(known, l) {
+			v.check(false, name, "use "+strings.Join(known, ", ")+" (vírgula separa vários)")
+			return ""
+		}
+	}
+	return raw
+}
+
+// validAlias restringe aliases de conta a caracteres seguros para nome de pasta.
+func validAlias(a string) bool {
+	if a == "" || len(a) > 64 {
+		return false
+	}
+	for _, r := range a {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+		default:
+			return false
+		}
+	}
+	return a != "." && a != ".."
+}
+
+func owner(r *http.Request) string {
+	return auth.FromContext(r.Context()).Username
+}
+
+func (s *Server) sessionDir(owner, alias string) string {
+	return filepath.Join(s.UserDir(owner), "sessions", alias)
+}
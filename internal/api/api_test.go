@@ -0,0 +1,711 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/auth"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/notify"
+	"CrawlerLinkedin/internal/quality"
+	"CrawlerLinkedin/internal/results"
+	"CrawlerLinkedin/internal/searches"
+	"CrawlerLinkedin/internal/settings"
+	"CrawlerLinkedin/internal/vault"
+)
+
+type testEnv struct {
+	srv     *Server
+	mux     *http.ServeMux
+	created []jobs.Job
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	dir := t.TempDir()
+	js, err := jobs.Open(filepath.Join(dir, "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	v, err := vault.Open(filepath.Join(dir, "vault.json"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar, err := accounts.Open(filepath.Join(dir, "accounts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := settings.Open(filepath.Join(dir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := searches.Open(filepath.Join(dir, "searches.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := notify.Open(filepath.Join(dir, "notifications.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := &testEnv{mux: http.NewServeMux()}
+	env.srv = &Server{
+		Jobs:             js,
+		Accounts:         ar,
+		Vault:            v,
+		Settings:         st,
+		Searches:         ss,
+		Notifications:    ns,
+		TestNotification: notify.New(ns, nil, "").Test,
+		UserDir:          func(owner string) string { return filepath.Join(dir, "users", owner) },
+		// a sessão vem do contexto montado em do()
+		Require:    func(h http.Handler) http.Handler { return h },
+		JobCreated: func(j jobs.Job) { env.created = append(env.created, j) },
+	}
+	// como no servidor: convites restantes pelo histórico do usuário
+	ar.UseLedger(ledger.Quota{Daily: 20, Weekly: 100}, func(owner string) (*ledger.Checker, error) {
+		return ledger.Load(filepath.Join(env.srv.UserDir(owner), LedgerFile), "")
+	})
+	env.srv.CancelJob = func(id string) bool {
+		_, err := js.Update(id, func(j *jobs.Job) { j.Status = jobs.StatusCancelled })
+		return err == nil
+	}
+	env.srv.Routes(env.mux)
+	return env
+}
+
+func (e *testEnv) do(t *testing.T, user, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if user != "" {
+		r = r.WithContext(auth.NewContext(r.Context(), &auth.Session{Username: user}))
+	}
+	w := httptest.NewRecorder()
+	e.mux.ServeHTTP(w, r)
+	return w
+}
+
+func decodeBody[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("resposta não é JSON: %v\n%s", err, w.Body.String())
+	}
+	return v
+}
+
+func wantError(t *testing.T, w *httptest.ResponseRecorder, status int, code string) Error {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d\n%s", w.Code, status, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := decodeBody[map[string]Error](t, w)
+	e := body["error"]
+	if e.Code != code {
+		t.Errorf("code = %q, want %q", e.Code, code)
+	}
+	if e.Message == "" {
+		t.Error("mensagem de erro vazia")
+	}
+	return e
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	env := newTestEnv(t)
+	w := env.do(t, "", http.MethodGet, "/api/v1/openapi.json", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+	spec := decodeBody[struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}](t, w)
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("openapi = %q", spec.OpenAPI)
+	}
+	for _, p := range []string{"/jobs", "/jobs/{id}", "/jobs/{id}/results", "/jobs/{id}/quality", "/profiles", "/exports", "/exports/{id}", "/accounts", "/accounts/{alias}", "/searches", "/searches/{id}", "/searches/{id}/run", "/notifications", "/notifications/{id}", "/notifications/{id}/deliveries", "/notifications/{id}/test", "/invites", "/invites/export", "/invites/suppression", "/invites/preview", "/settings"} {
+		if _, ok := spec.Paths[p]; !ok {
+			t.Errorf("caminho %s ausente do OpenAPI", p)
+		}
+	}
+}
+
+func TestUnknownRouteAndMethod(t *testing.T) {
+	env := newTestEnv(t)
+	wantError(t, env.do(t, "ana", http.MethodGet, "/api/v1/nada", ""), http.StatusNotFound, CodeNotFound)
+	w := env.do(t, "ana", http.MethodDelete, "/api/v1/jobs", "")
+	wantError(t, w, http.StatusMethodNotAllowed, CodeMethodNotAllowed)
+	if w.Header().Get("Allow") == "" {
+		t.Error("405 sem header Allow")
+	}
+}
+
+func TestFail(t *testing.T) {
+	w := httptest.NewRecorder()
+	Fail(w, httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil), http.StatusUnauthorized, "não autenticado")
+	wantError(t, w, http.StatusUnauthorized, CodeUnauthorized)
+
+	w = httptest.NewRecorder()
+	Fail(w, httptest.NewRequest(http.MethodPost, "/api/v1/jobs", nil), http.StatusForbidden, "token CSRF inválido")
+	wantError(t, w, http.StatusForbidden, CodeForbidden)
+}
+
+func TestCreateJobValidation(t *testing.T) {
+	env := newTestEnv(t)
+
+	wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", "{"), http.StatusBadRequest, CodeInvalidJSON)
+	wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"go","bogus":1}`), http.StatusBadRequest, CodeInvalidJSON)
+
+	e := wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":" ","max_pages":0,"account":"fantasma","invite_note":"Oi {{.Empresa}}"}`), http.StatusBadRequest, CodeValidation)
+	fields := map[string]bool{}
+	for _, d := range e.Details {
+		fields[d.Field] = true
+	}
+	for _, f := range []string{"query", "max_pages", "account", "invite_note"} {
+		if !fields[f] {
+			t.Errorf("esperava erro no campo %s, veio %+v", f, e.Details)
+		}
+	}
+	if len(env.created) != 0 {
+		t.Error("job inválido não deveria ser criado")
+	}
+}
+
+func TestCreateAndGetJob(t *testing.T) {
+	env := newTestEnv(t)
+
+	w := env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"golang","max_pages":3,"dry_run":true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d\n%s", w.Code, w.Body.String())
+	}
+	j := decodeBody[Job](t, w)
+	if j.ID == "" || j.Status != jobs.StatusQueued || j.MaxPages != 3 || !j.Headless || !j.DryRun {
+		t.Errorf("job criado inesperado: %+v", j)
+	}
+	if w.Header().Get("Location") != "/api/v1/jobs/"+j.ID {
+		t.Errorf("Location = %q", w.Header().Get("Location"))
+	}
+	if len(env.created) != 1 || env.created[0].ID != j.ID || !env.created[0].DryRun {
+		t.Error("JobCreated não foi chamado (ou sem dry_run)")
+	}
+	if strings.Contains(w.Body.String(), `"dir"`) || strings.Contains(w.Body.String(), `"owner"`) {
+		t.Error("resposta expõe campos internos")
+	}
+
+	if w := env.do(t, "ana", http.MethodGet, "/api/v1/jobs/"+j.ID, ""); w.Code != http.StatusOK {
+		t.Errorf("GET do dono: status %d", w.Code)
+	}
+	wantError(t, env.do(t, "bia", http.MethodGet, "/api/v1/jobs/"+j.ID, ""), http.StatusNotFound, CodeNotFound)
+
+	list := decodeBody[List[Job]](t, env.do(t, "ana", http.MethodGet, "/api/v1/jobs", ""))
+	if list.Total != 1 || len(list.Items) != 1 {
+		t.Errorf("lista = %+v", list)
+	}
+	list = decodeBody[List[Job]](t, env.do(t, "bia", http.MethodGet, "/api/v1/jobs", ""))
+	if list.Total != 0 || list.Items == nil {
+		t.Errorf("lista de outro usuário = %+v", list)
+	}
+}
+
+func TestCreateJobUsesSettings(t *testing.T) {
+	env := newTestEnv(t)
+	if w := env.do(t, "ana", http.MethodPut, "/api/v1/settings", `{"max_pages":5,"headless":false}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT settings: %d %s", w.Code, w.Body.String())
+	}
+	j := decodeBody[Job](t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"rust"}`))
+	if j.MaxPages != 5 || j.Headless {
+		t.Errorf("padrões não aplicados: %+v", j)
+	}
+}
+
+func TestSettingsValidation(t *testing.T) {
+	env := newTestEnv(t)
+	got := decodeBody[settings.Settings](t, env.do(t, "ana", http.MethodGet, "/api/v1/settings", ""))
+	if got != settings.Defaults {
+		t.Errorf("GET sem nada salvo = %+v", got)
+	}
+	e := wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/settings", `{"max_pages":500,"account":"x"}`), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 2 {
+		t.Errorf("details = %+v", e.Details)
+	}
+}
+
+// doneJob cria um job já concluído de user com o CSV dado.
+func (e *testEnv) doneJob(t *testing.T, user, csv string) Job {
+	t.Helper()
+	j := decodeBody[Job](t, e.do(t, user, http.MethodPost, "/api/v1/jobs", `{"query":"golang"}`))
+	full, _ := e.srv.Jobs.Get(j.ID)
+	if err := os.MkdirAll(full.Dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	csvPath := filepath.Join(full.Dir, "linkedin_golang.csv")
+	if err := os.WriteFile(csvPath, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.srv.Jobs.Update(j.ID, func(x *jobs.Job) { x.Status = jobs.StatusDone; x.CSVPath = csvPath; x.Profiles = 2 }); err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+const csvHeader = "\ufeffname,title,company,location,role,url,source_query,captured_at\n"
+
+func TestJobResultsAndExports(t *testing.T) {
+	env := newTestEnv(t)
+	j := env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Dev,Acme,São Paulo,,https://www.linkedin.com/in/ana,golang,2025-01-01T00:00:00Z\n"+
+		"Bruno Lima,SRE,Beta,Recife,,https://www.linkedin.com/in/bruno,golang,2025-01-01T00:00:00Z\n")
+
+	res := decodeBody[Page[results.Row]](t, env.do(t, "ana", http.MethodGet, "/api/v1/jobs/"+j.ID+"/results?limit=1", ""))
+	if res.Total != 2 || len(res.Items) != 1 || res.Items[0].Name != "Ana Souza" {
+		t.Errorf("results = %+v", res)
+	}
+	wantError(t, env.do(t, "ana", http.MethodGet, "/api/v1/jobs/"+j.ID+"/results?limit=abc", ""), http.StatusBadRequest, CodeValidation)
+
+	profs := decodeBody[Page[results.Row]](t, env.do(t, "ana", http.MethodGet, "/api/v1/profiles?offset=1", ""))
+	if profs.Total != 2 || len(profs.Items) != 1 || profs.Items[0].Name != "Bruno Lima" {
+		t.Errorf("profiles = %+v", profs)
+	}
+
+	exps := decodeBody[List[Export]](t, env.do(t, "ana", http.MethodGet, "/api/v1/exports", ""))
+	if exps.Total != 1 || exps.Items[0].DownloadURL != "/api/v1/exports/"+j.ID {
+		t.Errorf("exports = %+v", exps)
+	}
+	w := env.do(t, "ana", http.MethodGet, "/api/v1/exports/"+j.ID, "")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") || !strings.Contains(w.Body.String(), "Bruno Lima") {
+		t.Errorf("download: %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	wantError(t, env.do(t, "bia", http.MethodGet, "/api/v1/exports/"+j.ID, ""), http.StatusNotFound, CodeNotFound)
+}
+
+func TestProfilesSameProfileAcrossJobs(t *testing.T) {
+	env := newTestEnv(t)
+	env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Dev,Acme,São Paulo,,https://www.linkedin.com/in/ana,golang,2025-01-01T00:00:00Z\n"+
+		"Sem URL,Dev,Acme,Recife,,,golang,2025-01-01T00:00:00Z\n")
+	env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Tech Lead,Acme,São Paulo,,https://www.linkedin.com/in/Ana/?miniProfileUrn=x,golang,2025-02-01T00:00:00Z\n"+
+		"Sem URL,Dev,Acme,Recife,,,golang,2025-02-01T00:00:00Z\n")
+
+	profs := decodeBody[Page[results.Row]](t, env.do(t, "ana", http.MethodGet, "/api/v1/profiles", ""))
+	if profs.Total != 2 || profs.Items[0].Title != "Tech Lead" || profs.Items[1].Name != "Sem URL" {
+		t.Errorf("profiles = %+v", profs)
+	}
+}
+
+func TestCreateInviteJob(t *testing.T) {
+	env := newTestEnv(t)
+	src := env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Dev,Acme,São Paulo,,https://www.linkedin.com/in/ana,golang,2025-01-01T00:00:00Z\n"+
+		"Bruno Lima,SRE,Beta,Recife,,https://www.linkedin.com/in/bruno,golang,2025-01-01T00:00:00Z\n")
+
+	// a busca não aceita mais convidar sozinha
+	wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"go","send_invites":true}`), http.StatusBadRequest, CodeInvalidJSON)
+
+	body := `{"invite_from":"` + src.ID + `","invite_urls":["https://www.linkedin.com/in/bruno/","https://www.linkedin.com/in/bruno"],"invite_note":"Oi {{.FirstName}}"}`
+	w := env.do(t, "ana", http.MethodPost, "/api/v1/jobs", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d\n%s", w.Code, w.Body.String())
+	}
+	j := decodeBody[Job](t, w)
+	if j.InviteFrom != src.ID || len(j.InviteURLs) != 1 || j.MaxPages != 0 || j.Query != "" {
+		t.Errorf("job de convites = %+v", j)
+	}
+
+	e := wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs",
+		`{"invite_from":"`+src.ID+`","invite_urls":["https://www.linkedin.com/in/carla"],"query":"go"}`), http.StatusBadRequest, CodeValidation)
+	fields := map[string]bool{}
+	for _, d := range e.Details {
+		fields[d.Field] = true
+	}
+	if !fields["invite_urls"] || !fields["query"] {
+		t.Errorf("details = %+v", e.Details)
+	}
+	wantError(t, env.do(t, "bia", http.MethodPost, "/api/v1/jobs", body), http.StatusBadRequest, CodeValidation)
+	wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"go","invite_urls":["https://www.linkedin.com/in/ana"]}`), http.StatusBadRequest, CodeValidation)
+}
+
+func TestJobQuality(t *testing.T) {
+	env := newTestEnv(t)
+	j := env.doneJob(t, "ana", csvHeader)
+	wantError(t, env.do(t, "ana", http.MethodGet, "/api/v1/jobs/"+j.ID+"/quality", ""), http.StatusNotFound, CodeNotFound)
+
+	full, _ := env.srv.Jobs.Get(j.ID)
+	rep := quality.Build(quality.Tally{Pages: 2, EmptyPages: 1}, nil, quality.Thresholds{MaxEmptyPages: 0})
+	if err := quality.Write(full.Dir, rep); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.srv.Jobs.Update(j.ID, func(x *jobs.Job) {
+		x.QualityPath = filepath.Join(full.Dir, quality.FileName)
+		x.Degraded, x.QualityIssues = rep.Degraded, rep.Reasons
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := decodeBody[Job](t, env.do(t, "ana", http.MethodGet, "/api/v1/jobs/"+j.ID, ""))
+	if !got.Degraded || len(got.QualityIssues) != 1 || got.QualityURL != "/api/v1/jobs/"+j.ID+"/quality" {
+		t.Errorf("job = %+v", got)
+	}
+	w := env.do(t, "ana", http.MethodGet, got.QualityURL, "")
+	if r := decodeBody[quality.Report](t, w); w.Code != http.StatusOK || !r.Degraded || r.Pages != 2 || len(r.Checks) == 0 {
+		t.Errorf("quality: %d %+v", w.Code, r)
+	}
+	wantError(t, env.do(t, "bia", http.MethodGet, got.QualityURL, ""), http.StatusNotFound, CodeNotFound)
+}
+
+func TestNotePreview(t *testing.T) {
+	env := newTestEnv(t)
+	j := env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Dev,Acme,São Paulo,,https://www.linkedin.com/in/ana,golang,2025-01-01T00:00:00Z\n"+
+		"Maximiliano Lima,SRE,,Recife,,https://www.linkedin.com/in/max,golang,2025-01-01T00:00:00Z\n")
+
+	tmpl := `Oi {{.Name}}! ` + strings.Repeat("x", 280)
+	body, _ := json.Marshal(NotePreviewRequest{Template: tmpl, JobID: j.ID})
+	w := env.do(t, "ana", http.MethodPost, "/api/v1/invites/preview", string(body))
+	p := decodeBody[NotePreview](t, w)
+	if w.Code != http.StatusOK || len(p.Items) != 2 || p.MaxLen != 300 {
+		t.Fatalf("prévia: %d %+v", w.Code, p)
+	}
+	if !strings.HasPrefix(p.Items[0].Note, "Oi Ana Souza! x") || p.Items[0].Error != "" {
+		t.Errorf("Ana = %+v", p.Items[0])
+	}
+	if p.Items[1].Error == "" || p.Items[1].Length <= 300 {
+		t.Errorf("Maximiliano deveria passar do limite: %+v", p.Items[1])
+	}
+
+	// sem job: perfil de exemplo
+	p = decodeBody[NotePreview](t, env.do(t, "ana", http.MethodPost, "/api/v1/invites/preview", `{"template":"Oi {{.FirstName}}"}`))
+	if len(p.Items) != 1 || p.Items[0].Note != "Oi Ana" {
+		t.Errorf("exemplo = %+v", p)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/invites/preview", `{"template":"Oi {{.Empresa}}"}`), http.StatusBadRequest, CodeValidation)
+	wantError(t, env.do(t, "bia", http.MethodPost, "/api/v1/invites/preview", string(body)), http.StatusNotFound, CodeNotFound)
+}
+
+func TestInviteLedgerAndSuppression(t *testing.T) {
+	env := newTestEnv(t)
+	path := filepath.Join(env.srv.UserDir("ana"), LedgerFile)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, e := range []ledger.Entry{
+		{At: at, Account: "rec1", URL: "https://www.linkedin.com/in/ana", Status: "sent", Note: "Oi Ana"},
+		{At: at.Add(time.Hour), Account: "rec2", URL: "https://www.linkedin.com/in/bia", Status: "failed"},
+	} {
+		if err := ledger.Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page := decodeBody[Page[ledger.Entry]](t, env.do(t, "ana", http.MethodGet, "/api/v1/invites", ""))
+	if page.Total != 2 || page.Items[0].URL != "https://www.linkedin.com/in/bia" {
+		t.Errorf("histórico = %+v", page)
+	}
+	page = decodeBody[Page[ledger.Entry]](t, env.do(t, "ana", http.MethodGet, "/api/v1/invites?account=rec1", ""))
+	if page.Total != 1 || page.Items[0].Note != "Oi Ana" {
+		t.Errorf("filtro por conta = %+v", page)
+	}
+	if page := decodeBody[Page[ledger.Entry]](t, env.do(t, "bia", http.MethodGet, "/api/v1/invites", "")); page.Total != 0 {
+		t.Errorf("histórico de outro usuário vazou: %+v", page)
+	}
+	w := env.do(t, "ana", http.MethodGet, "/api/v1/invites/export?status=sent", "")
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") ||
+		!strings.Contains(w.Body.String(), "Oi Ana") || strings.Contains(w.Body.String(), "/in/bia") {
+		t.Errorf("export: %d\n%s", w.Code, w.Body.String())
+	}
+
+	sup := decodeBody[Suppression](t, env.do(t, "ana", http.MethodGet, "/api/v1/invites/suppression", ""))
+	if len(sup.URLs) != 0 || len(sup.Companies) != 0 {
+		t.Errorf("lista inicial = %+v", sup)
+	}
+	w = env.do(t, "ana", http.MethodPut, "/api/v1/invites/suppression", `{"text":"empresa: Acme\nhttps://www.linkedin.com/in/caio\n"}`)
+	sup = decodeBody[Suppression](t, w)
+	if w.Code != http.StatusOK || len(sup.URLs) != 1 || len(sup.Companies) != 1 || !strings.Contains(sup.Text, "empresa: Acme") {
+		t.Errorf("PUT = %d %+v", w.Code, sup)
+	}
+	if got, _ := ledger.ReadSuppression(filepath.Join(env.srv.UserDir("ana"), SuppressFile)); len(got.URLs) != 1 {
+		t.Errorf("arquivo = %+v", got)
+	}
+	e := wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/invites/suppression", `{"text":"acme"}`), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 1 || e.Details[0].Field != "text" {
+		t.Errorf("detalhes = %+v", e.Details)
+	}
+}
+
+func TestAccountsCRUD(t *testing.T) {
+	env := newTestEnv(t)
+
+	e := wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"daily_pages":0}`), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 2 { // sem credencial e daily_pages fora da faixa
+		t.Errorf("details = %+v", e.Details)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/accounts/conta%20ruim", `{"email":"a@b.c","password":"x"}`), http.StatusBadRequest, CodeValidation)
+	wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"email":"a@b.c","password":"x","totp_secret":"!!"}`), http.StatusBadRequest, CodeValidation)
+
+	w := env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"email":"ana@exemplo.com","password":"s3nha","totp_secret":"GEZDGNBVGY3TQOJQ","daily_pages":10}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("PUT novo: %d %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "s3nha") || strings.Contains(w.Body.String(), "GEZD") {
+		t.Error("resposta vaza segredo")
+	}
+	a := decodeBody[Account](t, w)
+	if a.Email != "ana@exemplo.com" || !a.HasTOTP || a.DailyPages != 10 || a.DailyInvites != 0 || a.WeeklyInvites != 0 || a.RemainingInvites != 20 {
+		t.Errorf("conta = %+v", a)
+	}
+
+	// só orçamento: mantém credencial e o resto
+	w = env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"daily_invites":5}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT existente: %d %s", w.Code, w.Body.String())
+	}
+	a = decodeBody[Account](t, w)
+	if a.DailyPages != 10 || a.DailyInvites != 5 || a.Email != "ana@exemplo.com" {
+		t.Errorf("atualização parcial = %+v", a)
+	}
+	if c, err := env.srv.Vault.Get("ana", "rec-1"); err != nil || c.Password != "s3nha" {
+		t.Errorf("credencial no cofre: %+v, %v", c, err)
+	}
+
+	// o que resta sai do histórico de convites (o do crawler): o menor entre
+	// dia e semana; o aviso do LinkedIn zera
+	now := time.Now()
+	ledgerPath := filepath.Join(env.srv.UserDir("ana"), LedgerFile)
+	for i := range 8 {
+		e := ledger.Entry{At: now.AddDate(0, 0, -2), Account: "rec-1", URL: fmt.Sprintf("https://www.linkedin.com/in/p%d", i), Status: "sent"}
+		if err := ledger.Append(ledgerPath, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a = decodeBody[Account](t, env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"weekly_invites":10}`))
+	if a.InvitesWeek != 8 || a.InvitesToday != 0 || a.RemainingInvites != 2 {
+		t.Errorf("semana = %+v", a)
+	}
+	if err := ledger.Append(ledgerPath, ledger.Entry{At: now, Account: "rec-1", URL: "https://www.linkedin.com/in/x", Status: ledger.StatusWeeklyLimit}); err != nil {
+		t.Fatal(err)
+	}
+	a = decodeBody[Account](t, env.do(t, "ana", http.MethodGet, "/api/v1/accounts/rec-1", ""))
+	if !a.InvitesPaused || a.RemainingInvites != 0 {
+		t.Errorf("pausada = %+v", a)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPut, "/api/v1/accounts/rec-1", `{"weekly_invites":-1}`), http.StatusBadRequest, CodeValidation)
+
+	list := decodeBody[List[Account]](t, env.do(t, "ana", http.MethodGet, "/api/v1/accounts", ""))
+	if list.Total != 1 {
+		t.Errorf("lista = %+v", list)
+	}
+	wantError(t, env.do(t, "bia", http.MethodGet, "/api/v1/accounts/rec-1", ""), http.StatusNotFound, CodeNotFound)
+
+	if w := env.do(t, "ana", http.MethodDelete, "/api/v1/accounts/rec-1", ""); w.Code != http.StatusNoContent {
+		t.Errorf("DELETE: %d", w.Code)
+	}
+	if _, err := env.srv.Vault.Get("ana", "rec-1"); err == nil {
+		t.Error("credencial deveria sair do cofre junto com a conta")
+	}
+	wantError(t, env.do(t, "ana", http.MethodDelete, "/api/v1/accounts/rec-1", ""), http.StatusNotFound, CodeNotFound)
+}
+
+func TestJobResultsBrowsing(t *testing.T) {
+	env := newTestEnv(t)
+	j := env.doneJob(t, "ana", csvHeader+
+		"Ana Souza,Engenheira de Software,Acme,São Paulo,,https://www.linkedin.com/in/ana,golang,2025-01-01T00:00:00Z\n"+
+		"Bruno Lima,SRE,Beta,Recife,,https://www.linkedin.com/in/bruno,golang,2025-01-02T00:00:00Z\n"+
+		"Carla Dias,Software Engineer,Acme Labs,Sao Paulo,,https://www.linkedin.com/in/carla,golang,2025-01-03T00:00:00Z\n"+
+		"Davi Reis,Tech Lead,Gama,Curitiba,Golang e Kubernetes,https://www.linkedin.com/in/davi,golang,2025-01-04T00:00:00Z\n")
+	base := "/api/v1/jobs/" + j.ID + "/results"
+
+	names := func(path string) ([]string, Page[results.Row]) {
+		t.Helper()
+		w := env.do(t, "ana", http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: %d %s", path, w.Code, w.Body.String())
+		}
+		p := decodeBody[Page[results.Row]](t, w)
+		var out []string
+		for _, r := range p.Items {
+			out = append(out, r.Name)
+		}
+		return out, p
+	}
+	cases := []struct {
+		query string
+		want  string
+		total int
+	}{
+		{"?company=acme", "Ana Souza,Carla Dias", 2},
+		{"?location=sao%20paulo", "Ana Souza,Carla Dias", 2}, // sem acento
+		{"?title=software", "Ana Souza,Carla Dias", 2},
+		{"?q=kubernetes", "Davi Reis", 1}, // busca livre olha o cargo/resumo
+		{"?q=BRUNO", "Bruno Lima", 1},
+		{"?sort=-captured_at&limit=2", "Davi Reis,Carla Dias", 4},
+		{"?sort=company&offset=1&limit=2", "Carla Dias,Bruno Lima", 4},
+		{"?company=acme&title=engineer", "Carla Dias", 1},
+		{"?offset=10", "", 4},
+		{"?seniority=lead", "Davi Reis", 1}, // CSV sem as colunas: classificado na leitura
+		{"?function=engineering&company=acme", "Ana Souza,Carla Dias", 2},
+		{"?seniority=senior,lead", "Davi Reis", 1},
+	}
+	for _, c := range cases {
+		got, p := names(base + c.query)
+		if strings.Join(got, ",") != c.want || p.Total != c.total {
+			t.Errorf("%s: got %v (total %d), want %s (total %d)", c.query, got, p.Total, c.want, c.total)
+		}
+	}
+	_, p := names(base + "?offset=2&limit=1")
+	if p.Offset != 2 || p.Limit != 1 {
+		t.Errorf("offset/limit ecoados = %d/%d", p.Offset, p.Limit)
+	}
+
+	e := wantError(t, env.do(t, "ana", http.MethodGet, base+"?sort=url&limit=0&seniority=chefe", ""), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 3 {
+		t.Errorf("details = %+v", e.Details)
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	env := newTestEnv(t)
+	j := decodeBody[Job](t, env.do(t, "ana", http.MethodPost, "/api/v1/jobs", `{"query":"golang"}`))
+	path := "/api/v1/jobs/" + j.ID + "/cancel"
+
+	wantError(t, env.do(t, "bia", http.MethodPost, path, ""), http.StatusNotFound, CodeNotFound)
+	wantError(t, env.do(t, "ana", http.MethodGet, path, ""), http.StatusMethodNotAllowed, CodeMethodNotAllowed)
+
+	w := env.do(t, "ana", http.MethodPost, path, "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("cancel: %d %s", w.Code, w.Body.String())
+	}
+	if got := decodeBody[Job](t, w); got.Status != jobs.StatusCancelled {
+		t.Errorf("status = %s", got.Status)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPost, path, ""), http.StatusConflict, CodeConflict)
+}
+
+func TestSavedSearches(t *testing.T) {
+	env := newTestEnv(t)
+
+	e := wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/searches",
+		`{"name":"","query":"golang","cron":"61 * * * *","missed":"always","facets":{"geo_urns":["sp"]}}`),
+		http.StatusBadRequest, CodeValidation)
+	fields := map[string]bool{}
+	for _, d := range e.Details {
+		fields[d.Field] = true
+	}
+	for _, f := range []string{"name", "cron", "missed", "facets.geo_urns"} {
+		if !fields[f] {
+			t.Errorf("faltou erro em %s: %+v", f, e.Details)
+		}
+	}
+	e = wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/searches", `{"name":"x","query":"go","cron":"0 0 30 2 *"}`), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 1 || e.Details[0].Field != "cron" {
+		t.Errorf("30/fev: %+v", e.Details)
+	}
+
+	w := env.do(t, "ana", http.MethodPost, "/api/v1/searches",
+		`{"name":"Devs SP","query":"golang","cron":"0 9 * * 1","jitter_minutes":10,"facets":{"geo_urns":["105871508"],"first_current_company":false}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST: %d %s", w.Code, w.Body.String())
+	}
+	sr := decodeBody[SavedSearch](t, w)
+	if !sr.Enabled || sr.Missed != searches.MissedSkip || sr.MaxPages != settings.Defaults.MaxPages {
+		t.Errorf("padrões: %+v", sr)
+	}
+	if sr.NextRun.IsZero() || sr.NextRun.Weekday() != time.Monday {
+		t.Errorf("next_run = %v", sr.NextRun)
+	}
+	path := "/api/v1/searches/" + sr.ID
+
+	wantError(t, env.do(t, "bia", http.MethodGet, path, ""), http.StatusNotFound, CodeNotFound)
+	if l := decodeBody[List[SavedSearch]](t, env.do(t, "bia", http.MethodGet, "/api/v1/searches", "")); l.Total != 0 {
+		t.Errorf("bia vê %d buscas", l.Total)
+	}
+
+	// desativar zera o próximo horário; os outros campos ficam
+	sr = decodeBody[SavedSearch](t, env.do(t, "ana", http.MethodPut, path, `{"enabled":false}`))
+	if sr.Enabled || !sr.NextRun.IsZero() || sr.Cron != "0 9 * * 1" || sr.Query != "golang" {
+		t.Errorf("após desativar: %+v", sr)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPut, path, `{"cron":"toda segunda"}`), http.StatusBadRequest, CodeValidation)
+
+	w = env.do(t, "ana", http.MethodPost, path+"/run", "")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("run: %d %s", w.Code, w.Body.String())
+	}
+	j := decodeBody[Job](t, w)
+	if j.SearchID != sr.ID || j.Facets == nil || j.Facets.GeoURNs[0] != "105871508" || len(env.created) != 1 {
+		t.Errorf("job da busca: %+v", j)
+	}
+	sr = decodeBody[SavedSearch](t, env.do(t, "ana", http.MethodGet, path, ""))
+	if len(sr.Runs) != 1 || sr.Runs[0].JobID != j.ID || sr.Runs[0].Trigger != searches.TriggerManual || sr.Runs[0].Status != jobs.StatusQueued {
+		t.Errorf("runs = %+v", sr.Runs)
+	}
+
+	if w := env.do(t, "ana", http.MethodDelete, path, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE: %d", w.Code)
+	}
+	wantError(t, env.do(t, "ana", http.MethodPost, path+"/run", ""), http.StatusNotFound, CodeNotFound)
+}
+
+func TestNotifications(t *testing.T) {
+	env := newTestEnv(t)
+
+	e := wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/notifications",
+		`{"type":"webhook","url":"ftp://x","events":["job.completed","job.exploded"]}`), http.StatusBadRequest, CodeValidation)
+	if len(e.Details) != 2 || e.Details[0].Field != "url" || e.Details[1].Field != "events" {
+		t.Errorf("detalhes: %+v", e.Details)
+	}
+	e = wantError(t, env.do(t, "ana", http.MethodPost, "/api/v1/notifications",
+		`{"type":"email","email":"ana@exemplo.com","events":["job.failed"]}`), http.StatusBadRequest, CodeValidation)
+	if e.Details[0].Field != "type" {
+		t.Errorf("email sem SMTP: %+v", e.Details)
+	}
+
+	w := env.do(t, "ana", http.MethodPost, "/api/v1/notifications",
+		`{"type":"webhook","url":"https://hooks.exemplo.com/golinkedin","events":["job.completed","profiles.new"]}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("POST: %d %s", w.Code, w.Body.String())
+	}
+	n := decodeBody[Notification](t, w)
+	if len(n.Secret) != 64 || !n.Enabled {
+		t.Errorf("criado: %+v", n)
+	}
+	secret, path := n.Secret, "/api/v1/notifications/"+n.ID
+
+	// o segredo não volta nas leituras
+	if n := decodeBody[Notification](t, env.do(t, "ana", http.MethodGet, path, "")); n.Secret != "" {
+		t.Error("GET expôs o segredo")
+	}
+	wantError(t, env.do(t, "bia", http.MethodGet, path, ""), http.StatusNotFound, CodeNotFound)
+
+	wantError(t, env.do(t, "ana", http.MethodPut, path, `{"type":"email"}`), http.StatusBadRequest, CodeValidation)
+	n = decodeBody[Notification](t, env.do(t, "ana", http.MethodPut, path, `{"enabled":false,"rotate_secret":true}`))
+	if n.Enabled || n.Secret == "" || n.Secret == secret || len(n.Events) != 2 {
+		t.Errorf("após PUT: %+v", n)
+	}
+
+	w = env.do(t, "ana", http.MethodPost, path+"/test", "")
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("test: %d %s", w.Code, w.Body.String())
+	}
+	log := decodeBody[List[notify.Delivery]](t, env.do(t, "ana", http.MethodGet, path+"/deliveries", ""))
+	if log.Total != 1 || log.Items[0].Event != notify.EventPing || log.Items[0].Status != notify.StatusPending {
+		t.Errorf("log: %+v", log)
+	}
+	wantError(t, env.do(t, "bia", http.MethodPost, path+"/test", ""), http.StatusNotFound, CodeNotFound)
+
+	if w := env.do(t, "ana", http.MethodDelete, path, ""); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE: %d", w.Code)
+	}
+	wantError(t, env.do(t, "ana", http.MethodGet, path+"/deliveries", ""), http.StatusNotFound, CodeNotFound)
+}
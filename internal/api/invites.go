@@ -0,0 +1,186 @@
+package api
+
+import (
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/notes"
+	"CrawlerLinkedin/internal/results"
+)
+
+// Arquivos do histórico de convites e da lista de não contatar, na pasta do
+// usuário (os jobs recebem os mesmos caminhos).
+const (
+	LedgerFile   = "invites.jsonl"
+	SuppressFile = "suppress.txt"
+)
+
+// quantos perfis do job entram na prévia da nota
+const previewProfiles = 5
+
+// NotePreviewRequest pede a prévia de um modelo de nota, com os primeiros
+// perfis de um job ou, sem job, com o perfil de exemplo.
+type NotePreviewRequest struct {
+	Template string `json:"template"`
+	JobID    string `json:"job_id"`
+}
+
+// NotePreview é a nota montada para cada perfil da prévia.
+type NotePreview struct {
+	MaxLen int             `json:"max_len"`
+	Items  []notes.Preview `json:"items"`
+}
+
+func (s *Server) handleNotePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	var in NotePreviewRequest
+	if !decode(w, r, &in) {
+		return
+	}
+	tmpl, err := notes.Parse(strings.TrimSpace(in.Template))
+	if err != nil {
+		writeValidation(w, []FieldError{{Field: "template", Message: err.Error()}})
+		return
+	}
+	fields := []notes.Fields{notes.Sample}
+	if in.JobID != "" {
+		j, ok := s.Jobs.Get(in.JobID)
+		if !ok || j.Owner != owner(r) {
+			writeError(w, http.StatusNotFound, CodeNotFound, "job não encontrado")
+			return
+		}
+		if j.CSVPath != "" {
+			rows, err := results.ReadCSV(j.CSVPath, previewProfiles)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, CodeInternal, "lendo resultados: "+err.Error())
+				return
+			}
+			if len(rows) > 0 {
+				fields = fields[:0]
+				for _, row := range rows {
+					fields = append(fields, notes.FromProfile(row.Name, row.Title, row.Company, row.Location))
+				}
+			}
+		}
+	}
+	writeJSON(w, http.StatusOK, NotePreview{MaxLen: notes.MaxLen, Items: tmpl.PreviewAll(fields)})
+}
+
+// =============== Histórico e supressão ===============
+
+// handleInvites pagina o histórico de convites do usuário, mais recente
+// primeiro, com filtros por conta e resultado.
+func (s *Server) handleInvites(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	var v validator
+	limit := v.intParam(r, "limit", 100, 1, 1000)
+	offset := v.intParam(r, "offset", 0, 0, 1<<30)
+	if !v.valid() {
+		writeValidation(w, v.errs)
+		return
+	}
+	entries, ok := s.readLedger(w, r)
+	if !ok {
+		return
+	}
+	slices.Reverse(entries)
+	end := min(offset+limit, len(entries))
+	page := Page[ledger.Entry]{Items: []ledger.Entry{}, Total: len(entries), Offset: offset, Limit: limit}
+	if offset < len(entries) {
+		page.Items = entries[offset:end]
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+// handleInvitesExport baixa o histórico (mesmos filtros) em CSV.
+func (s *Server) handleInvitesExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	entries, ok := s.readLedger(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=convites.csv")
+	_ = ledger.WriteCSV(w, entries)
+}
+
+// readLedger lê o histórico do usuário filtrado por ?account= e ?status=.
+func (s *Server) readLedger(w http.ResponseWriter, r *http.Request) ([]ledger.Entry, bool) {
+	all, err := ledger.Read(filepath.Join(s.UserDir(owner(r)), LedgerFile))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, "lendo histórico: "+err.Error())
+		return nil, false
+	}
+	account, status := r.URL.Query().Get("account"), r.URL.Query().Get("status")
+	out := []ledger.Entry{}
+	for _, e := range all {
+		if (account == "" || e.Account == account) && (status == "" || e.Status == status) {
+			out = append(out, e)
+		}
+	}
+	return out, true
+}
+
+// Suppression é a lista de não contatar: o texto como o usuário edita e o
+// que foi entendido dele.
+type Suppression struct {
+	Text      string   `json:"text"`
+	URLs      []string `json:"urls"`
+	Companies []string `json:"companies"`
+}
+
+func (s *Server) handleSuppression(w http.ResponseWriter, r *http.Request) {
+	path := filepath.Join(s.UserDir(owner(r)), SuppressFile)
+	switch r.Method {
+	case http.MethodGet:
+		sup, err := ledger.ReadSuppression(path)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, suppressionView(sup))
+	case http.MethodPut:
+		var in struct {
+			Text string `json:"text"`
+		}
+		if !decode(w, r, &in) {
+			return
+		}
+		sup, err := ledger.ParseSuppression(in.Text)
+		if err != nil {
+			var errs []FieldError
+			for _, line := range strings.Split(err.Error(), "\n") {
+				errs = append(errs, FieldError{Field: "text", Message: line})
+			}
+			writeValidation(w, errs)
+			return
+		}
+		if err := ledger.WriteSuppression(path, sup); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, suppressionView(sup))
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+func suppressionView(sup ledger.Suppression) Suppression {
+	return Suppression{
+		Text:      sup.Format(),
+		URLs:      append([]string{}, sup.URLs...),
+		Companies: append([]string{}, sup.Companies...),
+	}
+}
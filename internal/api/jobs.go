@@ -0,0 +1,479 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/classify"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/notes"
+	"CrawlerLinkedin/internal/quality"
+	"CrawlerLinkedin/internal/results"
+)
+
+const (
+	maxJobPages   = 100
+	maxInviteURLs = 200 // perfis por job de convites
+)
+
+// Job é a visão pública de jobs.Job (sem caminhos do servidor).
+type Job struct {
+	ID              string       `json:"id"`
+	Query           string       `json:"query"`
+	MaxPages        int          `json:"max_pages"`
+	Headless        bool         `json:"headless"`
+	InviteNote      string       `json:"invite_note,omitempty"`
+	InviteFrom      string       `json:"invite_from,omitempty"`
+	InviteURLs      []string     `json:"invite_urls,omitempty"`
+	DumpHTML        bool         `json:"dump_html"`
+	DryRun          bool         `json:"dry_run"`
+	Account         string       `json:"account,omitempty"`
+	Facets          *jobs.Facets `json:"facets,omitempty"`
+	SearchID        string       `json:"search_id,omitempty"`
+	Status          jobs.Status  `json:"status"`
+	Message         string       `json:"message,omitempty"`
+	AssignedAccount string       `json:"assigned_account,omitempty"`
+	Pages           int          `json:"pages"`
+	Profiles        int          `json:"profiles"`
+	Invites         int          `json:"invites"`
+	ExportURL       string       `json:"export_url,omitempty"`
+	Degraded        bool         `json:"degraded"`
+	QualityIssues   []string     `json:"quality_issues,omitempty"`
+	QualityURL      string       `json:"quality_url,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+	StartedAt       time.Time    `json:"started_at,omitzero"`
+	EndedAt         time.Time    `json:"ended_at,omitzero"`
+}
+
+func jobView(j jobs.Job) Job {
+	v := Job{
+		ID:              j.ID,
+		Query:           j.Query,
+		MaxPages:        j.MaxPages,
+		Headless:        j.Headless,
+		InviteNote:      j.InviteNote,
+		InviteFrom:      j.InviteFrom,
+		InviteURLs:      j.InviteURLs,
+		DumpHTML:        j.DumpHTML,
+		DryRun:          j.DryRun,
+		Account:         j.Account,
+		Facets:          j.Facets,
+		SearchID:        j.SearchID,
+		Status:          j.Status,
+		Message:         j.Message,
+		AssignedAccount: j.AssignedAccount,
+		Pages:           j.Pages,
+		Profiles:        j.Profiles,
+		Invites:         j.Invites,
+		Degraded:        j.Degraded,
+		QualityIssues:   j.QualityIssues,
+		CreatedAt:       j.CreatedAt,
+		StartedAt:       j.StartedAt,
+		EndedAt:         j.EndedAt,
+	}
+	if j.CSVPath != "" {
+		v.ExportURL = Prefix + "/exports/" + j.ID
+	}
+	if j.QualityPath != "" {
+		v.QualityURL = Prefix + "/jobs/" + j.ID + "/quality"
+	}
+	return v
+}
+
+// JobRequest cria um job; campos omitidos vêm das configurações do usuário.
+// Com InviteFrom o job não busca: convida os perfis InviteURLs, revisados
+// nos resultados daquele job.
+type JobRequest struct {
+	Query      string   `json:"query"`
+	MaxPages   *int     `json:"max_pages"`
+	Headless   *bool    `json:"headless"`
+	InviteFrom string   `json:"invite_from"`
+	InviteURLs []string `json:"invite_urls"`
+	InviteNote string   `json:"invite_note"` // modelo text/template; vazio = sem nota
+	DumpHTML   bool     `json:"dump_html"`
+	DryRun     bool     `json:"dry_run"` // simula: o crawler não clica em convites nem filtros
+	Account    *string  `json:"account"`
+	// Facets omitido = filtros padrão do crawler
+	Facets *jobs.Facets `json:"facets"`
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list := s.Jobs.List(owner(r))
+		out := List[Job]{Items: make([]Job, 0, len(list)), Total: len(list)}
+		for _, j := range list {
+			out.Items = append(out.Items, jobView(j))
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		s.createJob(w, r)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) createJob(w http.ResponseWriter, r *http.Request) {
+	user := owner(r)
+	var in JobRequest
+	if !decode(w, r, &in) {
+		return
+	}
+	def := s.Settings.Get(user)
+	j := jobs.Job{
+		Owner:      user,
+		Query:      strings.TrimSpace(in.Query),
+		MaxPages:   def.MaxPages,
+		Headless:   def.Headless,
+		InviteFrom: strings.TrimSpace(in.InviteFrom),
+		InviteURLs: in.InviteURLs,
+		InviteNote: strings.TrimSpace(in.InviteNote),
+		DumpHTML:   in.DumpHTML,
+		DryRun:     in.DryRun,
+		Account:    def.Account,
+		Facets:     in.Facets,
+	}
+	if in.MaxPages != nil {
+		j.MaxPages = *in.MaxPages
+	}
+	if j.IsInvite() {
+		j.MaxPages = 0 // não busca
+	}
+	if in.Headless != nil {
+		j.Headless = *in.Headless
+	}
+	if in.Account != nil {
+		j.Account = strings.TrimSpace(*in.Account)
+	}
+
+	var v validator
+	if j.IsInvite() {
+		s.checkInvites(&v, user, &j)
+	} else {
+		s.checkJob(&v, user, j)
+		v.check(len(j.InviteURLs) == 0, "invite_urls", "só em jobs de convites (com invite_from)")
+		v.check(j.InviteNote == "", "invite_note", "só em jobs de convites (com invite_from): uma busca não convida ninguém")
+	}
+	if !v.valid() {
+		writeValidation(w, v.errs)
+		return
+	}
+
+	created, err := s.enqueue(j)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	w.Header().Set("Location", Prefix+"/jobs/"+created.ID)
+	writeJSON(w, http.StatusCreated, jobView(created))
+}
+
+// checkJob valida os parâmetros comuns a jobs e buscas salvas.
+func (s *Server) checkJob(v *validator, user string, j jobs.Job) {
+	v.check(j.Query != "", "query", "obrigatório")
+	v.check(len(j.Query) <= 200, "query", "no máximo 200 caracteres")
+	v.check(j.MaxPages >= 1 && j.MaxPages <= maxJobPages, "max_pages", "deve estar entre 1 e 100")
+	if j.Account != "" {
+		_, err := s.Accounts.Get(user, j.Account)
+		v.check(err == nil, "account", "conta não existe")
+	}
+	if f := j.Facets; f != nil {
+		v.check(len(f.GeoURNs) <= 10, "facets.geo_urns", "no máximo 10 localidades")
+		numeric := true
+		for _, g := range f.GeoURNs {
+			if _, err := strconv.ParseUint(g, 10, 64); err != nil {
+				numeric = false
+			}
+		}
+		v.check(numeric, "facets.geo_urns", "IDs geoUrn são numéricos (ex.: 105871508)")
+	}
+}
+
+// checkInvites valida o job de convites: a lista tem de sair dos resultados
+// de um job do próprio usuário. Remove URLs repetidas de j.InviteURLs.
+func (s *Server) checkInvites(v *validator, user string, j *jobs.Job) {
+	v.check(j.Query == "" && j.Facets == nil && !j.DumpHTML, "query", "job de convites não busca: use só invite_from e invite_urls")
+	if j.Account != "" {
+		_, err := s.Accounts.Get(user, j.Account)
+		v.check(err == nil, "account", "conta não existe")
+	}
+	if j.InviteNote != "" {
+		if _, err := notes.Parse(j.InviteNote); err != nil {
+			v.check(false, "invite_note", err.Error())
+		}
+	}
+	src, ok := s.Jobs.Get(j.InviteFrom)
+	if !ok || src.Owner != user {
+		v.check(false, "invite_from", "job não existe")
+		return
+	}
+	if src.CSVPath == "" {
+		v.check(false, "invite_from", "job sem resultados")
+		return
+	}
+	rows, err := results.ReadCSV(src.CSVPath, 0)
+	if err != nil {
+		v.check(false, "invite_from", "resultados ilegíveis: "+err.Error())
+		return
+	}
+	captured := map[string]bool{}
+	for _, r := range rows {
+		captured[results.Key(r)] = true
+	}
+	seen := map[string]bool{}
+	urls, missing := []string{}, 0
+	for _, u := range j.InviteURLs {
+		k := results.Key(results.Row{URL: strings.TrimSpace(u)})
+		switch {
+		case seen[k]:
+		case !captured[k]:
+			missing++
+		default:
+			seen[k] = true
+			urls = append(urls, strings.TrimSpace(u))
+		}
+	}
+	j.InviteURLs = urls
+	v.check(missing == 0, "invite_urls", fmt.Sprintf("%d perfil(is) fora dos resultados do job %s", missing, src.ID))
+	v.check(len(urls) > 0, "invite_urls", "obrigatório: os perfis revisados a convidar")
+	v.check(len(urls) <= maxInviteURLs, "invite_urls", fmt.Sprintf("no máximo %d perfis", maxInviteURLs))
+}
+
+// enqueue cria o job (pasta dentro da do dono) e avisa o servidor.
+func (s *Server) enqueue(j jobs.Job) (jobs.Job, error) {
+	created, err := s.Jobs.Create(j, func(id string) string {
+		return filepath.Join(s.UserDir(j.Owner), id)
+	})
+	if err != nil {
+		return jobs.Job{}, err
+	}
+	if s.JobCreated != nil {
+		s.JobCreated(created)
+	}
+	return created, nil
+}
+
+// ownJob devolve o job do usuário; senão responde 404 (sem revelar que
+// existe para outro dono).
+func (s *Server) ownJob(w http.ResponseWriter, r *http.Request) (jobs.Job, bool) {
+	j, ok := s.Jobs.Get(r.PathValue("id"))
+	if !ok || j.Owner != owner(r) {
+		writeError(w, http.StatusNotFound, CodeNotFound, jobs.ErrNotFound.Error())
+		return jobs.Job{}, false
+	}
+	return j, true
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	if j, ok := s.ownJob(w, r); ok {
+		writeJSON(w, http.StatusOK, jobView(j))
+	}
+}
+
+// handleJobCancel pede o cancelamento. Um job rodando termina a página atual,
+// grava o parcial e fica "cancelled"; por isso a resposta é 202.
+func (s *Server) handleJobCancel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	j, ok := s.ownJob(w, r)
+	if !ok {
+		return
+	}
+	if j.Status.Finished() || s.CancelJob == nil || !s.CancelJob(j.ID) {
+		writeError(w, http.StatusConflict, CodeConflict, "job já terminou")
+		return
+	}
+	j, _ = s.Jobs.Get(j.ID)
+	writeJSON(w, http.StatusAccepted, jobView(j))
+}
+
+// handleJobQuality devolve o relatório de qualidade da execução.
+func (s *Server) handleJobQuality(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	j, ok := s.ownJob(w, r)
+	if !ok {
+		return
+	}
+	if j.QualityPath == "" {
+		writeError(w, http.StatusNotFound, CodeNotFound, "job sem relatório de qualidade")
+		return
+	}
+	rep, ok, err := quality.Read(j.Dir)
+	switch {
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, CodeInternal, "lendo relatório de qualidade: "+err.Error())
+	case !ok:
+		writeError(w, http.StatusNotFound, CodeNotFound, "job sem relatório de qualidade")
+	default:
+		writeJSON(w, http.StatusOK, rep)
+	}
+}
+
+// handleJobResults pagina os perfis do job com busca, filtros por coluna e
+// ordenação (?sort=company ou ?sort=-company para decrescente). Senioridade e
+// área filtram pelo rótulo exato (?seniority=senior,lead).
+func (s *Server) handleJobResults(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	qs := r.URL.Query()
+	var v validator
+	q := results.Query{
+		Q:        qs.Get("q"),
+		Company:  qs.Get("company"),
+		Location: qs.Get("location"),
+		Title:    qs.Get("title"),
+		Offset:   v.intParam(r, "offset", 0, 0, 1<<30),
+		Limit:    v.intParam(r, "limit", 50, 1, 1000),
+	}
+	rules := s.rules()
+	seniorities, functions := rules.Labels()
+	q.Seniority = v.labelsParam(r, "seniority", seniorities)
+	q.Function = v.labelsParam(r, "function", functions)
+	if sortBy := qs.Get("sort"); sortBy != "" {
+		q.Sort, q.Desc = strings.CutPrefix(sortBy, "-")
+		_, ok := results.SortFields[q.Sort]
+		v.check(ok, "sort", "use name, title, company, location ou captured_at (prefixo - para decrescente)")
+	}
+	if !v.valid() {
+		writeValidation(w, v.errs)
+		return
+	}
+	j, ok := s.ownJob(w, r)
+	if !ok {
+		return
+	}
+	out := Page[results.Row]{Items: []results.Row{}, Offset: q.Offset, Limit: q.Limit}
+	if j.CSVPath != "" {
+		rows, err := results.ReadCSV(j.CSVPath, 0)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, "lendo resultados: "+err.Error())
+			return
+		}
+		rules.Fill(rows)
+		out.Items, out.Total = results.Apply(rows, q)
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleProfiles junta os perfis de todos os jobs do usuário, um por perfil
+// (results.Key; a captura mais recente vence).
+func (s *Server) handleProfiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	var v validator
+	limit := v.intParam(r, "limit", 100, 1, 1000)
+	offset := v.intParam(r, "offset", 0, 0, 1<<30)
+	if !v.valid() {
+		writeValidation(w, v.errs)
+		return
+	}
+
+	seen := map[string]bool{}
+	all := []results.Row{}
+	for _, j := range s.Jobs.List(owner(r)) { // mais recente primeiro
+		if j.CSVPath == "" {
+			continue
+		}
+		rows, err := results.ReadCSV(j.CSVPath, 0)
+		if err != nil {
+			continue
+		}
+		s.rules().Fill(rows)
+		for _, row := range rows {
+			key := results.Key(row)
+			if key == "" {
+				key = row.Name + "|" + row.Company
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			all = append(all, row)
+		}
+	}
+	page, total := results.Apply(all, results.Query{Offset: offset, Limit: limit})
+	writeJSON(w, http.StatusOK, Page[results.Row]{Items: page, Total: total, Offset: offset, Limit: limit})
+}
+
+// rules são as regras de classificação do servidor.
+func (s *Server) rules() *classify.Rules {
+	if s.Classify == nil {
+		return classify.Default()
+	}
+	return s.Classify
+}
+
+// Export descreve o CSV de um job.
+type Export struct {
+	JobID       string    `json:"job_id"`
+	Query       string    `json:"query"`
+	File        string    `json:"file"`
+	Profiles    int       `json:"profiles"`
+	DownloadURL string    `json:"download_url"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (s *Server) handleExports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	out := List[Export]{Items: []Export{}}
+	for _, j := range s.Jobs.List(owner(r)) {
+		if j.CSVPath == "" {
+			continue
+		}
+		out.Items = append(out.Items, Export{
+			JobID:       j.ID,
+			Query:       j.Query,
+			File:        filepath.Base(j.CSVPath),
+			Profiles:    j.Profiles,
+			DownloadURL: Prefix + "/exports/" + j.ID,
+			CreatedAt:   j.EndedAt,
+		})
+	}
+	out.Total = len(out.Items)
+	writeJSON(w, http.StatusOK, out)
+}
+
+// handleExport baixa o CSV do job.
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	j, ok := s.ownJob(w, r)
+	if !ok {
+		return
+	}
+	if j.CSVPath == "" || !within(j.Dir, j.CSVPath) {
+		writeError(w, http.StatusNotFound, CodeNotFound, "job sem CSV")
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filepath.Base(j.CSVPath))
+	http.ServeFile(w, r, j.CSVPath)
+}
+
+// within diz se path está dentro de dir (sem escapar via "..").
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
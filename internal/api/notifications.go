@@ -0,0 +1,222 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/notify"
+)
+
+// Notification é um destino de notificações. O segredo do webhook só aparece
+// na criação e ao gerar um novo (rotate_secret).
+type Notification struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url,omitempty"`
+	Email     string    `json:"email,omitempty"`
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func notificationView(h notify.Hook, withSecret bool) Notification {
+	v := Notification{
+		ID:        h.ID,
+		Type:      h.Type,
+		URL:       h.URL,
+		Email:     h.Email,
+		Events:    h.Events,
+		Enabled:   h.Enabled,
+		CreatedAt: h.CreatedAt,
+	}
+	if withSecret {
+		v.Secret = h.Secret
+	}
+	return v
+}
+
+// NotificationRequest cria ou altera um destino; no PUT, campos omitidos
+// mantêm o valor atual e o tipo não muda.
+type NotificationRequest struct {
+	Type         *string  `json:"type"`
+	URL          *string  `json:"url"`
+	Email        *string  `json:"email"`
+	Events       []string `json:"events"`
+	Enabled      *bool    `json:"enabled"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+func (in NotificationRequest) apply(h *notify.Hook) {
+	if in.Type != nil {
+		h.Type = strings.TrimSpace(*in.Type)
+	}
+	if in.URL != nil {
+		h.URL = strings.TrimSpace(*in.URL)
+	}
+	if in.Email != nil {
+		h.Email = strings.TrimSpace(*in.Email)
+	}
+	if in.Events != nil {
+		h.Events = in.Events
+	}
+	if in.Enabled != nil {
+		h.Enabled = *in.Enabled
+	}
+}
+
+func (s *Server) checkNotification(h notify.Hook) []FieldError {
+	var v validator
+	switch h.Type {
+	case notify.TypeWebhook:
+		u, err := url.Parse(h.URL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "url", "URL http(s) obrigatória")
+	case notify.TypeEmail:
+		v.check(s.EmailEnabled, "type", "email não configurado no servidor (SMTP_ADDR)")
+		_, err := mail.ParseAddress(h.Email)
+		v.check(err == nil, "email", "endereço inválido")
+	default:
+		v.check(false, "type", "use webhook ou email")
+	}
+	v.check(len(h.Events) > 0, "events", "escolha ao menos um evento")
+	for _, e := range h.Events {
+		v.check(slices.Contains(notify.Events, e), "events", "evento desconhecido: "+e)
+	}
+	return v.errs
+}
+
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	user := owner(r)
+	switch r.Method {
+	case http.MethodGet:
+		hooks := s.Notifications.Hooks(user)
+		out := List[Notification]{Items: make([]Notification, 0, len(hooks)), Total: len(hooks)}
+		for _, h := range hooks {
+			out.Items = append(out.Items, notificationView(h, false))
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		var in NotificationRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		h := notify.Hook{Owner: user, Enabled: true}
+		in.apply(&h)
+		if errs := s.checkNotification(h); len(errs) > 0 {
+			writeValidation(w, errs)
+			return
+		}
+		if h.Type != notify.TypeWebhook {
+			h.URL = ""
+		} else {
+			h.Email = ""
+		}
+		created, err := s.Notifications.CreateHook(h)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		w.Header().Set("Location", Prefix+"/notifications/"+created.ID)
+		writeJSON(w, http.StatusCreated, notificationView(created, true))
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleNotification(w http.ResponseWriter, r *http.Request) {
+	user, id := owner(r), r.PathValue("id")
+	switch r.Method {
+	case http.MethodGet:
+		h, err := s.Notifications.Hook(user, id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, notificationView(h, false))
+	case http.MethodPut:
+		var in NotificationRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		cur, err := s.Notifications.Hook(user, id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		if in.Type != nil && *in.Type != cur.Type {
+			writeValidation(w, []FieldError{{Field: "type", Message: "o tipo não pode mudar; crie outro destino"}})
+			return
+		}
+		in.apply(&cur)
+		if errs := s.checkNotification(cur); len(errs) > 0 {
+			writeValidation(w, errs)
+			return
+		}
+		rotate := in.RotateSecret && cur.Type == notify.TypeWebhook
+		updated, err := s.Notifications.UpdateHook(user, id, func(h *notify.Hook) {
+			in.apply(h)
+			if rotate {
+				h.Secret = notify.NewSecret()
+			}
+		})
+		if errors.Is(err, notify.ErrNotFound) {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, notificationView(updated, rotate))
+	case http.MethodDelete:
+		if err := s.Notifications.DeleteHook(user, id); err != nil {
+			status, code := http.StatusInternalServerError, CodeInternal
+			if errors.Is(err, notify.ErrNotFound) {
+				status, code = http.StatusNotFound, CodeNotFound
+			}
+			writeError(w, status, code, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// handleNotificationDeliveries devolve o log de entregas do destino.
+func (s *Server) handleNotificationDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+	user, id := owner(r), r.PathValue("id")
+	if _, err := s.Notifications.Hook(user, id); err != nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+		return
+	}
+	list := s.Notifications.Deliveries(user, id)
+	writeJSON(w, http.StatusOK, List[notify.Delivery]{Items: list, Total: len(list)})
+}
+
+// handleNotificationTest enfileira um ping; o resultado aparece no log.
+func (s *Server) handleNotificationTest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	d, err := s.TestNotification(owner(r), r.PathValue("id"))
+	if errors.Is(err, notify.ErrNotFound) {
+		writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusAccepted, d)
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/cron"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/searches"
+)
+
+// SavedSearch é a busca salva com o resultado das últimas execuções.
+type SavedSearch struct {
+	ID            string       `json:"id"`
+	Name          string       `json:"name"`
+	Query         string       `json:"query"`
+	Facets        *jobs.Facets `json:"facets,omitempty"`
+	MaxPages      int          `json:"max_pages"`
+	Account       string       `json:"account,omitempty"`
+	Headless      bool         `json:"headless"`
+	DumpHTML      bool         `json:"dump_html"`
+	DryRun        bool         `json:"dry_run"`
+	Cron          string       `json:"cron"`
+	JitterMinutes int          `json:"jitter_minutes"`
+	Missed        string       `json:"missed"`
+	Enabled       bool         `json:"enabled"`
+	NextRun       time.Time    `json:"next_run,omitzero"`
+	Runs          []SearchRun  `json:"runs"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// SearchRun é uma execução com o estado atual do job que ela criou.
+type SearchRun struct {
+	searches.Run
+	Status    jobs.Status `json:"status,omitempty"`
+	Profiles  int         `json:"profiles"`
+	ExportURL string      `json:"export_url,omitempty"`
+}
+
+func (s *Server) searchView(sr searches.Search) SavedSearch {
+	v := SavedSearch{
+		ID:            sr.ID,
+		Name:          sr.Name,
+		Query:         sr.Query,
+		Facets:        sr.Facets,
+		MaxPages:      sr.MaxPages,
+		Account:       sr.Account,
+		Headless:      sr.Headless,
+		DumpHTML:      sr.DumpHTML,
+		DryRun:        sr.DryRun,
+		Cron:          sr.Cron,
+		JitterMinutes: sr.JitterMinutes,
+		Missed:        sr.Missed,
+		Enabled:       sr.Enabled,
+		NextRun:       sr.NextRun,
+		Runs:          make([]SearchRun, 0, len(sr.Runs)),
+		CreatedAt:     sr.CreatedAt,
+		UpdatedAt:     sr.UpdatedAt,
+	}
+	for _, run := range sr.Runs {
+		out := SearchRun{Run: run}
+		if j, ok := s.Jobs.Get(run.JobID); run.JobID != "" && ok {
+			jv := jobView(j)
+			out.Status, out.Profiles, out.ExportURL = jv.Status, jv.Profiles, jv.ExportURL
+		}
+		v.Runs = append(v.Runs, out)
+	}
+	return v
+}
+
+// SearchRequest cria ou altera uma busca salva; no PUT, campos omitidos
+// mantêm o valor atual. Na criação, os omitidos vêm das configurações.
+type SearchRequest struct {
+	Name          *string      `json:"name"`
+	Query         *string      `json:"query"`
+	Facets        *jobs.Facets `json:"facets"`
+	MaxPages      *int         `json:"max_pages"`
+	Account       *string      `json:"account"`
+	Headless      *bool        `json:"headless"`
+	DumpHTML      *bool        `json:"dump_html"`
+	DryRun        *bool        `json:"dry_run"`
+	Cron          *string      `json:"cron"`
+	JitterMinutes *int         `json:"jitter_minutes"`
+	Missed        *string      `json:"missed"`
+	Enabled       *bool        `json:"enabled"`
+}
+
+func (in SearchRequest) apply(sr *searches.Search) {
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = strings.TrimSpace(*src)
+		}
+	}
+	set(&sr.Name, in.Name)
+	set(&sr.Query, in.Query)
+	set(&sr.Account, in.Account)
+	set(&sr.Cron, in.Cron)
+	set(&sr.Missed, in.Missed)
+	if in.Facets != nil {
+		sr.Facets = in.Facets
+	}
+	if in.MaxPages != nil {
+		sr.MaxPages = *in.MaxPages
+	}
+	if in.Headless != nil {
+		sr.Headless = *in.Headless
+	}
+	if in.DumpHTML != nil {
+		sr.DumpHTML = *in.DumpHTML
+	}
+	if in.DryRun != nil {
+		sr.DryRun = *in.DryRun
+	}
+	if in.JitterMinutes != nil {
+		sr.JitterMinutes = *in.JitterMinutes
+	}
+	if in.Enabled != nil {
+		sr.Enabled = *in.Enabled
+	}
+}
+
+func (s *Server) checkSearch(user string, sr searches.Search) []FieldError {
+	var v validator
+	v.check(sr.Name != "", "name", "obrigatório")
+	v.check(len(sr.Name) <= 80, "name", "no máximo 80 caracteres")
+	s.checkJob(&v, user, sr.Job())
+	if sched, err := cron.Parse(sr.Cron); err != nil {
+		v.check(false, "cron", err.Error())
+	} else {
+		v.check(!sched.Next(time.Now()).IsZero(), "cron", "a expressão nunca dispara")
+	}
+	v.check(sr.JitterMinutes >= 0 && sr.JitterMinutes <= searches.MaxJitterMinutes, "jitter_minutes", "deve estar entre 0 e 120")
+	v.check(sr.Missed == searches.MissedSkip || sr.Missed == searches.MissedRunOnce, "missed", "use skip ou run_once")
+	return v.errs
+}
+
+func (s *Server) handleSearches(w http.ResponseWriter, r *http.Request) {
+	user := owner(r)
+	switch r.Method {
+	case http.MethodGet:
+		list := s.Searches.List(user)
+		out := List[SavedSearch]{Items: make([]SavedSearch, 0, len(list)), Total: len(list)}
+		for _, sr := range list {
+			out.Items = append(out.Items, s.searchView(sr))
+		}
+		writeJSON(w, http.StatusOK, out)
+	case http.MethodPost:
+		var in SearchRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		def := s.Settings.Get(user)
+		sr := searches.Search{
+			Owner:    user,
+			MaxPages: def.MaxPages,
+			Headless: def.Headless,
+			Account:  def.Account,
+			Missed:   searches.MissedSkip,
+			Enabled:  true,
+		}
+		in.apply(&sr)
+		if errs := s.checkSearch(user, sr); len(errs) > 0 {
+			writeValidation(w, errs)
+			return
+		}
+		created, err := s.Searches.Create(sr, time.Now())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		w.Header().Set("Location", Prefix+"/searches/"+created.ID)
+		writeJSON(w, http.StatusCreated, s.searchView(created))
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	user, id := owner(r), r.PathValue("id")
+	switch r.Method {
+	case http.MethodGet:
+		sr, err := s.Searches.Get(user, id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.searchView(sr))
+	case http.MethodPut:
+		var in SearchRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		cur, err := s.Searches.Get(user, id)
+		if err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		in.apply(&cur)
+		if errs := s.checkSearch(user, cur); len(errs) > 0 {
+			writeValidation(w, errs)
+			return
+		}
+		updated, err := s.Searches.Update(user, id, time.Now(), func(sr *searches.Search) { in.apply(sr) })
+		if errors.Is(err, searches.ErrNotFound) {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, s.searchView(updated))
+	case http.MethodDelete:
+		if err := s.Searches.Delete(user, id); err != nil {
+			writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// handleSearchRun dispara a busca agora, fora do horário.
+func (s *Server) handleSearchRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+	user, id := owner(r), r.PathValue("id")
+	sr, err := s.Searches.Get(user, id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, err.Error())
+		return
+	}
+	run := searches.Run{Trigger: searches.TriggerManual, At: time.Now()}
+	j, err := s.EnqueueSearch(sr)
+	if err != nil {
+		run.Error = err.Error()
+		_ = s.Searches.AddRun(user, id, run)
+		writeError(w, http.StatusConflict, CodeConflict, err.Error())
+		return
+	}
+	run.JobID = j.ID
+	_ = s.Searches.AddRun(user, id, run)
+	w.Header().Set("Location", Prefix+"/jobs/"+j.ID)
+	writeJSON(w, http.StatusCreated, jobView(j))
+}
+
+// EnqueueSearch é o searches.EnqueueFunc: põe a busca na fila como um job
+// comum. Falha se a conta escolhida não existe mais.
+func (s *Server) EnqueueSearch(sr searches.Search) (jobs.Job, error) {
+	if sr.Account != "" {
+		if _, err := s.Accounts.Get(sr.Owner, sr.Account); err != nil {
+			return jobs.Job{}, errors.New("conta " + sr.Account + " não existe")
+		}
+	}
+	return s.enqueue(sr.Job())
+}
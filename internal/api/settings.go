@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/settings"
+)
+
+// handleSettings lê e grava os padrões de novos jobs do usuário.
+func (s *Server) handleSettings(w http.ResponseWriter, r *http.Request) {
+	user := owner(r)
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.Settings.Get(user))
+	case http.MethodPut:
+		var in settings.Settings
+		if !decode(w, r, &in) {
+			return
+		}
+		in.Account = strings.TrimSpace(in.Account)
+		var v validator
+		v.check(in.MaxPages >= 1 && in.MaxPages <= maxJobPages, "max_pages", "deve estar entre 1 e 100")
+		if in.Account != "" {
+			_, err := s.Accounts.Get(user, in.Account)
+			v.check(!errors.Is(err, accounts.ErrNotFound), "account", "conta não existe")
+		}
+		if !v.valid() {
+			writeValidation(w, v.errs)
+			return
+		}
+		if err := s.Settings.Put(user, in); err != nil {
+			writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, in)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
@@ -0,0 +1,281 @@
+// Package auth protege a UI web: usuários locais (bcrypt), OIDC opcional,
+// sessões por cookie e proteção CSRF nos POSTs.
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// Config define de onde vêm os usuários da UI.
+type Config struct {
+	UsersFile string
+	OIDC      *OIDCConfig // nil = desabilitado
+}
+
+// Provedores de login. Usuários do SSO ganham o prefixo "oidc:" na
+// identidade, para um usuário local não se passar por um do SSO (nem o
+// contrário) só por ter o mesmo nome.
+const (
+	ProviderLocal = "local"
+	ProviderOIDC  = "oidc"
+)
+
+type Authenticator struct {
+	users    *UserDB
+	oidc     *oidcProvider
+	sessions *sessionStore
+}
+
+type ctxKey struct{}
+
+// New carrega os usuários locais e, se configurado, descobre o provedor OIDC.
+// Pelo menos um dos dois precisa estar disponível.
+func New(ctx context.Context, cfg Config) (*Authenticator, error) {
+	users, err := LoadUsers(cfg.UsersFile)
+	if err != nil {
+		return nil, err
+	}
+	a := &Authenticator{users: users, sessions: newSessionStore()}
+	if cfg.OIDC != nil && cfg.OIDC.Issuer != "" {
+		p, err := newOIDCProvider(ctx, *cfg.OIDC)
+		if err != nil {
+			return nil, err
+		}
+		a.oidc = p
+	}
+	if a.users.Len() == 0 && a.oidc == nil {
+		return nil, fmt.Errorf("nenhum usuário configurado: crie %s (veja 'hash-password') ou configure OIDC_ISSUER", cfg.UsersFile)
+	}
+	return a, nil
+}
+
+// Routes registra login/logout e o fluxo OIDC.
+func (a *Authenticator) Routes(mux *http.ServeMux) {
+	mux.HandleFunc("/login", a.handleLogin)
+	mux.HandleFunc("/logout", a.handleLogout)
+	mux.HandleFunc("/auth/oidc/login", a.handleOIDCLogin)
+	mux.HandleFunc("/auth/oidc/callback", a.handleOIDCCallback)
+}
+
+// FailFunc escreve a resposta de erro de autenticação (401) ou CSRF (403).
+type FailFunc func(w http.ResponseWriter, r *http.Request, status int, msg string)
+
+// Require exige sessão válida e, para métodos que alteram estado, o token CSRF
+// (header X-CSRF-Token ou campo csrf_token).
+func (a *Authenticator) Require(next http.Handler) http.Handler {
+	return a.RequireWith(next, func(w http.ResponseWriter, r *http.Request, status int, msg string) {
+		if status == http.StatusUnauthorized && r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html") {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		http.Error(w, msg, status)
+	})
+}
+
+// RequireWith é o Require com a resposta de erro escolhida pelo chamador
+// (a API responde em JSON).
+func (a *Authenticator) RequireWith(next http.Handler, fail FailFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sess := a.session(r)
+		if sess == nil {
+			fail(w, r, http.StatusUnauthorized, "não autenticado")
+			return
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			tok := r.Header.Get("X-CSRF-Token")
+			if tok == "" {
+				tok = r.PostFormValue("csrf_token")
+			}
+			if !tokensEqual(tok, sess.CSRF) {
+				fail(w, r, http.StatusForbidden, "token CSRF inválido")
+				return
+			}
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), sess)))
+	})
+}
+
+// NewContext devolve ctx carregando a sessão (usado por Require e em testes).
+func NewContext(ctx context.Context, sess *Session) context.Context {
+	return context.WithValue(ctx, ctxKey{}, sess)
+}
+
+// FromContext devolve a sessão colocada por Require.
+func FromContext(ctx context.Context) *Session {
+	sess, _ := ctx.Value(ctxKey{}).(*Session)
+	return sess
+}
+
+func (a *Authenticator) session(r *http.Request) *Session {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return nil
+	}
+	sess, ok := a.sessions.get(c.Value)
+	if !ok {
+		return nil
+	}
+	return sess
+}
+
+// DirName é o nome da pasta de dados do dono owner (Session.Username):
+// hex(sha256("provedor:usuário")), sem colisão entre nomes parecidos
+// ("ana silva", "ana_silva", "ana/silva"), entre provedores ou com "." e "..".
+func DirName(owner string) string {
+	key := owner
+	if !strings.HasPrefix(owner, ProviderOIDC+":") {
+		key = ProviderLocal + ":" + owner
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *Authenticator) startSession(w http.ResponseWriter, r *http.Request, provider, username, name string) {
+	if provider == ProviderOIDC {
+		username = ProviderOIDC + ":" + username
+	}
+	sess := a.sessions.create(provider, username, name)
+	setCookie(w, r, sessionCookie, sess.ID, int(sessionTTL.Seconds()))
+	slog.Info("login", "user", username)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// =================== Login local ===================
+
+func (a *Authenticator) handleLogin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.renderLogin(w, r, http.StatusOK, "")
+	case http.MethodPost:
+		c, err := r.Cookie(loginCSRFCookie)
+		if err != nil || !tokensEqual(r.PostFormValue("csrf_token"), c.Value) {
+			http.Error(w, "token CSRF inválido", http.StatusForbidden)
+			return
+		}
+		u, err := a.users.Authenticate(r.PostFormValue("username"), r.PostFormValue("password"))
+		if err != nil {
+			a.renderLogin(w, r, http.StatusUnauthorized, err.Error())
+			return
+		}
+		a.startSession(w, r, ProviderLocal, u.Username, u.Name)
+	default:
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *Authenticator) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	sess := a.session(r)
+	if sess != nil {
+		if !tokensEqual(r.PostFormValue("csrf_token"), sess.CSRF) {
+			http.Error(w, "token CSRF inválido", http.StatusForbidden)
+			return
+		}
+		a.sessions.delete(sess.ID)
+	}
+	setCookie(w, r, sessionCookie, "", -1)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
+
+func (a *Authenticator) renderLogin(w http.ResponseWriter, r *http.Request, status int, errMsg string) {
+	tok := randomToken()
+	setCookie(w, r, loginCSRFCookie, tok, 3600)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = loginTmpl.Execute(w, map[string]any{
+		"CSRF":     tok,
+		"Error":    errMsg,
+		"HasLocal": a.users.Len() > 0,
+		"HasOIDC":  a.oidc != nil,
+	})
+}
+
+// =================== OIDC ===================
+
+const oidcStateCookie = "golinkedin_oidc"
+
+func (a *Authenticator) handleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if a.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	state, nonce := randomToken(), randomToken()
+	setCookie(w, r, oidcStateCookie, state+"."+nonce, 600)
+	http.Redirect(w, r, a.oidc.oauth.AuthCodeURL(state, oidc.Nonce(nonce)), http.StatusFound)
+}
+
+func (a *Authenticator) handleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if a.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	c, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		http.Error(w, "estado OIDC ausente", http.StatusBadRequest)
+		return
+	}
+	setCookie(w, r, oidcStateCookie, "", -1)
+	state, nonce, _ := strings.Cut(c.Value, ".")
+	if !tokensEqual(r.URL.Query().Get("state"), state) {
+		http.Error(w, "estado OIDC inválido", http.StatusBadRequest)
+		return
+	}
+	if e := r.URL.Query().Get("error"); e != "" {
+		http.Error(w, "login OIDC recusado: "+e, http.StatusUnauthorized)
+		return
+	}
+	username, name, err := a.oidc.exchange(r.Context(), r.URL.Query().Get("code"), nonce)
+	if err != nil {
+		slog.Warn("login OIDC", "err", err)
+		http.Error(w, "falha no login OIDC", http.StatusUnauthorized)
+		return
+	}
+	a.startSession(w, r, ProviderOIDC, username, name)
+}
+
+var loginTmpl = template.Must(template.New("login").Parse(`<!doctype html>
+<html lang="pt-BR"><head>
+<meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1"/>
+<title>GoLinkedIn • Login</title>
+<link rel="icon" href="data:,">
+<script src="https://cdn.tailwindcss.com"></script>
+</head>
+<body class="bg-gray-50 text-gray-900">
+<div class="max-w-sm mx-auto px-4 py-16">
+  <h1 class="text-3xl font-bold text-center mb-8" style="color:hsl(200 98% 39%)">GoLinkedIn</h1>
+  <div class="bg-white border rounded-xl p-5 space-y-4">
+    {{if .Error}}<div class="text-sm text-red-700 bg-red-50 rounded-md px-3 py-2">{{.Error}}</div>{{end}}
+    {{if .HasLocal}}
+    <form method="post" action="/login" class="space-y-3">
+      <input type="hidden" name="csrf_token" value="{{.CSRF}}">
+      <label class="block">
+        <span class="text-sm">Usuário</span>
+        <input name="username" autocomplete="username" class="mt-1 w-full border rounded-md px-3 py-2">
+      </label>
+      <label class="block">
+        <span class="text-sm">Senha</span>
+        <input name="password" type="password" autocomplete="current-password" class="mt-1 w-full border rounded-md px-3 py-2">
+      </label>
+      <button class="w-full py-2 rounded-lg text-white font-medium" style="background:hsl(200 98% 39%)">Entrar</button>
+    </form>
+    {{end}}
+    {{if .HasOIDC}}
+    <a href="/auth/oidc/login" class="block text-center w-full py-2 rounded-lg border hover:bg-gray-100">Entrar com SSO</a>
+    {{end}}
+  </div>
+</div>
+</body></html>`))
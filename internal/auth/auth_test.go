@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeUsers(t *testing.T, users ...User) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "users.json")
+	b, _ := json.Marshal(usersFile{Users: users})
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newLocal(t *testing.T) *Authenticator {
+	t.Helper()
+	hash, err := HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := New(context.Background(), Config{UsersFile: writeUsers(t, User{Username: "Ana", Name: "Ana Souza", PasswordHash: hash})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func cookie(w *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+var reCSRF = regexp.MustCompile(`name="csrf_token" value="([^"]+)"`)
+
+func TestLocalLogin(t *testing.T) {
+	a := newLocal(t)
+	mux := http.NewServeMux()
+	a.Routes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/login", nil))
+	m := reCSRF.FindStringSubmatch(w.Body.String())
+	loginCookie := cookie(w, loginCSRFCookie)
+	if m == nil || loginCookie == nil || m[1] != loginCookie.Value {
+		t.Fatalf("formulário sem token CSRF: %v\n%s", loginCookie, w.Body.String())
+	}
+
+	post := func(csrf, user, pass string) *httptest.ResponseRecorder {
+		form := url.Values{"csrf_token": {csrf}, "username": {user}, "password": {pass}}
+		r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		r.AddCookie(loginCookie)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+	if w := post("outro", "ana", "secret"); w.Code != http.StatusForbidden {
+		t.Errorf("CSRF errado: status %d", w.Code)
+	}
+	if w := post(m[1], "ana", "errada"); w.Code != http.StatusUnauthorized || cookie(w, sessionCookie) != nil {
+		t.Errorf("senha errada: status %d", w.Code)
+	}
+	w = post(m[1], " ANA ", "secret")
+	c := cookie(w, sessionCookie)
+	if w.Code != http.StatusSeeOther || c == nil {
+		t.Fatalf("login: status %d, cookie %v", w.Code, c)
+	}
+	sess, ok := a.sessions.get(c.Value)
+	if !ok || sess.Username != "Ana" || sess.Provider != ProviderLocal {
+		t.Errorf("sessão = %+v", sess)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	a := newLocal(t)
+	sess := a.sessions.create(ProviderLocal, "Ana", "")
+	h := a.RequireWith(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(FromContext(r.Context()).Username))
+	}), func(w http.ResponseWriter, r *http.Request, status int, msg string) {
+		http.Error(w, msg, status)
+	})
+	do := func(method string, withCookie bool, header, form string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/x", strings.NewReader(form))
+		if form != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		if header != "" {
+			r.Header.Set("X-CSRF-Token", header)
+		}
+		if withCookie {
+			r.AddCookie(&http.Cookie{Name: sessionCookie, Value: sess.ID})
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do(http.MethodGet, false, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("sem sessão: status %d", w.Code)
+	}
+	if w := do(http.MethodGet, true, "", ""); w.Code != http.StatusOK || w.Body.String() != "Ana" {
+		t.Errorf("GET: %d %q", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodPost, true, "", ""); w.Code != http.StatusForbidden {
+		t.Errorf("POST sem CSRF: status %d", w.Code)
+	}
+	if w := do(http.MethodPost, true, "errado", ""); w.Code != http.StatusForbidden {
+		t.Errorf("POST com CSRF errado: status %d", w.Code)
+	}
+	if w := do(http.MethodPost, true, sess.CSRF, ""); w.Code != http.StatusOK {
+		t.Errorf("POST com header: status %d", w.Code)
+	}
+	if w := do(http.MethodPost, true, "", "csrf_token="+url.QueryEscape(sess.CSRF)); w.Code != http.StatusOK {
+		t.Errorf("POST com campo: status %d", w.Code)
+	}
+
+	// sessão vencida some
+	sess.ExpiresAt = time.Now().Add(-time.Second)
+	if w := do(http.MethodGet, true, "", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("sessão vencida: status %d", w.Code)
+	}
+	if _, ok := a.sessions.get(sess.ID); ok {
+		t.Error("sessão vencida continua no store")
+	}
+}
+
+func TestLoadUsersRejectsBadNames(t *testing.T) {
+	for _, name := range []string{".", "..", "oidc:ana", "ana\x00"} {
+		if _, err := LoadUsers(writeUsers(t, User{Username: name, PasswordHash: "x"})); err == nil {
+			t.Errorf("usuário %q aceito", name)
+		}
+	}
+	if _, err := LoadUsers(writeUsers(t, User{Username: "ana", PasswordHash: "x"}, User{Username: "Ana", PasswordHash: "y"})); err == nil {
+		t.Error("usuário repetido aceito")
+	}
+}
+
+func TestDirName(t *testing.T) {
+	seen := map[string]string{}
+	for _, owner := range []string{"ana silva", "ana_silva", "ana/silva", "ana", "oidc:ana", "..", "."} {
+		d := DirName(owner)
+		if len(d) != 64 || strings.ContainsAny(d, "./") {
+			t.Errorf("DirName(%q) = %q", owner, d)
+		}
+		if other, dup := seen[d]; dup {
+			t.Errorf("DirName(%q) = DirName(%q)", owner, other)
+		}
+		seen[d] = owner
+	}
+	if DirName("ana") != DirName("ana") {
+		t.Error("DirName não é estável")
+	}
+}
+
+// =============== OIDC ===============
+
+// fakeIssuer é um provedor OIDC mínimo: descoberta, JWKS e token endpoint
+// que assina um id_token com o nonce escolhido pelo teste.
+type fakeIssuer struct {
+	srv   *httptest.Server
+	key   *rsa.PrivateKey
+	nonce string // nonce que o id_token vai levar
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeIssuer{key: key}
+	mux := http.NewServeMux()
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                                f.srv.URL,
+			"authorization_endpoint":                f.srv.URL + "/auth",
+			"token_endpoint":                        f.srv.URL + "/token",
+			"jwks_uri":                              f.srv.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA", "alg": "RS256", "use": "sig", "kid": "k1",
+			"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().Unix()
+		writeJSON(w, map[string]any{
+			"access_token": "at", "token_type": "Bearer", "expires_in": 3600,
+			"id_token": f.sign(map[string]any{
+				"iss": f.srv.URL, "aud": "client", "sub": "1", "iat": now, "exp": now + 300,
+				"email": "ana@example.com", "email_verified": true, "name": "Ana Souza", "nonce": f.nonce,
+			}),
+		})
+	})
+	return f
+}
+
+func (f *fakeIssuer) sign(claims map[string]any) string {
+	h, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "k1", "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	input := b64(h) + "." + b64(c)
+	sum := sha256.Sum256([]byte(input))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, sum[:])
+	return input + "." + b64(sig)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func TestOIDCCallback(t *testing.T) {
+	f := newFakeIssuer(t)
+	a, err := New(context.Background(), Config{
+		UsersFile: filepath.Join(t.TempDir(), "users.json"),
+		OIDC:      &OIDCConfig{Issuer: f.srv.URL, ClientID: "client", ClientSecret: "s", RedirectURL: "http://app/auth/oidc/callback"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	a.Routes(mux)
+
+	// começa o login: cookie com estado e nonce, redireciona ao provedor
+	start := func() (state, nonce string, c *http.Cookie) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+		c = cookie(w, oidcStateCookie)
+		loc, err := url.Parse(w.Header().Get("Location"))
+		if w.Code != http.StatusFound || c == nil || err != nil {
+			t.Fatalf("login OIDC: %d %v %v", w.Code, c, err)
+		}
+		state, nonce, _ = strings.Cut(c.Value, ".")
+		if loc.Query().Get("state") != state || loc.Query().Get("nonce") != nonce {
+			t.Fatalf("redirect %s não leva estado/nonce do cookie %q", loc, c.Value)
+		}
+		return state, nonce, c
+	}
+	callback := func(state string, c *http.Cookie) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?code=x&state="+url.QueryEscape(state), nil)
+		if c != nil {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+
+	state, nonce, c := start()
+	if w := callback(state, nil); w.Code != http.StatusBadRequest {
+		t.Errorf("sem cookie de estado: status %d", w.Code)
+	}
+	if w := callback("outro", c); w.Code != http.StatusBadRequest {
+		t.Errorf("estado errado: status %d", w.Code)
+	}
+	f.nonce = "outro"
+	if w := callback(state, c); w.Code != http.StatusUnauthorized || cookie(w, sessionCookie) != nil {
+		t.Errorf("nonce errado: status %d", w.Code)
+	}
+
+	state, nonce, c = start()
+	f.nonce = nonce
+	w := callback(state, c)
+	sc := cookie(w, sessionCookie)
+	if w.Code != http.StatusSeeOther || sc == nil {
+		t.Fatalf("callback: status %d\n%s", w.Code, w.Body.String())
+	}
+	sess, ok := a.sessions.get(sc.Value)
+	if !ok || sess.Username != "oidc:ana@example.com" || sess.Provider != ProviderOIDC || sess.Name != "Ana Souza" {
+		t.Errorf("sessão = %+v", sess)
+	}
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCConfig configura o login opcional num provedor OpenID Connect.
+type OIDCConfig struct {
+	Issuer        string
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	UsernameClaim string // default: "email"
+}
+
+type oidcProvider struct {
+	verifier *oidc.IDTokenVerifier
+	oauth    oauth2.Config
+	claim    string
+}
+
+func newOIDCProvider(ctx context.Context, cfg OIDCConfig) (*oidcProvider, error) {
+	if cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return nil, errors.New("oidc: client id e redirect url são obrigatórios")
+	}
+	p, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: descoberta do issuer %q: %w", cfg.Issuer, err)
+	}
+	claim := cfg.UsernameClaim
+	if claim == "" {
+		claim = "email"
+	}
+	return &oidcProvider{
+		verifier: p.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     p.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+		claim: claim,
+	}, nil
+}
+
+// exchange troca o code pelo id_token e devolve (username, nome).
+func (p *oidcProvider) exchange(ctx context.Context, code, nonce string) (string, string, error) {
+	tok, err := p.oauth.Exchange(ctx, code)
+	if err != nil {
+		return "", "", fmt.Errorf("oidc: troca do code: %w", err)
+	}
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok {
+		return "", "", errors.New("oidc: resposta sem id_token")
+	}
+	idt, err := p.verifier.Verify(ctx, raw)
+	if err != nil {
+		return "", "", fmt.Errorf("oidc: id_token inválido: %w", err)
+	}
+	if idt.Nonce != nonce {
+		return "", "", errors.New("oidc: nonce não confere")
+	}
+	var claims map[string]any
+	if err := idt.Claims(&claims); err != nil {
+		return "", "", err
+	}
+	if v, ok := claims["email_verified"].(bool); ok && !v && p.claim == "email" {
+		return "", "", errors.New("oidc: email não verificado")
+	}
+	username, _ := claims[p.claim].(string)
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return "", "", fmt.Errorf("oidc: claim %q ausente", p.claim)
+	}
+	name, _ := claims["name"].(string)
+	return username, name, nil
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	sessionCookie   = "golinkedin_session"
+	loginCSRFCookie = "golinkedin_login_csrf"
+	sessionTTL      = 12 * time.Hour
+)
+
+// Session é uma sessão autenticada da UI. CSRF é exigido em todo POST.
+// Username é a identidade dona dos dados (jobs, contas, pasta do usuário):
+// o nome para usuários locais, "oidc:<claim>" para o SSO.
+type Session struct {
+	ID        string
+	Username  string
+	Provider  string // ProviderLocal ou ProviderOIDC
+	Name      string
+	CSRF      string
+	ExpiresAt time.Time
+}
+
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func newSessionStore() *sessionStore {
+	return &sessionStore{sessions: map[string]*Session{}}
+}
+
+func (s *sessionStore) create(provider, username, name string) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.After(sess.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+	sess := &Session{
+		ID:        randomToken(),
+		Username:  username,
+		Provider:  provider,
+		Name:      name,
+		CSRF:      randomToken(),
+		ExpiresAt: now.Add(sessionTTL),
+	}
+	s.sessions[sess.ID] = sess
+	return sess
+}
+
+func (s *sessionStore) get(id string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(sess.ExpiresAt) {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	return sess, true
+}
+
+func (s *sessionStore) delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+}
+
+func setCookie(w http.ResponseWriter, r *http.Request, name, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func tokensEqual(a, b string) bool {
+	return a != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func randomToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+var ErrInvalidCredentials = errors.New("usuário ou senha inválidos")
+
+// User é uma conta local da UI (não confundir com a conta do LinkedIn).
+type User struct {
+	Username     string `json:"username"`
+	Name         string `json:"name,omitempty"`
+	PasswordHash string `json:"password_hash"`
+}
+
+type usersFile struct {
+	Users []User `json:"users"`
+}
+
+// UserDB é o arquivo local de usuários com senhas em bcrypt.
+type UserDB struct {
+	users map[string]User
+}
+
+// LoadUsers lê o arquivo de usuários. Arquivo ausente resulta num banco
+// vazio; nome inválido ou repetido é erro.
+func LoadUsers(path string) (*UserDB, error) {
+	var f usersFile
+	if err := jsonfile.Load(path, &f); err != nil {
+		return nil, err
+	}
+	db := &UserDB{users: map[string]User{}}
+	for _, u := range f.Users {
+		name := strings.TrimSpace(u.Username)
+		if name == "" || u.PasswordHash == "" {
+			continue
+		}
+		if err := ValidUsername(name); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, dup := db.users[strings.ToLower(name)]; dup {
+			return nil, fmt.Errorf("%s: usuário %q repetido", path, name)
+		}
+		u.Username = name
+		db.users[strings.ToLower(name)] = u
+	}
+	return db, nil
+}
+
+// ValidUsername recusa nomes de usuário local que confundem pastas ou
+// identidades: "." e "..", ":" (reservado ao prefixo do provedor) e
+// caracteres de controle.
+func ValidUsername(name string) error {
+	switch {
+	case name == "." || name == "..":
+		return fmt.Errorf("usuário %q inválido", name)
+	case strings.Contains(name, ":"):
+		return fmt.Errorf("usuário %q inválido: ':' é reservado", name)
+	case strings.ContainsFunc(name, unicode.IsControl):
+		return fmt.Errorf("usuário %q inválido: caractere de controle", name)
+	case len(name) > 128:
+		return fmt.Errorf("usuário %q inválido: mais de 128 bytes", name)
+	}
+	return nil
+}
+
+func (db *UserDB) Len() int { return len(db.users) }
+
+// Authenticate confere a senha contra o hash bcrypt do usuário.
+func (db *UserDB) Authenticate(username, password string) (User, error) {
+	u, ok := db.users[strings.ToLower(strings.TrimSpace(username))]
+	if !ok {
+		// compara mesmo assim para não vazar a existência do usuário pelo tempo de resposta
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
+		return User{}, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+var dummyHash = sync.OnceValue(func() []byte {
+	b, _ := bcrypt.GenerateFromPassword([]byte("dummy"), bcrypt.DefaultCost)
+	return b
+})
+
+// HashPassword gera o hash bcrypt para colocar no arquivo de usuários.
+func HashPassword(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
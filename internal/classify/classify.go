@@ -0,0 +1,257 @@
+// Package classify deriva senioridade e área de um perfil a partir do título
+// e do cargo, com dicionários de palavras-chave em português e inglês. Os
+// dicionários embutidos podem ser estendidos (ou trocados) por um arquivo YAML.
+package classify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+
+	"CrawlerLinkedin/internal/results"
+)
+
+// Senioridades dos dicionários embutidos, da menor para a maior.
+const (
+	Intern   = "intern"
+	Junior   = "junior"
+	Mid      = "mid"
+	Senior   = "senior"
+	Lead     = "lead"
+	Manager  = "manager"
+	Director = "director"
+	CLevel   = "c_level"
+)
+
+// Rule dá o rótulo Label ao texto que tem alguma das Keywords e nenhuma das
+// Not. Palavras-chave casam por palavra inteira, sem diferenciar maiúsculas
+// nem acentos ("sr" casa "Sr." mas não "Srta"; "back-end" casa "Back End").
+type Rule struct {
+	Label    string   `yaml:"label"`
+	Keywords []string `yaml:"keywords"`
+	Not      []string `yaml:"not,omitempty"`
+}
+
+// Rules são as regras de cada dimensão, em ordem: a primeira que casa vence.
+type Rules struct {
+	// Replace (só no arquivo) descarta os dicionários embutidos.
+	Replace   bool   `yaml:"replace,omitempty"`
+	Seniority []Rule `yaml:"seniority"`
+	Function  []Rule `yaml:"function"`
+}
+
+// Default devolve os dicionários embutidos. A ordem importa: cargos de
+// gestão antes de "senior" ("Senior Manager" é manager) e áreas específicas
+// antes de engenharia ("Data Engineer" é data, "Tech Recruiter" é hr).
+func Default() *Rules {
+	return &Rules{
+		Seniority: []Rule{
+			{Label: CLevel, Keywords: []string{
+				"ceo", "cto", "cfo", "coo", "cmo", "cio", "cpo", "chro", "ciso", "chief", "c level",
+				"presidente", "president", "vice presidente", "vice president", "vp",
+				"founder", "co founder", "cofounder", "fundador", "fundadora", "cofundador", "cofundadora", "co fundador", "co fundadora",
+				"socio", "socia", "partner", "owner", "proprietario", "proprietaria",
+			}, Not: []string{"business partner", "partner manager", "channel partner", "hrbp", "product owner", "process owner"}},
+			{Label: Director, Keywords: []string{
+				"diretor", "diretora", "director", "head", "superintendente", "superintendent",
+			}},
+			{Label: Manager, Keywords: []string{
+				"gerente", "manager", "gestor", "gestora", "gerencia",
+			}, Not: []string{
+				"product manager", "project manager", "program manager", "account manager",
+				"gerente de produto", "gerente de produtos", "gerente de projeto", "gerente de projetos", "gerente de contas",
+			}},
+			{Label: Lead, Keywords: []string{
+				"lead", "leader", "lider", "tech lead", "team lead", "coordenador", "coordenadora", "coordinator",
+				"supervisor", "supervisora", "principal", "staff",
+			}, Not: []string{"lead generation", "geracao de leads"}},
+			{Label: Intern, Keywords: []string{
+				"estagiario", "estagiaria", "estagio", "intern", "internship", "trainee", "aprendiz",
+			}},
+			{Label: Junior, Keywords: []string{
+				"junior", "jr", "entry level", "assistente", "assistant", "auxiliar",
+			}},
+			{Label: Senior, Keywords: []string{
+				"senior", "sr", "especialista", "expert",
+			}},
+			{Label: Mid, Keywords: []string{
+				"pleno", "pl", "mid", "mid level", "intermediate",
+			}},
+		},
+		Function: []Rule{
+			{Label: "hr", Keywords: []string{
+				"rh", "recursos humanos", "human resources", "hr", "hrbp", "people partner", "people & culture", "gente e gestao",
+				"talent", "talentos", "talent acquisition", "recruiter", "recruiting", "recrutador", "recrutadora",
+				"recrutamento", "selecao", "departamento pessoal",
+			}},
+			{Label: "data", Keywords: []string{
+				"data", "dados", "analytics", "bi", "business intelligence", "machine learning", "ml",
+				"inteligencia artificial", "ai", "ia", "cientista", "scientist", "estatistico", "statistician",
+			}},
+			{Label: "design", Keywords: []string{
+				"design", "designer", "ux", "ui", "ux ui", "ui ux", "product designer", "user experience",
+			}},
+			{Label: "product", Keywords: []string{
+				"product", "produto", "produtos", "product owner", "po", "product manager", "pm",
+			}},
+			{Label: "sales", Keywords: []string{
+				"vendas", "sales", "comercial", "vendedor", "vendedora", "account executive", "executivo de contas",
+				"executiva de contas", "account manager", "gerente de contas", "sdr", "bdr", "business development",
+				"desenvolvimento de negocios", "pre vendas", "pre sales", "inside sales", "key account",
+			}},
+			{Label: "marketing", Keywords: []string{
+				"marketing", "growth", "seo", "conteudo", "content", "brand", "branding", "marca",
+				"comunicacao", "communications", "social media", "midias sociais",
+			}},
+			{Label: "finance", Keywords: []string{
+				"financeiro", "financeira", "financas", "finance", "financial", "contabil", "contabilidade",
+				"contador", "contadora", "accounting", "accountant", "controller", "controladoria", "fiscal",
+				"tesouraria", "treasury", "fp&a", "tributario", "tax", "auditor", "auditoria", "audit", "cfo",
+			}},
+			{Label: "legal", Keywords: []string{
+				"juridico", "juridica", "legal", "advogado", "advogada", "lawyer", "attorney", "counsel",
+				"compliance", "paralegal",
+			}},
+			{Label: "support", Keywords: []string{
+				"suporte", "support", "customer success", "sucesso do cliente", "atendimento",
+				"customer service", "customer experience", "cx", "help desk", "service desk",
+			}},
+			{Label: "operations", Keywords: []string{
+				"operacoes", "operations", "ops", "logistica", "logistics", "supply chain", "suprimentos",
+				"compras", "procurement", "purchasing", "facilities", "coo",
+			}},
+			{Label: "engineering", Keywords: []string{
+				"engenheiro", "engenheira", "engineer", "engineering", "engenharia", "developer", "desenvolvedor",
+				"desenvolvedora", "dev", "software", "programador", "programadora", "devops", "sre", "backend",
+				"back end", "frontend", "front end", "fullstack", "full stack", "mobile", "android", "ios",
+				"qa", "quality assurance", "tester", "testes", "arquiteto", "arquiteta", "architect", "cto",
+				"infraestrutura", "infrastructure", "cloud", "seguranca da informacao", "security", "cybersecurity",
+				"ti", "information technology", "tecnologia", "technology", "sistemas", "tech lead",
+			}},
+		},
+	}
+}
+
+// Load lê o arquivo de regras em path e o põe na frente dos dicionários
+// embutidos (regras do arquivo vencem), ou no lugar deles com replace: true.
+// Vazio = só os embutidos.
+func Load(path string) (*Rules, error) {
+	def := Default()
+	if path == "" {
+		return def, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file Rules
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if file.Replace {
+		file.Replace = false
+		return &file, nil
+	}
+	return &Rules{
+		Seniority: append(file.Seniority, def.Seniority...),
+		Function:  append(file.Function, def.Function...),
+	}, nil
+}
+
+func (r *Rules) validate() error {
+	var errs []error
+	for _, dim := range []struct {
+		name  string
+		rules []Rule
+	}{{"seniority", r.Seniority}, {"function", r.Function}} {
+		for i, rule := range dim.rules {
+			switch {
+			case strings.TrimSpace(rule.Label) == "":
+				errs = append(errs, fmt.Errorf("%s[%d]: label vazio", dim.name, i))
+			case len(rule.Keywords) == 0:
+				errs = append(errs, fmt.Errorf("%s[%d] (%s): sem keywords", dim.name, i, rule.Label))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Classify devolve a senioridade e a área dos textos (título, cargo...);
+// vazio quando nenhuma regra casa.
+func (r *Rules) Classify(texts ...string) (seniority, function string) {
+	if r == nil {
+		return "", ""
+	}
+	text := words(strings.Join(texts, " | "))
+	return first(r.Seniority, text), first(r.Function, text)
+}
+
+// Fill classifica as linhas que ainda não têm senioridade nem área (CSVs
+// gravados antes da classificação).
+func (r *Rules) Fill(rows []results.Row) {
+	for i := range rows {
+		if rows[i].Seniority == "" && rows[i].Function == "" {
+			rows[i].Seniority, rows[i].Function = r.Classify(rows[i].Title, rows[i].Role)
+		}
+	}
+}
+
+// Labels lista os rótulos de cada dimensão, sem repetir, na ordem das regras.
+func (r *Rules) Labels() (seniority, function []string) {
+	uniq := func(rules []Rule) []string {
+		var out []string
+		seen := map[string]bool{}
+		for _, rule := range rules {
+			if !seen[rule.Label] {
+				seen[rule.Label] = true
+				out = append(out, rule.Label)
+			}
+		}
+		return out
+	}
+	if r == nil {
+		return nil, nil
+	}
+	return uniq(r.Seniority), uniq(r.Function)
+}
+
+func first(rules []Rule, text string) string {
+	for _, rule := range rules {
+		if matchAny(rule.Keywords, text) && !matchAny(rule.Not, text) {
+			return rule.Label
+		}
+	}
+	return ""
+}
+
+func matchAny(keywords []string, text string) bool {
+	for _, k := range keywords {
+		if k = words(k); k != "  " && strings.Contains(text, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// words normaliza para casar por palavra inteira: sem acento, minúsculas,
+// pontuação vira espaço e o texto fica entre espaços (" senior engineer ").
+func words(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '&' || r == '+' || r == '#' {
+			return r
+		}
+		return ' '
+	}, results.Fold(s))
+	return " " + strings.Join(strings.Fields(s), " ") + " "
+}
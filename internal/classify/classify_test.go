@@ -0,0 +1,99 @@
+package classify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CrawlerLinkedin/internal/results"
+)
+
+func TestClassify(t *testing.T) {
+	r := Default()
+	cases := []struct {
+		title, seniority, function string
+	}{
+		{"Engenheira de Software Sênior", Senior, "engineering"},
+		{"Sr. Backend Developer | Go, Kubernetes", Senior, "engineering"},
+		{"Desenvolvedora Front-End Pleno na Acme", Mid, "engineering"},
+		{"Estagiária de Recursos Humanos", Intern, "hr"},
+		{"Tech Recruiter", "", "hr"},
+		{"Senior Data Engineer", Senior, "data"},
+		{"Gerente de Engenharia", Manager, "engineering"},
+		{"Senior Engineering Manager", Manager, "engineering"},
+		{"Senior Product Manager", Senior, "product"},
+		{"Head de Vendas", Director, "sales"},
+		{"Diretora Financeira", Director, "finance"},
+		{"Co-founder & CTO", CLevel, "engineering"},
+		{"HR Business Partner", "", "hr"},
+		{"Product Owner", "", "product"},
+		{"Tech Lead | Java", Lead, "engineering"},
+		{"Coordenador de Marketing", Lead, "marketing"},
+		{"Analista Jr. de Suporte", Junior, "support"},
+		{"Advogada Trabalhista", "", "legal"},
+		{"Apaixonada por pessoas", "", ""},
+		{"Srta. Ana", "", ""},
+	}
+	for _, c := range cases {
+		s, f := r.Classify(c.title)
+		if s != c.seniority || f != c.function {
+			t.Errorf("Classify(%q) = %q, %q; quero %q, %q", c.title, s, f, c.seniority, c.function)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "regras.yaml")
+	os.WriteFile(path, []byte(`
+seniority:
+  - label: senior
+    keywords: [analista III]
+function:
+  - label: research
+    keywords: [pesquisador, pesquisadora, UX research]
+`), 0o600)
+	r, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, f := r.Classify("Analista III de Sistemas"); s != Senior || f != "engineering" {
+		t.Errorf("regra do arquivo = %q, %q", s, f)
+	}
+	if _, f := r.Classify("UX Research Lead"); f != "research" {
+		t.Errorf("regra do arquivo vence a embutida: %q", f)
+	}
+	if s, _ := r.Classify("Diretor"); s != Director {
+		t.Errorf("embutidas continuam valendo: %q", s)
+	}
+
+	os.WriteFile(path, []byte("replace: true\nfunction:\n  - label: x\n    keywords: [foo]\n"), 0o600)
+	if r, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if s, f := r.Classify("Senior foo"); s != "" || f != "x" {
+		t.Errorf("replace = %q, %q", s, f)
+	}
+
+	for _, bad := range []string{
+		"function:\n  - label: x\n",
+		"function:\n  - keywords: [a]\n",
+		"funcao: []\n",
+	} {
+		os.WriteFile(path, []byte(bad), 0o600)
+		if _, err := Load(path); err == nil {
+			t.Errorf("Load(%q) deveria falhar", bad)
+		}
+	}
+}
+
+func TestFill(t *testing.T) {
+	rows := []results.Row{
+		{Title: "Engenheiro de Dados"},
+		{Title: "Engenheiro de Dados", Seniority: "lead"},
+	}
+	Default().Fill(rows)
+	if rows[0].Function != "data" || rows[1].Function != "" {
+		t.Errorf("Fill = %+v", rows)
+	}
+}
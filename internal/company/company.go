@@ -0,0 +1,218 @@
+// Package company acha a empresa atual de um perfil a partir do que o card
+// da busca mostra: headline ("Cargo at Empresa", "Cargo na Empresa",
+// "Cargo | Empresa"), resumo ("Atual: Cargo na Empresa") e subtítulo, e
+// confere com o filtro "Empresa atual" aplicado na busca. Cada resultado diz
+// de onde veio e quanto dá para confiar nele.
+//
+// Não há etapa de enriquecimento (dados de empresa de fora do LinkedIn) no
+// projeto; quando houver, entra em Extract como mais uma fonte.
+package company
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"CrawlerLinkedin/internal/results"
+)
+
+// Fontes da empresa, de onde Result.Company veio.
+const (
+	SourceFacet    = "facet"    // filtro "Empresa atual" da busca
+	SourceHeadline = "headline" // título do card
+	SourceSummary  = "summary"  // resumo ("Atual: ...")
+	SourceSubtitle = "subtitle" // subtítulo secundário (muitas vezes é a região)
+)
+
+// Input é o que o card tem de útil para achar a empresa.
+type Input struct {
+	Title    string // headline
+	Summary  string // resumo do card (coluna role)
+	Caption  string // subtítulo secundário
+	Location string // região já extraída
+	Facet    string // empresa do filtro "Empresa atual" aplicado; vazio = sem filtro
+}
+
+// Result é a empresa escolhida, a fonte e a confiança (0 a 1).
+type Result struct {
+	Company    string
+	Source     string
+	Confidence float64
+}
+
+type candidate struct {
+	name   string
+	source string
+	conf   float64
+}
+
+// Extract junta os candidatos e escolhe um. Com filtro de empresa aplicado,
+// a empresa é a do filtro (todo resultado trabalha lá), com mais confiança
+// se o card concorda. Sem filtro vence o candidato mais confiável, reforçado
+// quando outra fonte diz o mesmo.
+func Extract(in Input) Result {
+	var cands []candidate
+	cands = append(cands, fromHeadline(in.Title)...)
+	cands = append(cands, fromSummary(in.Summary)...)
+	if c := fromCaption(in.Caption, in.Location); c.name != "" {
+		cands = append(cands, c)
+	}
+
+	if facet := clean(in.Facet); facet != "" {
+		for _, c := range cands {
+			if same(c.name, facet) {
+				return Result{Company: facet, Source: SourceFacet, Confidence: 0.95}
+			}
+		}
+		return Result{Company: facet, Source: SourceFacet, Confidence: 0.75}
+	}
+	if len(cands) == 0 {
+		return Result{}
+	}
+	best := cands[0]
+	for _, c := range cands[1:] {
+		if c.conf > best.conf {
+			best = c
+		}
+	}
+	conf := best.conf
+	for _, c := range cands {
+		if c.source != best.source && same(c.name, best.name) {
+			conf = math.Min(0.9, best.conf+0.15)
+			break
+		}
+	}
+	return Result{Company: best.name, Source: best.source, Confidence: math.Round(conf*100) / 100}
+}
+
+// =============== Padrões ===============
+
+var (
+	// "@" só solto ("Dev @ iFood", "Dev @iFood"): colado à esquerda é email
+	reAt   = regexp.MustCompile(`(?i)(?:\s+at\s+|\s+@\s*)(.+)`)
+	reNaNo = regexp.MustCompile(`(?i)\s+(?:na|no|pela|pelo)\s+(.+)`)
+	reEm   = regexp.MustCompile(`(?i)\s+(?:em|do|da)\s+(.+)`)
+	// rótulo do resumo; anterior/past é empresa passada e não serve
+	reLabel = regexp.MustCompile(`(?i)^\s*(atual|current|cargo atual|anterior|past|passado)\s*:\s*`)
+	// email, site ou domínio ("joao@gmail.com", "acme.com.br"): não é nome de empresa
+	reDomain = regexp.MustCompile(`(?i)@|^\S+\.[a-z]{2,}$`)
+	// onde o nome da empresa termina
+	reTail = regexp.MustCompile(`\s*(?:\||·|•|,|\(|\s[-–—]\s|\s/\s).*$`)
+)
+
+// fields são áreas que aparecem depois de "em/do/da" e não são empresa
+// ("Especialista em Segurança", "Mestre em Computação").
+var fields = map[string]bool{
+	"area": true, "busca": true, "transicao": true, "formacao": true, "tecnologia": true, "ti": true,
+	"dados": true, "desenvolvimento": true, "software": true, "computacao": true, "engenharia": true,
+	"gestao": true, "marketing": true, "vendas": true, "rh": true, "financas": true, "direito": true,
+	"administracao": true, "seguranca": true, "recursos": true, "ciencia": true, "ciencias": true,
+	"produto": true, "produtos": true, "projetos": true, "inovacao": true, "saude": true, "educacao": true,
+}
+
+func fromHeadline(title string) []candidate {
+	var out []candidate
+	if n := match(reAt, title, false); n != "" {
+		out = append(out, candidate{n, SourceHeadline, 0.8})
+	}
+	if n := match(reNaNo, title, true); n != "" {
+		out = append(out, candidate{n, SourceHeadline, 0.65})
+	}
+	if parts := strings.Split(title, "|"); len(parts) > 1 {
+		if n := clean(reTail.ReplaceAllString(strings.TrimSpace(parts[1]), "")); looksLikeName(n) && len(strings.Fields(n)) <= 4 {
+			out = append(out, candidate{n, SourceHeadline, 0.45})
+		}
+	}
+	if n := match(reEm, title, true); n != "" {
+		out = append(out, candidate{n, SourceHeadline, 0.4})
+	}
+	return out
+}
+
+func fromSummary(summary string) []candidate {
+	if m := reLabel.FindStringSubmatch(summary); m != nil {
+		switch results.Fold(m[1]) {
+		case "anterior", "past", "passado":
+			return nil
+		}
+		summary = summary[len(m[0]):]
+	}
+	var out []candidate
+	if n := match(reAt, summary, false); n != "" {
+		out = append(out, candidate{n, SourceSummary, 0.75})
+	}
+	if n := match(reNaNo, summary, true); n != "" {
+		out = append(out, candidate{n, SourceSummary, 0.7})
+	}
+	if n := match(reEm, summary, true); n != "" {
+		out = append(out, candidate{n, SourceSummary, 0.5})
+	}
+	return out
+}
+
+// fromCaption usa o subtítulo secundário só quando ele não é a região.
+func fromCaption(caption, location string) candidate {
+	c := clean(caption)
+	if c == "" || results.Fold(c) == results.Fold(location) || LooksLikeLocation(c) || !looksLikeName(c) {
+		return candidate{}
+	}
+	if f := results.Fold(c); strings.Contains(f, "conex") || strings.Contains(f, "seguidores") || strings.Contains(f, "followers") {
+		return candidate{}
+	}
+	return candidate{c, SourceSubtitle, 0.3}
+}
+
+// match devolve o nome depois do padrão, até o primeiro separador. Com
+// strict (padrões em português, mais ambíguos), recusa o que começa com
+// minúscula ou é uma área ("na área de dados", "em Segurança").
+func match(re *regexp.Regexp, text string, strict bool) string {
+	m := re.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	n := clean(reTail.ReplaceAllString(m[1], ""))
+	if !looksLikeName(n) {
+		return ""
+	}
+	if strict {
+		first := results.Fold(strings.Fields(n)[0])
+		if fields[first] || !startsUpper(n) {
+			return ""
+		}
+	}
+	return n
+}
+
+var reLocation = regexp.MustCompile(`(?i)\b(são paulo|sao paulo|rio de janeiro|belo horizonte|curitiba|porto alegre|recife|salvador|brasília|brasilia|florianópolis|florianopolis|campinas|lisboa|porto|brasil|brazil|portugal|london|new york|remote|remoto|área metropolitana|region|região|greater)\b`)
+
+// LooksLikeLocation diz se o texto parece uma região ("São Paulo, SP",
+// "Brasil", "Greater London").
+func LooksLikeLocation(s string) bool {
+	return strings.Contains(s, ",") || reLocation.MatchString(s)
+}
+
+func looksLikeName(s string) bool {
+	return s != "" && len([]rune(s)) <= 60 && len(strings.Fields(s)) <= 6 && !reDomain.MatchString(s)
+}
+
+func startsUpper(s string) bool {
+	for _, r := range s {
+		return strings.ToUpper(string(r)) == string(r)
+	}
+	return false
+}
+
+func same(a, b string) bool {
+	a, b = results.Fold(a), results.Fold(b)
+	if a == "" || b == "" {
+		return false
+	}
+	if len(a) < 3 || len(b) < 3 {
+		return a == b
+	}
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+func clean(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, " ", " ")), " ")
+}
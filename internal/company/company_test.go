@@ -0,0 +1,50 @@
+package company
+
+import "testing"
+
+func TestExtract(t *testing.T) {
+	cases := []struct {
+		name string
+		in   Input
+		want Result
+	}{
+		{"at", Input{Title: "Senior Software Engineer at Nubank | Go, Kubernetes"}, Result{"Nubank", SourceHeadline, 0.8}},
+		{"arroba", Input{Title: "Data Scientist @ iFood"}, Result{"iFood", SourceHeadline, 0.8}},
+		{"arroba colado", Input{Title: "Data Scientist @iFood"}, Result{"iFood", SourceHeadline, 0.8}},
+		{"email não é empresa", Input{Title: "Dev | contato: joao@gmail.com"}, Result{}},
+		{"site não é empresa", Input{Title: "Consultor | acme.com.br"}, Result{}},
+		{"na", Input{Title: "Engenheira de Software na Acme Tecnologia - Remoto"}, Result{"Acme Tecnologia", SourceHeadline, 0.65}},
+		{"pipe", Input{Title: "Tech Recruiter | Stone | Ex-Itaú"}, Result{"Stone", SourceHeadline, 0.45}},
+		{"área não é empresa", Input{Title: "Especialista em Segurança da Informação"}, Result{}},
+		{"minúscula não é empresa", Input{Title: "Pesquisadora na área de IA"}, Result{}},
+		{"resumo", Input{Title: "Backend Developer", Summary: "Atual: Desenvolvedor Backend no Banco Inter"}, Result{"Banco Inter", SourceSummary, 0.7}},
+		{"resumo anterior", Input{Title: "Backend Developer", Summary: "Anterior: Desenvolvedor no Itaú"}, Result{}},
+		{"headline e resumo concordam", Input{Title: "Product Manager at Nubank", Summary: "Atual: Product Manager na Nubank"}, Result{"Nubank", SourceHeadline, 0.9}},
+		{"subtítulo é região", Input{Title: "Desenvolvedor", Caption: "São Paulo, SP"}, Result{}},
+		{"subtítulo igual à região", Input{Title: "Desenvolvedor", Caption: "Recife", Location: "Recife"}, Result{}},
+		{"subtítulo", Input{Title: "Desenvolvedor", Caption: "Acme"}, Result{"Acme", SourceSubtitle, 0.3}},
+		{"filtro confirma", Input{Title: "Engenheiro no Nubank", Facet: "Nubank"}, Result{"Nubank", SourceFacet, 0.95}},
+		{"filtro sem confirmação", Input{Title: "Engenheiro de Software", Facet: "Nubank"}, Result{"Nubank", SourceFacet, 0.75}},
+		{"nada", Input{Title: "Engenheiro de Software"}, Result{}},
+	}
+	for _, c := range cases {
+		if got := Extract(c.in); got != c.want {
+			t.Errorf("%s: Extract = %+v, quero %+v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestLooksLikeLocation(t *testing.T) {
+	for s, want := range map[string]bool{
+		"São Paulo, SP":                  true,
+		"Brasil":                         true,
+		"Greater London":                 true,
+		"Área metropolitana de Curitiba": true,
+		"Nubank":                         false,
+		"Porto Seguro Seguros":           true, // falso positivo aceito: subtítulo é só o último recurso
+	} {
+		if got := LooksLikeLocation(s); got != want {
+			t.Errorf("LooksLikeLocation(%q) = %v", s, got)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+// Package config junta num só lugar o que antes estava espalhado em flags,
+// variáveis de ambiente e constantes (endereço, pasta de dados, navegador,
+// timeouts, pausas, saída e padrões da busca), inclusive os segredos (tag
+// secret, escondidos no config print). Precedência: flags > env > arquivo
+// YAML > padrões.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPath aponta o arquivo de configuração quando não vem --config.
+const EnvPath = "GOLINKEDIN_CONFIG"
+
+type Config struct {
+	LogFormat string   `yaml:"log_format" env:"LOG_FORMAT"`
+	DryRun    bool     `yaml:"dry_run" env:"GOLINKEDIN_DRY_RUN"` // navega e captura, mas não clica no que muda algo
+	Server    Server   `yaml:"server"`
+	OIDC      OIDC     `yaml:"oidc"`
+	Vault     Vault    `yaml:"vault"`
+	SMTP      SMTP     `yaml:"smtp"`
+	Browser   Browser  `yaml:"browser"`
+	Timeouts  Timeouts `yaml:"timeouts"`
+	Throttle  Throttle `yaml:"throttle"`
+	Output    Output   `yaml:"output"`
+	Invites   Invites  `yaml:"invites"`
+	Classify  Classify `yaml:"classify"`
+	Quality   Quality  `yaml:"quality"`
+	Defaults  Defaults `yaml:"defaults"`
+}
+
+type Server struct {
+	Addr       string `yaml:"addr" env:"GOLINKEDIN_ADDR"`
+	DataDir    string `yaml:"data_dir" env:"DATA_DIR"`
+	UsersFile  string `yaml:"users_file" env:"AUTH_USERS_FILE"` // vazio = <data_dir>/users.json
+	CrawlerBin string `yaml:"crawler_bin" env:"CRAWLER_BIN"`    // vazio = o próprio binário (golinkedin crawl)
+	PublicURL  string `yaml:"public_url" env:"PUBLIC_URL"`
+	MinFreeMB  int    `yaml:"min_free_mb" env:"READY_MIN_FREE_MB"` // /readyz
+	// /metrics exige "Authorization: Bearer <token>"; vazio = aberto
+	MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN" secret:"true"`
+}
+
+// OIDC liga o login por SSO quando Issuer está definido.
+type OIDC struct {
+	Issuer        string `yaml:"issuer" env:"OIDC_ISSUER"`
+	ClientID      string `yaml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret  string `yaml:"client_secret" env:"OIDC_CLIENT_SECRET" secret:"true"`
+	RedirectURL   string `yaml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	UsernameClaim string `yaml:"username_claim" env:"OIDC_USERNAME_CLAIM"` // vazio = email
+}
+
+// Vault é a chave mestra do cofre de credenciais (32 bytes em base64).
+type Vault struct {
+	MasterKey     string `yaml:"master_key" env:"VAULT_MASTER_KEY" secret:"true"`
+	MasterKeyFile string `yaml:"master_key_file" env:"VAULT_MASTER_KEY_FILE"` // lido quando master_key está vazio
+}
+
+// SMTP liga as notificações por email; Addr vazio = email desligado.
+type SMTP struct {
+	Addr     string `yaml:"addr" env:"SMTP_ADDR"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" env:"SMTP_FROM"`
+}
+
+type Browser struct {
+	ChromePath string `yaml:"chrome_path" env:"CHROME_PATH"` // vazio = procura no PATH
+	Headless   bool   `yaml:"headless" env:"GOLINKEDIN_HEADLESS"`
+	UserAgent  string `yaml:"user_agent" env:"GOLINKEDIN_USER_AGENT"`
+	Lang       string `yaml:"lang" env:"GOLINKEDIN_LANG"`
+}
+
+type Timeouts struct {
+	Run         time.Duration `yaml:"run" env:"GOLINKEDIN_RUN_TIMEOUT"`             // execução inteira do crawler
+	Challenge   time.Duration `yaml:"challenge" env:"GOLINKEDIN_CHALLENGE_TIMEOUT"` // captcha e 2FA manuais
+	Checkpoint  time.Duration `yaml:"checkpoint" env:"GOLINKEDIN_CHECKPOINT_TIMEOUT"`
+	ReadyChrome time.Duration `yaml:"ready_chrome" env:"READY_CHROME_TIMEOUT"`
+	ReadyTTL    time.Duration `yaml:"ready_chrome_ttl" env:"READY_CHROME_TTL"`
+}
+
+type Throttle struct {
+	PageDelay   Range `yaml:"page_delay" env:"GOLINKEDIN_PAGE_DELAY"`     // entre páginas de resultado
+	InviteDelay Range `yaml:"invite_delay" env:"GOLINKEDIN_INVITE_DELAY"` // entre convites
+}
+
+type Output struct {
+	Dir      string `yaml:"dir" env:"GOLINKEDIN_OUT_DIR"` // CSVs da CLI; jobs da UI usam a pasta do usuário
+	DumpHTML bool   `yaml:"dump_html" env:"GOLINKEDIN_DUMP_HTML"`
+}
+
+type Invites struct {
+	Ledger      string `yaml:"ledger" env:"GOLINKEDIN_INVITE_LEDGER"`             // histórico (JSONL); jobs da UI usam o do usuário
+	Suppress    string `yaml:"suppress" env:"GOLINKEDIN_INVITE_SUPPRESS"`         // lista de não contatar; vazio = nenhuma
+	DailyQuota  int    `yaml:"daily_quota" env:"GOLINKEDIN_INVITE_DAILY_QUOTA"`   // enviados por conta e dia, contados no histórico; 0 = sem cota
+	WeeklyQuota int    `yaml:"weekly_quota" env:"GOLINKEDIN_INVITE_WEEKLY_QUOTA"` // em 7 dias corridos; 0 = sem cota
+	// invites sync retira os pendentes enviados há mais que isso; 0 = não retira
+	WithdrawAfterDays int `yaml:"withdraw_after_days" env:"GOLINKEDIN_INVITE_WITHDRAW_AFTER_DAYS"`
+}
+
+type Classify struct {
+	Rules string `yaml:"rules" env:"GOLINKEDIN_CLASSIFY_RULES"` // YAML com palavras-chave extras de senioridade e área; vazio = só as embutidas
+}
+
+// Quality são os limites do relatório de qualidade de cada execução, em %
+// dos perfis (páginas sem cards: % das páginas; repetidos: % dos cards).
+// Passar de um marca a execução como degradada; 100 desliga a verificação.
+type Quality struct {
+	MinProfiles        int `yaml:"min_profiles" env:"GOLINKEDIN_QUALITY_MIN_PROFILES"` // abaixo disso só páginas sem cards contam
+	MaxEmptyPages      int `yaml:"max_empty_pages" env:"GOLINKEDIN_QUALITY_MAX_EMPTY_PAGES"`
+	MaxDuplicates      int `yaml:"max_duplicates" env:"GOLINKEDIN_QUALITY_MAX_DUPLICATES"`
+	MaxEmptyName       int `yaml:"max_empty_name" env:"GOLINKEDIN_QUALITY_MAX_EMPTY_NAME"`
+	MaxEmptyTitle      int `yaml:"max_empty_title" env:"GOLINKEDIN_QUALITY_MAX_EMPTY_TITLE"`
+	MaxEmptyLocation   int `yaml:"max_empty_location" env:"GOLINKEDIN_QUALITY_MAX_EMPTY_LOCATION"`
+	MaxEmptyCompany    int `yaml:"max_empty_company" env:"GOLINKEDIN_QUALITY_MAX_EMPTY_COMPANY"`
+	MaxNameFromURL     int `yaml:"max_name_from_url" env:"GOLINKEDIN_QUALITY_MAX_NAME_FROM_URL"`
+	MaxLocationIsTitle int `yaml:"max_location_is_title" env:"GOLINKEDIN_QUALITY_MAX_LOCATION_IS_TITLE"`
+	MaxSuspectLocation int `yaml:"max_suspect_location" env:"GOLINKEDIN_QUALITY_MAX_SUSPECT_LOCATION"`
+	MaxSuspectCompany  int `yaml:"max_suspect_company" env:"GOLINKEDIN_QUALITY_MAX_SUSPECT_COMPANY"`
+}
+
+type Defaults struct {
+	MaxPages     int    `yaml:"max_pages" env:"GOLINKEDIN_MAX_PAGES"`
+	MaxInvites   int    `yaml:"max_invites" env:"GOLINKEDIN_MAX_INVITES"`
+	Geo          string `yaml:"geo" env:"GOLINKEDIN_GEO"`
+	FirstCompany bool   `yaml:"first_company" env:"GOLINKEDIN_FIRST_COMPANY"`
+}
+
+// Default devolve os valores de antes do arquivo de configuração.
+func Default() Config {
+	return Config{
+		LogFormat: "text",
+		Server: Server{
+			Addr:      ":8080",
+			DataDir:   "data",
+			PublicURL: "http://localhost:8080",
+			MinFreeMB: 200,
+		},
+		SMTP: SMTP{From: "golinkedin@localhost"},
+		Browser: Browser{
+			Headless:  true,
+			UserAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0 Safari/537.36",
+			Lang:      "pt-BR",
+		},
+		Timeouts: Timeouts{
+			Run:         15 * time.Minute,
+			Challenge:   3 * time.Minute,
+			Checkpoint:  5 * time.Minute,
+			ReadyChrome: 20 * time.Second,
+			ReadyTTL:    time.Minute,
+		},
+		Throttle: Throttle{
+			PageDelay:   Range{1500 * time.Millisecond, 3 * time.Second},
+			InviteDelay: Range{900 * time.Millisecond, 1800 * time.Millisecond},
+		},
+		Output:   Output{Dir: "data"},
+		Invites:  Invites{Ledger: "data/invites.jsonl", Suppress: "data/suppress.txt", DailyQuota: 20, WeeklyQuota: 100},
+		Defaults: Defaults{MaxPages: 1, MaxInvites: 20, Geo: "105871508", FirstCompany: true},
+		Quality: Quality{
+			MinProfiles:        10,
+			MaxEmptyPages:      0,
+			MaxDuplicates:      30,
+			MaxEmptyName:       5,
+			MaxEmptyTitle:      20,
+			MaxEmptyLocation:   50,
+			MaxEmptyCompany:    90,
+			MaxNameFromURL:     20,
+			MaxLocationIsTitle: 10,
+			MaxSuspectLocation: 10,
+			MaxSuspectCompany:  20,
+		},
+	}
+}
+
+// Load aplica, sobre os padrões, o arquivo em path (vazio = nenhum) e depois
+// as variáveis de ambiente. As flags vêm por último, no chamador; Validate
+// fica para depois delas.
+func Load(path string) (Config, error) {
+	c := Default()
+	if path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return c, err
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(b))
+		dec.KnownFields(true) // chave com erro de digitação não passa calada
+		if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return c, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(&c).Elem()); err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Path acha o arquivo de configuração: --config nos args, senão
+// GOLINKEDIN_CONFIG. Serve para carregar antes do flag.Parse, já que os
+// padrões das flags vêm da configuração.
+func Path(args []string) string {
+	for i, a := range args {
+		name, val, hasVal := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if !strings.HasPrefix(a, "-") || name != "config" {
+			continue
+		}
+		if hasVal {
+			return val
+		}
+		if i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return os.Getenv(EnvPath)
+}
+
+// applyEnv sobrescreve os campos com tag env cuja variável está definida.
+func applyEnv(v reflect.Value) error {
+	var errs []error
+	for i := 0; i < v.NumField(); i++ {
+		f, sf := v.Field(i), v.Type().Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != rangeType {
+			if err := applyEnv(f); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		name := sf.Tag.Get("env")
+		raw, ok := os.LookupEnv(name)
+		if name == "" || !ok || raw == "" {
+			continue
+		}
+		if err := setValue(f, raw); err != nil {
+			errs = append(errs, fmt.Errorf("%s=%q: %w", name, raw, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	rangeType    = reflect.TypeOf(Range{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+func setValue(f reflect.Value, raw string) error {
+	switch {
+	case f.Type() == rangeType:
+		return f.Addr().Interface().(*Range).UnmarshalText([]byte(raw))
+	case f.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+	case f.Kind() == reflect.String:
+		f.SetString(raw)
+	case f.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case f.Kind() == reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	default:
+		return fmt.Errorf("tipo %s sem suporte", f.Type())
+	}
+	return nil
+}
+
+// Validate devolve todos os problemas de uma vez.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, field, msg string) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", field, msg))
+		}
+	}
+	check(c.LogFormat == "text" || c.LogFormat == "json", "log_format", "use text ou json")
+	_, _, err := net.SplitHostPort(c.Server.Addr)
+	check(err == nil, "server.addr", "use host:porta (ex: :8080)")
+	check(c.Server.DataDir != "", "server.data_dir", "obrigatório")
+	check(c.Server.MinFreeMB >= 0, "server.min_free_mb", "não pode ser negativo")
+	if c.OIDC.Issuer != "" {
+		check(c.OIDC.ClientID != "", "oidc.client_id", "obrigatório com oidc.issuer")
+		check(c.OIDC.RedirectURL != "", "oidc.redirect_url", "obrigatório com oidc.issuer")
+	}
+	if c.SMTP.Addr != "" {
+		_, _, err := net.SplitHostPort(c.SMTP.Addr)
+		check(err == nil, "smtp.addr", "use host:porta (ex: smtp.exemplo.com:587)")
+		check(c.SMTP.From != "", "smtp.from", "obrigatório com smtp.addr")
+	}
+	check(c.Browser.UserAgent != "", "browser.user_agent", "obrigatório")
+	check(c.Browser.Lang != "", "browser.lang", "obrigatório")
+	check(c.Timeouts.Run > 0, "timeouts.run", "deve ser maior que zero")
+	check(c.Timeouts.Challenge > 0, "timeouts.challenge", "deve ser maior que zero")
+	check(c.Timeouts.Checkpoint > 0, "timeouts.checkpoint", "deve ser maior que zero")
+	check(c.Timeouts.ReadyChrome > 0, "timeouts.ready_chrome", "deve ser maior que zero")
+	check(c.Timeouts.ReadyTTL >= 0, "timeouts.ready_chrome_ttl", "não pode ser negativo")
+	check(c.Throttle.PageDelay.valid(), "throttle.page_delay", "use mínimo-máximo com 0 <= mínimo <= máximo")
+	check(c.Throttle.InviteDelay.valid(), "throttle.invite_delay", "use mínimo-máximo com 0 <= mínimo <= máximo")
+	check(c.Output.Dir != "", "output.dir", "obrigatório")
+	check(c.Invites.Ledger != "", "invites.ledger", "obrigatório (sem histórico, convites se repetem)")
+	check(c.Invites.DailyQuota >= 0, "invites.daily_quota", "não pode ser negativo (0 = sem cota)")
+	check(c.Invites.WeeklyQuota >= 0, "invites.weekly_quota", "não pode ser negativo (0 = sem cota)")
+	check(c.Invites.WithdrawAfterDays >= 0, "invites.withdraw_after_days", "não pode ser negativo (0 = não retira)")
+	check(c.Quality.MinProfiles >= 0, "quality.min_profiles", "não pode ser negativo")
+	for _, q := range []struct {
+		name string
+		pct  int
+	}{
+		{"max_empty_pages", c.Quality.MaxEmptyPages}, {"max_duplicates", c.Quality.MaxDuplicates},
+		{"max_empty_name", c.Quality.MaxEmptyName}, {"max_empty_title", c.Quality.MaxEmptyTitle},
+		{"max_empty_location", c.Quality.MaxEmptyLocation}, {"max_empty_company", c.Quality.MaxEmptyCompany},
+		{"max_name_from_url", c.Quality.MaxNameFromURL}, {"max_location_is_title", c.Quality.MaxLocationIsTitle},
+		{"max_suspect_location", c.Quality.MaxSuspectLocation}, {"max_suspect_company", c.Quality.MaxSuspectCompany},
+	} {
+		check(q.pct >= 0 && q.pct <= 100, "quality."+q.name, "use uma porcentagem de 0 a 100")
+	}
+	check(c.Defaults.MaxPages >= 1, "defaults.max_pages", "mínimo 1")
+	check(c.Defaults.MaxInvites >= 0, "defaults.max_invites", "não pode ser negativo")
+	return errors.Join(errs...)
+}
+
+// YAML serializa a configuração efetiva (config print).
+func (c Config) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), enc.Close()
+}
+
+// redaction é o que o config print mostra no lugar de um segredo.
+const redaction = "***"
+
+// Redacted devolve uma cópia com os campos secret preenchidos trocados por
+// "***", para o config print não vazar senha e chave no terminal ou no log.
+func (c Config) Redacted() Config {
+	redact(reflect.ValueOf(&c).Elem())
+	return c
+}
+
+func redact(v reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		f, sf := v.Field(i), v.Type().Field(i)
+		switch {
+		case sf.Type.Kind() == reflect.Struct && sf.Type != rangeType:
+			redact(f)
+		case sf.Tag.Get("secret") == "true" && f.Kind() == reflect.String && f.String() != "":
+			f.SetString(redaction)
+		}
+	}
+}
+
+// =============== Range ===============
+
+// Range é um intervalo de pausa, escrito "1.5s-3s" no YAML, no env e nas flags.
+type Range struct {
+	Min, Max time.Duration
+}
+
+func (r Range) MarshalText() ([]byte, error) {
+	return []byte(r.Min.String() + "-" + r.Max.String()), nil
+}
+
+func (r *Range) UnmarshalText(b []byte) error {
+	lo, hi, ok := strings.Cut(strings.TrimSpace(string(b)), "-")
+	if !ok {
+		hi = lo
+	}
+	min, err := time.ParseDuration(strings.TrimSpace(lo))
+	if err != nil {
+		return fmt.Errorf("intervalo %q: use mínimo-máximo (ex: 1.5s-3s)", b)
+	}
+	max, err := time.ParseDuration(strings.TrimSpace(hi))
+	if err != nil {
+		return fmt.Errorf("intervalo %q: use mínimo-máximo (ex: 1.5s-3s)", b)
+	}
+	r.Min, r.Max = min, max
+	return nil
+}
+
+func (r Range) valid() bool {
+	return r.Min >= 0 && r.Max >= r.Min
+}
+
+// Rand sorteia uma duração no intervalo.
+func (r Range) Rand() time.Duration {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + time.Duration(rand.Int63n(int64(r.Max-r.Min)+1))
+}
+
+// =============== config validate / config print ===============
+
+// Command roda "config validate" ou "config print" (sobre o arquivo de
+// --config/GOLINKEDIN_CONFIG e o env; segredos saem como ***) e devolve o
+// código de saída.
+func Command(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", os.Getenv(EnvPath), "Arquivo de configuração YAML (env "+EnvPath+")")
+	if len(args) == 0 || (args[0] != "validate" && args[0] != "print") {
+		fmt.Fprintln(stderr, "uso: golinkedin config validate|print [--config arquivo.yaml]")
+		return 2
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	c, err := Load(*path)
+	if err == nil {
+		err = c.Validate()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "configuração inválida:")
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintln(stderr, "  -", line)
+		}
+		return 1
+	}
+	if args[0] == "validate" {
+		src := *path
+		if src == "" {
+			src = "sem arquivo, só padrões e env"
+		}
+		fmt.Fprintf(stdout, "configuração ok (%s)\n", src)
+		return 0
+	}
+	b, err := c.Redacted().YAML()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	stdout.Write(b)
+	return 0
+}
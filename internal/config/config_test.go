@@ -0,0 +1,185 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, body string) string {
+	p := filepath.Join(t.TempDir(), "golinkedin.yaml")
+	if err := os.WriteFile(p, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLayering(t *testing.T) {
+	p := writeFile(t, `
+server:
+  addr: ":9090"
+  data_dir: /srv/data
+browser:
+  lang: en-US
+throttle:
+  page_delay: 2s-4s
+defaults:
+  max_pages: 3
+`)
+	t.Setenv("DATA_DIR", "/env/data")
+	t.Setenv("GOLINKEDIN_INVITE_DELAY", "1s-2s")
+	t.Setenv("GOLINKEDIN_HEADLESS", "false")
+
+	c, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Server.Addr != ":9090" || c.Browser.Lang != "en-US" || c.Defaults.MaxPages != 3 {
+		t.Errorf("arquivo não aplicado: %+v", c)
+	}
+	if c.Server.DataDir != "/env/data" || c.Browser.Headless || c.Throttle.InviteDelay != (Range{time.Second, 2 * time.Second}) {
+		t.Errorf("env não venceu o arquivo: %+v", c)
+	}
+	if c.Throttle.PageDelay != (Range{2 * time.Second, 4 * time.Second}) {
+		t.Errorf("page_delay = %v", c.Throttle.PageDelay)
+	}
+	if c.Timeouts.Run != 15*time.Minute || c.Browser.UserAgent == "" {
+		t.Errorf("padrões perdidos: %+v", c.Timeouts)
+	}
+	if err := c.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(writeFile(t, "server:\n  adr: \":80\"\n")); err == nil || !strings.Contains(err.Error(), "adr") {
+		t.Errorf("chave desconhecida aceita: %v", err)
+	}
+	if _, err := Load(writeFile(t, "")); err != nil {
+		t.Errorf("arquivo vazio: %v", err)
+	}
+	t.Setenv("GOLINKEDIN_MAX_PAGES", "muitas")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "GOLINKEDIN_MAX_PAGES") {
+		t.Errorf("env inválido aceito: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := Default()
+	c.LogFormat = "xml"
+	c.Server.Addr = "8080"
+	c.Defaults.MaxPages = 0
+	c.Throttle.PageDelay = Range{3 * time.Second, time.Second}
+	c.Quality.MaxEmptyTitle = 120
+	err := c.Validate()
+	if err == nil {
+		t.Fatal("configuração inválida passou")
+	}
+	for _, field := range []string{"log_format", "server.addr", "defaults.max_pages", "throttle.page_delay", "quality.max_empty_title"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("faltou %s em %q", field, err)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	var r Range
+	if err := r.UnmarshalText([]byte("500ms")); err != nil || r != (Range{500 * time.Millisecond, 500 * time.Millisecond}) {
+		t.Errorf("valor único = %v, %v", r, err)
+	}
+	if err := r.UnmarshalText([]byte("1s-x")); err == nil {
+		t.Error("aceitou 1s-x")
+	}
+	r = Range{time.Second, 2 * time.Second}
+	if b, _ := r.MarshalText(); string(b) != "1s-2s" {
+		t.Errorf("texto = %s", b)
+	}
+	for range 100 {
+		if d := r.Rand(); d < r.Min || d > r.Max {
+			t.Fatalf("Rand fora do intervalo: %v", d)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv(EnvPath, "/env.yaml")
+	for args, want := range map[string]string{
+		"--query x --config a.yaml": "a.yaml",
+		"-config=b.yaml":            "b.yaml",
+		"--query config":            "/env.yaml",
+	} {
+		if got := Path(strings.Fields(args)); got != want {
+			t.Errorf("Path(%q) = %q, quero %q", args, got, want)
+		}
+	}
+}
+
+func TestCommand(t *testing.T) {
+	p := writeFile(t, "defaults:\n  geo: \"\"\n")
+	var out, errOut bytes.Buffer
+	if code := Command([]string{"print", "--config", p}, &out, &errOut); code != 0 {
+		t.Fatalf("print = %d: %s", code, errOut.String())
+	}
+	// o que o print escreve volta a ser uma configuração válida e igual
+	c, err := Load(writeFile(t, out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c != func() Config { d := Default(); d.Defaults.Geo = ""; return d }() {
+		t.Errorf("ida e volta mudou a configuração:\n%s", out.String())
+	}
+
+	bad := writeFile(t, "timeouts:\n  run: 0s\n")
+	errOut.Reset()
+	if code := Command([]string{"validate", "--config", bad}, &out, &errOut); code != 1 || !strings.Contains(errOut.String(), "timeouts.run") {
+		t.Errorf("validate = %d: %s", code, errOut.String())
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	p := writeFile(t, "smtp:\n  addr: smtp.exemplo.com:587\n  username: avisos\n")
+	t.Setenv("SMTP_PASSWORD", "senha-smtp")
+	t.Setenv("OIDC_ISSUER", "https://sso.exemplo.com")
+	t.Setenv("OIDC_CLIENT_ID", "golinkedin")
+	t.Setenv("OIDC_CLIENT_SECRET", "segredo-oidc")
+	t.Setenv("OIDC_REDIRECT_URL", "http://localhost:8080/auth/oidc/callback")
+	t.Setenv("METRICS_TOKEN", "token-metricas")
+	t.Setenv("VAULT_MASTER_KEY", "chave-mestra")
+
+	c, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SMTP.Password != "senha-smtp" || c.OIDC.ClientSecret != "segredo-oidc" || c.Server.MetricsToken != "token-metricas" || c.Vault.MasterKey != "chave-mestra" {
+		t.Errorf("segredos do env não aplicados: %+v", c)
+	}
+	if c.SMTP.Addr != "smtp.exemplo.com:587" || c.SMTP.From != "golinkedin@localhost" {
+		t.Errorf("smtp = %+v", c.SMTP)
+	}
+
+	var out, errOut bytes.Buffer
+	if code := Command([]string{"print", "--config", p}, &out, &errOut); code != 0 {
+		t.Fatalf("print = %d: %s", code, errOut.String())
+	}
+	for _, secret := range []string{"senha-smtp", "segredo-oidc", "token-metricas", "chave-mestra"} {
+		if strings.Contains(out.String(), secret) {
+			t.Errorf("config print mostrou %q", secret)
+		}
+	}
+	if !strings.Contains(out.String(), "password: '***'") || !strings.Contains(out.String(), "client_id: golinkedin") {
+		t.Errorf("config print:\n%s", out.String())
+	}
+	if c.Redacted(); c.SMTP.Password != "senha-smtp" {
+		t.Error("Redacted mexeu no original")
+	}
+
+	t.Setenv("OIDC_REDIRECT_URL", "")
+	t.Setenv("OIDC_CLIENT_ID", "")
+	c, _ = Load(p)
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "oidc.client_id") {
+		t.Errorf("oidc incompleto passou: %v", err)
+	}
+}
@@ -0,0 +1,1276 @@
+// Package crawler é a automação do LinkedIn no Chromium (login, busca,
+// captura, convites) usada por "golinkedin crawl" e "golinkedin parse".
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/chromedp/chromedp/kb"
+
+	"CrawlerLinkedin/internal/classify"
+	"CrawlerLinkedin/internal/company"
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/events"
+	"CrawlerLinkedin/internal/logging"
+	"CrawlerLinkedin/internal/names"
+	"CrawlerLinkedin/internal/notes"
+	"CrawlerLinkedin/internal/quality"
+	"CrawlerLinkedin/internal/results"
+	"CrawlerLinkedin/internal/summary"
+	"CrawlerLinkedin/internal/totp"
+	"CrawlerLinkedin/internal/vault"
+)
+
+type Profile struct {
+	Name        string
+	FirstName   string // names.Parse: primeiro nome
+	LastName    string // último sobrenome, sem partícula ("Silva" de "Ana da Silva")
+	Title       string
+	Company     string
+	Location    string
+	Role        string
+	URL         string
+	SourceQuery string
+	CapturedAt  time.Time
+	Seniority   string // classify, a partir do título e do cargo
+	Function    string
+
+	CompanySource     string  // de onde veio Company (company.Source*)
+	CompanyConfidence float64 // 0 a 1
+}
+
+// run é o estado de uma execução (Run, Invite, Sync ou ParseHTML). Cada
+// chamada cria o seu: duas execuções no mesmo processo não misturam
+// configuração, contagens nem screenshots.
+type run struct {
+	cfg config.Config
+
+	// rules classifica senioridade e área dos perfis (cfg.Classify.Rules).
+	rules *classify.Rules
+
+	// companyFacet é a empresa do filtro "Empresa atual" aplicado na busca;
+	// confere a empresa extraída de cada card.
+	companyFacet string
+
+	// tally conta, página a página, o que vai para o relatório de qualidade.
+	tally quality.Tally
+
+	// dryRunShots numera os screenshots do dry-run na execução.
+	dryRunShots int
+
+	// metrics recebe as amostras com --metrics; o servidor lê do stdout e
+	// soma no /metrics. nil = não reporta (CLI).
+	metrics *events.Writer
+
+	// stopRequested liga no primeiro SIGINT/SIGTERM: o crawler termina a
+	// página atual, grava o que já capturou e fecha o navegador.
+	stopRequested atomic.Bool
+}
+
+// Options são os parâmetros de uma execução que não vêm da configuração.
+type Options struct {
+	Query       string
+	Credentials vault.Credential
+	Note        *notes.Template // nota dos convites (Invite); nil = "Enviar sem nota"
+	Account     string          // conta no histórico de convites; vazio = o email
+	JobID       string          // job do servidor web, no histórico de convites
+	UserDataDir string          // perfil do Chromium reaproveitado entre execuções
+	Interactive bool            // desafios resolvidos pela UI web (eventos no Events, comandos no Stdin)
+	Metrics     bool            // amostras de métricas no Events
+	Stdin       *bufio.Reader   // o mesmo de onde vieram as credenciais
+	Events      io.Writer
+}
+
+// Run faz login, busca e captura até c.Defaults.MaxPages páginas, grava o CSV
+// (mesmo parcial, se cancelado) e o resumo em c.Output.Dir. Não convida
+// ninguém: convites só saem de uma lista revisada (Invite). O erro devolvido
+// já foi registrado no log e no resumo.
+func Run(c config.Config, opts Options) error {
+	query := sanitizeQuotes(opts.Query)
+	r, ui, err := setup(c, opts)
+	if err != nil {
+		return err
+	}
+
+	sum := summary.Summary{Query: query, DryRun: r.cfg.DryRun, StartedAt: time.Now()}
+	closeBrowser := func() {}
+	fail := func(format string, args ...any) error {
+		sum.Error = fmt.Sprintf(format, args...)
+		sum.EndedAt = time.Now()
+		if err := summary.Write(r.cfg.Output.Dir, sum); err != nil {
+			slog.Warn("gravando resumo", "err", err)
+		}
+		closeBrowser()
+		slog.Error(sum.Error, logging.KeyQuery, query, logging.KeyDuration, sum.EndedAt.Sub(sum.StartedAt))
+		return errors.New(sum.Error)
+	}
+	// finish grava o CSV (mesmo parcial) e o resumo. Cancelado antes de
+	// capturar qualquer página não gera CSV.
+	finish := func(all []Profile) error {
+		sum.Profiles = len(all)
+		sum.Cancelled = r.stopRequested.Load()
+		if !sum.Cancelled || len(all) > 0 {
+			filename := filepath.Join(r.cfg.Output.Dir, fmt.Sprintf("linkedin_%s.csv", time.Now().Format("20060102_150405")))
+			if err := WriteCSV(filename, all); err != nil {
+				return fail("erro salvando CSV: %v", err)
+			}
+			slog.Info("CSV salvo", "path", filename, logging.KeyProfileCount, len(all))
+			sum.CSVPath = filename
+		}
+		if r.tally.Pages > 0 {
+			rep := quality.Build(r.tally, Rows(all), quality.Thresholds(r.cfg.Quality))
+			if err := quality.Write(r.cfg.Output.Dir, rep); err != nil {
+				slog.Warn("gravando relatório de qualidade", "err", err)
+			} else {
+				sum.QualityPath = filepath.Join(r.cfg.Output.Dir, quality.FileName)
+			}
+			sum.Degraded, sum.Issues = rep.Degraded, rep.Reasons
+			if rep.Degraded {
+				slog.Warn("captura degradada: o LinkedIn pode ter mudado a página", "reasons", rep.Reasons)
+			}
+		}
+		sum.EndedAt = time.Now()
+		if err := summary.Write(r.cfg.Output.Dir, sum); err != nil {
+			slog.Warn("gravando resumo", "err", err)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeouts.Run)
+	defer cancel()
+	defer r.handleStopSignals(cancel)()
+
+	bctx, stopBrowser, err := r.openSession(ctx, opts, &sum, ui)
+	if err != nil {
+		if r.stopRequested.Load() {
+			slog.Warn("cancelado durante o login")
+			return finish(nil)
+		}
+		return fail("%v", err)
+	}
+	defer stopBrowser()
+	closeBrowser = stopBrowser
+
+	slog.Info("buscando", logging.KeyQuery, query, "geo", r.cfg.Defaults.Geo)
+	start := time.Now()
+	if err := r.runSearchViaURL(bctx, query, splitList(r.cfg.Defaults.Geo)); err != nil {
+		if r.stopRequested.Load() {
+			slog.Warn("cancelado antes da primeira página")
+			return finish(nil)
+		}
+		return fail("falha ao executar busca: %v", err)
+	}
+	slog.Info("resultados carregados", logging.KeyQuery, query, logging.KeyDuration, time.Since(start))
+
+	if r.cfg.Defaults.FirstCompany {
+		if facet, err := r.applyFirstCurrentCompanyOption(bctx, query); err != nil {
+			slog.Warn("não consegui aplicar o 1º item de 'Empresa atual'", "err", err)
+		} else if r.cfg.DryRun {
+			slog.Info("dry-run: filtro 'Empresa atual' não aplicado; resultados sem o filtro", "company", facet)
+		} else {
+			r.companyFacet = facet
+			slog.Info("filtro 'Empresa atual' aplicado (1º item)", "company", facet)
+		}
+	}
+
+	//if err := clickTwoFilterButtons(bctx); err != nil {
+	//	slog.Warn("filtros", "err", err)
+	//}
+
+	if r.cfg.Output.DumpHTML {
+		if err := dumpPageHTML(bctx, filepath.Join(r.cfg.Output.Dir, "results_page_1.html")); err != nil {
+			slog.Warn("dump do HTML falhou", "err", err)
+		} else {
+			slog.Info("HTML salvo", "path", filepath.Join(r.cfg.Output.Dir, "results_page_1.html"))
+		}
+	}
+
+	var all []Profile
+	captured := map[string]bool{} // o LinkedIn às vezes repete perfis entre páginas
+	for page := 1; page <= r.cfg.Defaults.MaxPages; page++ {
+		slog.Debug("capturando página", logging.KeyPage, page, "max_pages", r.cfg.Defaults.MaxPages)
+		pageStart := time.Now()
+		items, err := r.scrapeCurrentPage(bctx, query)
+		if err != nil {
+			slog.Warn("erro capturando página", logging.KeyPage, page, "err", err)
+		}
+
+		r.tidyProfiles(items)
+		items = slices.DeleteFunc(items, func(p Profile) bool {
+			if captured[p.URL] {
+				r.tally.Duplicates++
+				return true
+			}
+			captured[p.URL] = true
+			return false
+		})
+
+		slog.Info("página capturada", logging.KeyPage, page, logging.KeyProfileCount, len(items), logging.KeyDuration, time.Since(pageStart))
+		all = append(all, items...)
+		sum.Pages = page
+
+		if r.stopRequested.Load() {
+			slog.Warn("cancelado: parando após a página", logging.KeyPage, page)
+			break
+		}
+		if page < r.cfg.Defaults.MaxPages {
+			ok := r.goNextPage(bctx)
+			if !ok {
+				slog.Info("sem botão 'Avançar' (fim dos resultados); encerrando paginação", logging.KeyPage, page)
+				break
+			}
+			time.Sleep(r.cfg.Throttle.PageDelay.Rand())
+		}
+	}
+
+	slog.Info("captura concluída", logging.KeyQuery, query, "pages", sum.Pages, logging.KeyProfileCount, len(all))
+
+	if err := finish(all); err != nil {
+		return err
+	}
+	slog.Info("fim", logging.KeyQuery, query, logging.KeyProfileCount, len(all), logging.KeyDuration, time.Since(sum.StartedAt))
+	return nil
+}
+
+// setup cria o estado da execução: aplica a configuração, liga eventos e
+// métricas e cria a pasta de saída. Devolve também a UI de desafios (nil sem
+// Interactive).
+func setup(c config.Config, opts Options) (*run, *challengeUI, error) {
+	r := &run{cfg: c}
+	// eventos da UI e métricas dividem o stdout; um só Writer evita linhas misturadas
+	var evw *events.Writer
+	if opts.Interactive || opts.Metrics {
+		evw = events.NewWriter(opts.Events)
+	}
+	if opts.Metrics {
+		r.metrics = evw
+	}
+	var ui *challengeUI
+	if opts.Interactive {
+		ui = newChallengeUI(opts.Stdin, evw)
+	}
+	if err := os.MkdirAll(c.Output.Dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("criando pasta de saída: %w", err)
+	}
+	rules, err := classify.Load(c.Classify.Rules)
+	if err != nil {
+		return nil, nil, fmt.Errorf("regras de classificação: %w", err)
+	}
+	r.rules = rules
+	if c.DryRun {
+		slog.Warn("dry-run: convites e filtros não serão clicados; veja o log e os screenshots", "dir", filepath.Join(c.Output.Dir, "dryrun"))
+	}
+	return r, ui, nil
+}
+
+// openSession lança o Chrome (com o perfil de opts.UserDataDir) e faz login.
+// Em erro o navegador já foi fechado.
+func (r *run) openSession(ctx context.Context, opts Options, sum *summary.Summary, ui *challengeUI) (context.Context, func(), error) {
+	allocOpts := r.allocatorOptions()
+	if opts.UserDataDir != "" {
+		if err := os.MkdirAll(opts.UserDataDir, 0o700); err != nil {
+			return nil, nil, fmt.Errorf("criando pasta de sessão: %w", err)
+		}
+		allocOpts = append(allocOpts, chromedp.UserDataDir(opts.UserDataDir))
+	}
+	bctx, stopBrowser, err := r.startBrowser(ctx, allocOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("inicializando chrome: %w", err)
+	}
+
+	slog.Info("login no LinkedIn", "headless", r.cfg.Browser.Headless)
+	start := time.Now()
+	if err := r.loginLinkedIn(bctx, opts.Credentials, r.cfg.Browser.Headless, sum, ui); err != nil {
+		stopBrowser()
+		return nil, nil, fmt.Errorf("falha no login: %w", err)
+	}
+	slog.Info("login ok", logging.KeyDuration, time.Since(start))
+	return bctx, stopBrowser, nil
+}
+
+// ParseHTML roda a mesma extração da busca num HTML salvo com --dump-html,
+// sem login: serve para conferir seletores offline.
+func ParseHTML(c config.Config, path, sourceQuery string) ([]Profile, error) {
+	rules, err := classify.Load(c.Classify.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("regras de classificação: %w", err)
+	}
+	r := &run{cfg: c, rules: rules}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeouts.Run)
+	defer cancel()
+	bctx, stopBrowser, err := r.startBrowser(ctx, r.allocatorOptions())
+	if err != nil {
+		return nil, fmt.Errorf("inicializando chrome: %w", err)
+	}
+	defer stopBrowser()
+	u := url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	if err := chromedp.Run(bctx, chromedp.Navigate(u.String())); err != nil {
+		return nil, fmt.Errorf("abrindo %s: %w", path, err)
+	}
+	items, err := r.scrapeCurrentPage(bctx, sourceQuery)
+	r.tidyProfiles(items)
+	return items, err
+}
+
+// tidyProfiles corrige o que a extração deixa passar: status, selos, emoji e
+// credenciais no nome (names), nome vazio (vem da URL) e região repetindo o
+// título. Também separa primeiro nome e sobrenome e classifica senioridade e
+// área.
+func (r *run) tidyProfiles(items []Profile) {
+	for i := range items {
+		items[i].Name = names.Normalize(items[i].Name)
+		if items[i].Name == "" {
+			if n := guessNameFromURL(items[i].URL); n != "" {
+				items[i].Name = n
+				r.tally.NameFromURL++
+			}
+		}
+		if items[i].Title != "" && items[i].Location != "" &&
+			strings.EqualFold(items[i].Title, items[i].Location) {
+			items[i].Location = ""
+			r.tally.LocationIsTitle++
+		}
+		n := names.Parse(items[i].Name)
+		items[i].FirstName, items[i].LastName = n.First, n.Last
+		items[i].Seniority, items[i].Function = r.rules.Classify(items[i].Title, items[i].Role)
+	}
+}
+
+// =============== Navegador ===============
+
+// allocatorOptions monta as opções do Chrome a partir de cfg.Browser.
+func (r *run) allocatorOptions() []chromedp.ExecAllocatorOption {
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", r.cfg.Browser.Headless),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+		chromedp.Flag("lang", r.cfg.Browser.Lang),
+		chromedp.UserAgent(r.cfg.Browser.UserAgent),
+	)
+	if r.cfg.Browser.ChromePath != "" {
+		opts = append(opts, chromedp.ExecPath(r.cfg.Browser.ChromePath))
+	}
+	return opts
+}
+
+// startBrowser lança o Chrome e abre about:blank. Se não subir (crash no
+// start, perfil ainda travado por um Chrome que morreu), relança uma vez.
+func (r *run) startBrowser(ctx context.Context, opts []chromedp.ExecAllocatorOption) (context.Context, func(), error) {
+	var err error
+	for attempt := 1; attempt <= 2; attempt++ {
+		if attempt > 1 {
+			slog.Warn("Chrome não iniciou; relançando", "err", err)
+			r.countMetric(events.MetricChromeRestarts, "", 1)
+			time.Sleep(2 * time.Second)
+		}
+		allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+		bctx, bcancel := chromedp.NewContext(allocCtx)
+		if err = chromedp.Run(bctx, chromedp.Navigate("about:blank")); err == nil {
+			return bctx, func() {
+				bcancel()
+				allocCancel()
+			}, nil
+		}
+		bcancel()
+		allocCancel()
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, nil, err
+}
+
+// =============== Métricas ===============
+
+func (r *run) countMetric(name, label string, n int) {
+	if r.metrics == nil || n <= 0 {
+		return
+	}
+	_ = r.metrics.Emit(events.Metric{Type: events.TypeMetric, Name: name, Label: label, Value: float64(n)})
+}
+
+// observeLoad registra quanto a etapa levou desde start.
+func (r *run) observeLoad(step string, start time.Time) {
+	if r.metrics == nil {
+		return
+	}
+	_ = r.metrics.Emit(events.Metric{Type: events.TypeMetric, Name: events.MetricPageLoad, Label: step, Value: time.Since(start).Seconds()})
+}
+
+// =============== Cancelamento ===============
+
+// handleStopSignals trata o primeiro sinal como parada suave; o segundo chama
+// abort, que derruba o navegador na hora (o que já foi capturado ainda é gravado).
+// A função devolvida desliga o tratamento no fim da execução.
+func (r *run) handleStopSignals(abort context.CancelFunc) func() {
+	ch := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+		r.stopRequested.Store(true)
+		slog.Warn("sinal recebido: parando após a página atual (repita para abortar)")
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+		slog.Warn("abortando")
+		abort()
+	}()
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// =============== Credenciais ===============
+
+// LoadCredentials busca email/senha sem passar a senha pela linha de comando
+// (visível no `ps`). Ordem: stdin, arquivo, variáveis de ambiente; --email só
+// preenche o email se nenhuma fonte trouxer um.
+func LoadCredentials(stdin *bufio.Reader, email, file string, fromStdin bool) (vault.Credential, error) {
+	var c vault.Credential
+	switch {
+	case fromStdin:
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return c, fmt.Errorf("lendo stdin: %w", err)
+		}
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			return c, fmt.Errorf("JSON inválido no stdin: %w", err)
+		}
+	case file != "":
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return c, err
+		}
+		if err := json.Unmarshal(b, &c); err != nil {
+			return c, fmt.Errorf("JSON inválido em %s: %w", file, err)
+		}
+	default:
+		c.Email = os.Getenv("LINKEDIN_EMAIL")
+		c.Password = os.Getenv("LINKEDIN_PASSWORD")
+	}
+	if c.Email == "" {
+		c.Email = email
+	}
+	return c, nil
+}
+
+// =============== Login ===============
+
+func (r *run) loginLinkedIn(ctx context.Context, creds vault.Credential, headless bool, sum *summary.Summary, ui *challengeUI) error {
+	const loginURL = "https://www.linkedin.com/checkpoint/lg/sign-in-another-account"
+	const feedURL = "https://www.linkedin.com/feed/"
+
+	if isLoggedIn(ctx, feedURL) {
+		slog.Info("sessão existente reaproveitada (user-data-dir)")
+		return nil
+	}
+
+	start := time.Now()
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(loginURL),
+		chromedp.WaitVisible(`#username`, chromedp.ByQuery),
+	); err != nil {
+		return err
+	}
+	r.observeLoad("login", start)
+	if err := chromedp.Run(ctx,
+		chromedp.SetValue(`#username`, creds.Email, chromedp.ByQuery),
+		chromedp.SetValue(`#password, input[name="session_password"]`, creds.Password, chromedp.ByQuery),
+	); err != nil {
+		return err
+	}
+
+	clickTried := chromedp.Run(ctx,
+		chromedp.WaitVisible(`button[data-litms-control-urn="login-submit"], button[type="submit"]`, chromedp.ByQuery),
+		chromedp.ScrollIntoView(`button[data-litms-control-urn="login-submit"], button[type="submit"]`, chromedp.ByQuery),
+		chromedp.Click(`button[data-litms-control-urn="login-submit"], button[type="submit"]`, chromedp.ByQuery),
+		chromedp.Sleep(400*time.Millisecond),
+	)
+	if clickTried != nil {
+		_ = chromedp.Run(ctx, chromedp.Submit(`form`))
+		_ = chromedp.Run(ctx, chromedp.Focus(`#password, input[name="session_password"]`), chromedp.KeyEvent("\r"))
+	}
+
+	if isCaptcha(ctx) {
+		sum.Challenges = append(sum.Challenges, "captcha")
+		r.countMetric(events.MetricChallenges, "captcha", 1)
+		if headless && ui == nil {
+			return errors.New("captcha (iframe) detectado em modo headless; rode com --headless=false ou --interactive para resolver manualmente")
+		}
+		slog.Warn("captcha detectado; resolva manualmente", "challenge", "captcha", "timeout", r.cfg.Timeouts.Challenge)
+		if err := r.waitDisappear(ctx, r.cfg.Timeouts.Challenge, `iframe[src*="captcha"], iframe[src*="challenge"]`, ui, "captcha"); err != nil {
+			return errors.New("timeout aguardando captcha (iframe)")
+		}
+	}
+
+	if isCheckpointChallenge(ctx) {
+		sum.Challenges = append(sum.Challenges, "checkpoint")
+		r.countMetric(events.MetricChallenges, "checkpoint", 1)
+		if headless && ui == nil {
+			return errors.New("checkpoint challenge (página inteira) detectado em modo headless; rode com --headless=false ou --interactive para resolver manualmente")
+		}
+		slog.Warn("checkpoint detectado; tentando 'Iniciar desafio' e aguardando a resolução", "challenge", "checkpoint", "timeout", r.cfg.Timeouts.Checkpoint)
+		_ = chromedp.Run(ctx,
+			chromedp.ActionFunc(func(c context.Context) error {
+				var clicked bool
+				return chromedp.EvaluateAsDevTools(`(()=>{
+          const b = document.querySelector('[data-theme="home.verifyButton"], button.sc-nkuzb1-0, button:contains("Iniciar desafio")');
+          if(!b) return false;
+          b.scrollIntoView({behavior:'instant', block:'center'});
+          b.click();
+          return true;
+        })()`, &clicked).Do(c)
+			}),
+			chromedp.Sleep(1200*time.Millisecond),
+		)
+		err := r.waitUntil(ctx, r.cfg.Timeouts.Checkpoint, `
+      (()=>{
+        const stillChallenge = (()=>{
+          const href = location.href || "";
+          if (href.includes("/checkpoint/challenge/")) return true;
+          const h2 = document.querySelector('[data-theme="home.title"], h2.sc-1io4bok-0');
+          const btn = document.querySelector('[data-theme="home.verifyButton"]');
+          const txt = (h2?.textContent||"") + " " + (btn?.textContent||"");
+          return /Proteger a sua conta|Iniciar desafio/i.test(txt);
+        })();
+        if (stillChallenge) return false;
+        if (document.querySelector('input[placeholder*="Pesquisar"], input[placeholder*="Search"]')) return true;
+        if ((location.href||"").includes("/feed/")) return true;
+        return false;
+      })()
+    `, ui, "checkpoint")
+		if err != nil {
+			return errors.New("timeout aguardando resolução do challenge")
+		}
+	}
+
+	if has2FA(ctx) {
+		sum.Challenges = append(sum.Challenges, "2fa")
+		r.countMetric(events.MetricChallenges, "2fa", 1)
+		if headless && ui == nil && creds.TOTPSecret == "" {
+			return errors.New("2FA detectada em modo headless e a credencial não tem segredo TOTP; cadastre o TOTP ou rode com --headless=false ou --interactive para digitar o código")
+		}
+		solved := false
+		if creds.TOTPSecret != "" {
+			if err := r.fillTOTP(ctx, creds.TOTPSecret); err != nil {
+				slog.Warn("TOTP falhou", "err", err)
+			} else {
+				slog.Info("2FA resolvida com TOTP")
+				solved = true
+			}
+		}
+		if !solved {
+			if headless && ui == nil {
+				return errors.New("2FA não resolvida com TOTP em modo headless")
+			}
+			slog.Warn("2FA detectada; insira o código", "challenge", "2fa", "timeout", r.cfg.Timeouts.Challenge)
+			if err := r.waitDisappear(ctx, r.cfg.Timeouts.Challenge, otpSelector, ui, "2fa"); err != nil {
+				return errors.New("timeout aguardando 2FA")
+			}
+		}
+	}
+
+	return chromedp.Run(ctx,
+		chromedp.Navigate(feedURL),
+		chromedp.WaitReady(`body`, chromedp.ByQuery),
+	)
+}
+
+// isLoggedIn abre o feed e diz se a sessão do perfil do Chromium ainda vale.
+func isLoggedIn(ctx context.Context, feedURL string) bool {
+	var href string
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(feedURL),
+		chromedp.WaitReady(`body`, chromedp.ByQuery),
+		chromedp.Location(&href),
+	); err != nil {
+		return false
+	}
+	return strings.Contains(href, "/feed")
+}
+
+func isCheckpointChallenge(ctx context.Context) bool {
+	var on bool
+	_ = chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`(()=>{
+        const href = location.href || "";
+        if (href.includes("/checkpoint/challenge/")) return true;
+        const h2 = document.querySelector('[data-theme="home.title"], h2.sc-1io4bok-0');
+        const btn = document.querySelector('[data-theme="home.verifyButton"]');
+        const txt = (h2?.textContent || "") + " " + (btn?.textContent || "");
+        if (/Proteger a sua conta|Iniciar desafio/i.test(txt)) return true;
+        return false;
+      })()`, &on),
+	)
+	return on
+}
+
+func (r *run) waitUntil(ctx context.Context, timeout time.Duration, jsCond string, ui *challengeUI, kind string) error {
+	defer ui.end(kind)
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) && !r.stopRequested.Load() {
+		var ok bool
+		err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(jsCond, &ok))
+		if err == nil && ok {
+			return nil
+		}
+		ui.pause(ctx, kind, 1500*time.Millisecond)
+	}
+	return errors.New("timeout aguardando condição")
+}
+
+func isCaptcha(ctx context.Context) bool {
+	var n int
+	_ = chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`document.querySelectorAll('iframe[src*="captcha"], iframe[src*="challenge"]').length`, &n),
+	)
+	return n > 0
+}
+
+func has2FA(ctx context.Context) bool {
+	var n int
+	_ = chromedp.Run(ctx,
+		chromedp.EvaluateAsDevTools(`document.querySelectorAll('input[autocomplete="one-time-code"], input[name*="pin"]').length`, &n),
+	)
+	return n > 0
+}
+
+// fillTOTP digita o código do passo atual e, se o LinkedIn recusar (relógio
+// no limite do passo), tenta mais uma vez com o código do passo seguinte.
+func (r *run) fillTOTP(ctx context.Context, secret string) error {
+	first := time.Now()
+	for attempt := range 2 {
+		at := time.Now()
+		if attempt > 0 {
+			next := totp.NextStep(first)
+			if d := time.Until(next); d > 0 {
+				slog.Info("código TOTP recusado; tentando o próximo", "wait", d.Round(time.Second))
+				if err := chromedp.Run(ctx, chromedp.Sleep(d)); err != nil {
+					return err
+				}
+			}
+			at = next
+		}
+		code, err := totp.Code(secret, at)
+		if err != nil {
+			return err
+		}
+		if err := chromedp.Run(ctx,
+			chromedp.SetValue(otpSelector, "", chromedp.ByQuery),
+			chromedp.SendKeys(otpSelector, code+"\r", chromedp.ByQuery),
+		); err != nil {
+			return err
+		}
+		if r.waitDisappear(ctx, 15*time.Second, otpSelector, nil, "2fa") == nil {
+			return nil
+		}
+	}
+	return errors.New("código recusado duas vezes")
+}
+
+func (r *run) waitDisappear(ctx context.Context, timeout time.Duration, css string, ui *challengeUI, kind string) error {
+	defer ui.end(kind)
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) && !r.stopRequested.Load() {
+		var n int
+		err := chromedp.Run(ctx,
+			chromedp.EvaluateAsDevTools(fmt.Sprintf(`document.querySelectorAll(%q).length`, css), &n),
+		)
+		if err == nil && n == 0 {
+			return nil
+		}
+		ui.pause(ctx, kind, 2*time.Second)
+	}
+	return errors.New("timeout")
+}
+
+// =============== Desafios via UI web ===============
+
+const otpSelector = `input[autocomplete="one-time-code"], input[name*="pin"]`
+
+// challengeUI transmite a página durante um desafio para a UI web
+// (--interactive) e aplica os cliques e códigos 2FA que chegam pelo stdin.
+// Um *challengeUI nil é válido: só espera, como no modo noVNC.
+type challengeUI struct {
+	events *events.Writer
+	cmds   <-chan events.Command
+}
+
+func newChallengeUI(stdin *bufio.Reader, w *events.Writer) *challengeUI {
+	cmds := make(chan events.Command, 16)
+	go func() {
+		defer close(cmds)
+		for {
+			line, err := stdin.ReadString('\n')
+			if line = strings.TrimSpace(line); line != "" {
+				var c events.Command
+				if jerr := json.Unmarshal([]byte(line), &c); jerr != nil {
+					slog.Warn("comando inválido no stdin", "err", jerr)
+				} else {
+					cmds <- c
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return &challengeUI{events: w, cmds: cmds}
+}
+
+// pause espera d entre verificações do desafio. Com a UI ligada, manda um
+// screenshot e executa os comandos recebidos nesse intervalo.
+func (ui *challengeUI) pause(ctx context.Context, kind string, d time.Duration) {
+	if ui == nil {
+		time.Sleep(d)
+		return
+	}
+	ui.screenshot(ctx, kind)
+	t := time.NewTimer(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			return
+		case c, ok := <-ui.cmds:
+			if !ok {
+				ui.cmds = nil // stdin fechado: só espera
+				continue
+			}
+			ui.apply(ctx, c)
+		}
+	}
+}
+
+func (ui *challengeUI) end(kind string) {
+	if ui == nil {
+		return
+	}
+	_ = ui.events.Emit(events.Event{Type: events.TypeChallengeEnd, Kind: kind})
+}
+
+func (ui *challengeUI) screenshot(ctx context.Context, kind string) {
+	var buf []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(c context.Context) error {
+		var err error
+		buf, err = page.CaptureScreenshot().
+			WithFormat(page.CaptureScreenshotFormatJpeg).
+			WithQuality(60).
+			Do(c)
+		return err
+	}))
+	if err != nil {
+		slog.Warn("screenshot do desafio", "err", err)
+		return
+	}
+	_ = ui.events.Emit(events.Event{
+		Type:  events.TypeChallenge,
+		Kind:  kind,
+		Image: base64.StdEncoding.EncodeToString(buf),
+	})
+}
+
+func (ui *challengeUI) apply(ctx context.Context, c events.Command) {
+	switch c.Type {
+	case events.CmdClick:
+		var vp []float64
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`[window.innerWidth, window.innerHeight]`, &vp)); err != nil || len(vp) != 2 {
+			slog.Warn("clique da UI ignorado: viewport desconhecido")
+			return
+		}
+		x, y := c.X*vp[0], c.Y*vp[1]
+		slog.Info("clique da UI", "x", int(x), "y", int(y))
+		if err := chromedp.Run(ctx, chromedp.MouseClickXY(x, y)); err != nil {
+			slog.Warn("clique da UI falhou", "err", err)
+		}
+	case events.CmdCode:
+		code := strings.TrimSpace(c.Code)
+		slog.Info("código 2FA recebido da UI", "digits", len(code))
+		if err := chromedp.Run(ctx,
+			chromedp.SetValue(otpSelector, "", chromedp.ByQuery),
+			chromedp.SendKeys(otpSelector, code+"\r", chromedp.ByQuery),
+		); err != nil {
+			slog.Warn("preenchendo código 2FA", "err", err)
+		}
+	default:
+		slog.Warn("comando desconhecido da UI", "type", c.Type)
+	}
+}
+
+// =============== Busca via URL ===============
+
+// searchParams monta a query string da busca; geoUrn vai como lista JSON
+// (São Paulo: geoUrn=%5B%22105871508%22%5D).
+func searchParams(q string, geo []string) string {
+	v := url.Values{}
+	if len(geo) > 0 {
+		b, _ := json.Marshal(geo)
+		v.Set("geoUrn", string(b))
+	}
+	v.Set("keywords", q)
+	v.Set("origin", "FACETED_SEARCH")
+	return v.Encode()
+}
+
+// splitList separa uma lista por vírgulas, sem itens vazios.
+func splitList(s string) []string {
+	var out []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (r *run) runSearchViaURL(ctx context.Context, q string, geo []string) error {
+	params := searchParams(q, geo)
+	desktop := "https://www.linkedin.com/search/results/people/?" + params
+	mobile := "https://www.linkedin.com/m/search/results/people/?" + params
+	defer r.observeLoad("search", time.Now())
+
+	// tenta desktop
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(desktop),
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		waitDOMComplete(),
+		chromedp.Sleep(500*time.Millisecond),
+		//waitForCards(),
+		waitForResults(),
+	); err == nil {
+		return nil
+	}
+
+	return chromedp.Run(ctx,
+		chromedp.Navigate(mobile),
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		chromedp.Sleep(700*time.Millisecond),
+		//waitForCards(),
+		waitForResults(),
+	)
+}
+
+func waitDOMComplete() chromedp.Action {
+	return chromedp.EvaluateAsDevTools(`new Promise(r=>{
+        if (document.readyState==='complete') return r(true);
+        window.addEventListener('load', ()=>r(true), {once:true});
+    })`, nil)
+}
+
+func waitForCards() chromedp.Action {
+	js := `(async () => {
+	  const hasCards = () => {
+	    const sel = [
+	      "main [data-view-name='search-entity-result-universal-template'] a[href*='/in/']",
+	      "main [data-chameleon-result-urn] a[href*='/in/']",
+	      "div.search-results-container ul[role='list'] li a[href*='/in/']"
+	    ].join(", ");
+	    return document.querySelectorAll(sel).length > 0;
+	  };
+	  if (hasCards()) return true;
+	  return await new Promise(res => {
+	    const stop = () => { obs && obs.disconnect(); res(true); };
+	    const obs = new MutationObserver(() => { if (hasCards()) stop(); });
+	    obs.observe(document, {subtree:true, childList:true});
+	    setTimeout(() => { obs.disconnect(); res(hasCards()); }, 10000); // 10s hard cap
+	  });
+	})()`
+	return chromedp.EvaluateAsDevTools(js, nil)
+}
+
+// companyPopoverJS acha o popover do chip "Empresa atual" (aria-controls).
+const companyPopoverJS = `(() => {
+  const trigger = document.querySelector('#searchFilter_currentCompany');
+  if (!trigger) return null;
+  const popId = trigger.getAttribute('aria-controls');
+  return (popId && document.getElementById(popId)) || document.querySelector('.artdeco-hoverable-content--visible');
+})()`
+
+// companyOptionNameJS lê o nome da empresa do 1º item do chip (o span
+// visível; o label inteiro traz também o "Filtrar por ..." de leitor de tela).
+const companyOptionNameJS = `(() => {
+  const pop = ` + companyPopoverJS + `;
+  const li = pop && pop.querySelector('ul.search-reusables__collection-values-container > li');
+  if (!li) return '';
+  const span = li.querySelector('label span[aria-hidden="true"]') || li.querySelector('label') || li;
+  return (span.innerText || '').split('\n')[0].trim();
+})()`
+
+// applyFirstCurrentCompanyOption abre o chip "Empresa atual", marca o 1º item
+// e clica "Exibir resultados". Devolve o nome da empresa aplicada. Abrir o
+// chip não muda nada; marcar e aplicar passam por mutate, então em dry-run a
+// busca segue sem o filtro.
+func (r *run) applyFirstCurrentCompanyOption(ctx context.Context, query string) (string, error) {
+	var name string
+	if err := chromedp.Run(ctx,
+		chromedp.WaitVisible(`#searchFilter_currentCompany`, chromedp.ByQuery),
+		chromedp.ScrollIntoView(`#searchFilter_currentCompany`, chromedp.ByQuery),
+		chromedp.Click(`#searchFilter_currentCompany`, chromedp.ByQuery),
+		chromedp.Sleep(400*time.Millisecond),
+		chromedp.EvaluateAsDevTools(companyOptionNameJS, &name),
+	); err != nil {
+		return "", err
+	}
+
+	// 1º LI da lista de empresas; o label é o mais confiável de clicar
+	if _, err := r.mutate(ctx, action{
+		Name:     "company_option",
+		Target:   query,
+		Selector: `#searchFilter_currentCompany → ul.search-reusables__collection-values-container > li:first-child label`,
+		find: `(() => {
+		  const pop = ` + companyPopoverJS + `;
+		  const li = pop && pop.querySelector('ul.search-reusables__collection-values-container > li');
+		  return li ? (li.querySelector('label') || li) : null;
+		})()`,
+	}); err != nil {
+		return "", err
+	}
+	if _, err := r.mutate(ctx, action{
+		Name:     "company_apply",
+		Target:   query,
+		Selector: `#searchFilter_currentCompany → button "Exibir resultados" / aria-label "Aplicar filtro"`,
+		find: `(() => {
+		  const pop = ` + companyPopoverJS + `;
+		  return pop && Array.from(pop.querySelectorAll('button')).find(b =>
+		    /Exibir resultados/i.test(b.textContent || '') ||
+		    /Aplicar filtro/i.test(b.getAttribute('aria-label') || '')) || null;
+		})()`,
+	}); err != nil {
+		return "", err
+	}
+	if r.cfg.DryRun {
+		// fecha o popover sem aplicar
+		return name, chromedp.Run(ctx, chromedp.KeyEvent(kb.Escape))
+	}
+
+	// aguardar recarregar a lista
+	return name, chromedp.Run(ctx,
+		chromedp.Sleep(600*time.Millisecond),
+		waitForCards(),
+	)
+}
+
+func clickTwoFilterButtons(ctx context.Context) error {
+	selectors := []string{
+		`#search-reusables__filters-bar > ul > li:nth-child(5) > div > fieldset > ul > li:nth-child(2) > button`,
+		`#search-reusables__filters-bar > ul > li:nth-child(3) > div > fieldset > ul > li:nth-child(3) > button`,
+	}
+
+	for i, sel := range selectors {
+		// Wait up to 5s for the element to become visible
+		waitCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		if err := chromedp.Run(waitCtx,
+			chromedp.WaitVisible(sel, chromedp.ByQuery),
+		); err != nil {
+			cancel()
+			return fmt.Errorf("selector %d not visible within 5s: %s: %w", i+1, sel, err)
+		}
+		cancel()
+
+		// Scroll + click using the parent context
+		if err := chromedp.Run(ctx,
+			chromedp.Sleep(120*time.Millisecond),
+			chromedp.Click(sel, chromedp.ByQuery),
+		); err != nil {
+			return fmt.Errorf("failed clicking selector %d: %s: %w", i+1, sel, err)
+		}
+
+		// tiny pause between clicks
+		_ = chromedp.Run(ctx, chromedp.Sleep(250*time.Millisecond))
+	}
+
+	return nil
+}
+
+func waitForResults() chromedp.Action {
+	// qualquer contêiner típico de resultados serve
+	sel := `main .search-results-container,
+            main ul.reusable-search__entity-result-list,
+            main .reusable-search__entity-result-list,
+            main [data-view-name="search-entity-result-universal-template"],
+            main [data-chameleon-result-urn]`
+	return chromedp.WaitVisible(sel, chromedp.ByQuery)
+}
+
+// =============== Paginação ===============
+
+func (r *run) goNextPage(ctx context.Context) bool {
+	sel := `button[aria-label="Avançar"], a[aria-label="Avançar"]`
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := chromedp.Run(waitCtx,
+		chromedp.WaitVisible(sel, chromedp.ByQuery),
+	); err != nil {
+		return false
+	}
+	start := time.Now()
+	if err := chromedp.Run(ctx,
+		chromedp.Click(sel, chromedp.ByQuery),
+		waitForCards(),
+	); err != nil {
+		return false
+	}
+	r.observeLoad("next_page", start)
+	return true
+}
+
+// =============== Coleta ===============
+
+func (r *run) scrapeCurrentPage(ctx context.Context, sourceQuery string) ([]Profile, error) {
+	js := `(() => {
+	  const clean = s => (s || '').replace(/\u00a0/g,' ').replace(/\s+/g,' ').trim();
+	  const getText = el => el ? clean(el.textContent || "") : "";
+
+	  const looksLikeCity = (txt) => {
+	    if (!txt) return false;
+	    if (txt.includes(',')) return true;
+	    return /s\u00e3o paulo|sp|rio de janeiro|rj|lisboa|porto|belo horizonte|curitiba|brasil|brazil|london|new york/i.test(txt);
+	  };
+
+	  let cards = Array.from(document.querySelectorAll('main ul.reusable-search__entity-result-list > li'));
+	  if (cards.length === 0) {
+	    cards = Array.from(document.querySelectorAll('main [data-view-name="search-entity-result-universal-template"], main [data-chameleon-result-urn]'));
+	  }
+
+	  // repetidos saem em Go, para entrar no relatório de qualidade
+	  const out = [];
+
+	  for (const card of cards) {
+	    const isInsight = (el) => !!el.closest('.entity-result__insights, .reusable-search-simple-insight, .reusable-search-simple-insight__text-container');
+	    let a = null;
+	    const candidates = card.querySelectorAll('a[data-test-app-aware-link][href*="/in/"], a[href*="/in/"]');
+	    for (const cand of candidates) { if (!isInsight(cand)) { a = cand; break; } }
+	    if (!a) continue;
+
+	    let href = a.getAttribute('href') || '';
+	    try { const u = new URL(href, location.origin); href = u.origin + u.pathname; } catch {}
+	    if (!href.includes('/in/')) continue;
+
+	    let name = "";
+	    const hidden = a.querySelector('span[aria-hidden="true"]');
+	    name = getText(hidden) || getText(a);
+	    name = name.replace(/^O status est\u00e1 off-line/i, '').trim();
+
+	    let title = "";
+	    for (const sel of [
+	      '.entity-result__primary-subtitle',
+	      '.artdeco-entity-lockup__subtitle',
+	      '.linked-area div[dir="ltr"]:nth-of-type(2)',
+	      '.t-14.t-black.t-normal'
+	    ]) {
+	      const el = card.querySelector(sel);
+	      if (getText(el)) { title = getText(el); break; }
+	    }
+
+	    let location = "";
+	    const locNodes = Array.from(card.querySelectorAll('div.t-14.t-normal, .reusable-search-secondary-subtitle, .entity-result__secondary-subtitle'));
+	    for (const el of locNodes) {
+	      const txt = getText(el);
+	      if (!txt) continue;
+	      if (/conex\u00e3o.*grau/i.test(txt)) continue;
+	      if (looksLikeCity(txt)) { location = txt; break; }
+	    }
+
+	    // resumo e subtítulo vão crus: a empresa sai deles em Go (internal/company)
+	    const role = getText(card.querySelector('p.entity-result__summary--2-lines'));
+	    const caption = getText(card.querySelector('.entity-result__secondary-subtitle, .artdeco-entity-lockup__caption'));
+
+	    out.push({ name, title, location, role, caption, url: href });
+	  }
+
+	  return out;
+	})()`
+
+	var rows []map[string]string
+	start := time.Now()
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(js, &rows)); err != nil {
+		return nil, fmt.Errorf("falha extraindo resultados: %w", err)
+	}
+	r.observeLoad("scrape", start)
+	r.countMetric(events.MetricPages, "", 1)
+	r.tally.Pages++
+	if len(rows) == 0 {
+		r.countMetric(events.MetricEmptyPages, "", 1)
+		r.tally.EmptyPages++
+		return nil, errors.New("nenhum resultado encontrado na página (UI mudou ou bloqueio ativo)")
+	}
+
+	now := time.Now()
+	out := make([]Profile, 0, len(rows))
+	seen := map[string]bool{}
+	for _, row := range rows {
+		u := clean(row["url"])
+		if u == "" {
+			continue
+		}
+		if seen[u] {
+			r.tally.Duplicates++
+			continue
+		}
+		seen[u] = true
+
+		name := clean(row["name"])
+		title := clean(row["title"])
+		location := clean(row["location"])
+		role := clean(row["role"])
+
+		co := company.Extract(company.Input{Title: title, Summary: role, Caption: clean(row["caption"]), Location: location, Facet: r.companyFacet})
+		out = append(out, Profile{
+			Name:        name,
+			Title:       title,
+			Company:     co.Company,
+			Location:    location,
+			Role:        role,
+			URL:         u,
+			SourceQuery: sourceQuery,
+			CapturedAt:  now,
+
+			CompanySource:     co.Source,
+			CompanyConfidence: co.Confidence,
+		})
+	}
+	r.countMetric(events.MetricProfiles, "", len(out))
+	return out, nil
+}
+
+// =============== CSV ===============
+
+// WriteCSV grava os perfis no formato de results (com BOM para o Excel).
+func WriteCSV(path string, items []Profile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := results.WriteCSV(f, Rows(items)); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Rows converte os perfis para as linhas do CSV.
+func Rows(items []Profile) []results.Row {
+	rows := make([]results.Row, len(items))
+	for i, p := range items {
+		rows[i] = results.Row{
+			Name:        p.Name,
+			FirstName:   p.FirstName,
+			LastName:    p.LastName,
+			Title:       p.Title,
+			Company:     p.Company,
+			Location:    p.Location,
+			Role:        p.Role,
+			URL:         p.URL,
+			SourceQuery: p.SourceQuery,
+			CapturedAt:  p.CapturedAt.Format(results.TimeLayout),
+			Seniority:   p.Seniority,
+			Function:    p.Function,
+
+			CompanySource:     p.CompanySource,
+			CompanyConfidence: p.CompanyConfidence,
+		}
+	}
+	return rows
+}
+
+// =============== Helpers ===============
+
+// clickIfExists é só para cliques que não mudam nada (abrir menu, fechar
+// modal); os que mudam passam por mutate.
+func clickIfExists(sel string) chromedp.ActionFunc {
+	js := fmt.Sprintf(`(() => {
+		const el = document.querySelector(%q);
+		if (!el) return false;
+		el.scrollIntoView({behavior:'instant', block:'center'});
+		el.click();
+		return true;
+	})()`, sel)
+	return func(ctx context.Context) error {
+		var ok bool
+		return chromedp.EvaluateAsDevTools(js, &ok).Do(ctx)
+	}
+}
+
+func clean(s string) string {
+	return strings.TrimSpace(strings.ReplaceAll(s, "\u00a0", " "))
+}
+
+func sanitizeQuotes(s string) string {
+	repl := map[rune]rune{
+		'“': '"', '”': '"', '‟': '"', '〝': '"', '〞': '"',
+		'‘': '\'', '’': '\'', '‛': '\'', '‚': '\'', '‹': '\'', '›': '\'',
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if rr, ok := repl[r]; ok {
+			b.WriteRune(rr)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func dumpPageHTML(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var html string
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(`document.documentElement.outerHTML`, &html)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(html), 0o644)
+}
+
+// guessNameFromURL monta o nome a partir do slug de /in/<slug>.
+func guessNameFromURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	seg := u.Path
+	if idx := strings.Index(seg, "/in/"); idx >= 0 {
+		seg = seg[idx+len("/in/"):]
+	}
+	if j := strings.Index(seg, "/"); j >= 0 {
+		seg = seg[:j]
+	}
+	return names.FromSlug(seg)
+}
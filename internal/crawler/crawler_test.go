@@ -0,0 +1,26 @@
+package crawler
+
+import "testing"
+
+func TestTidyProfiles(t *testing.T) {
+	r := &run{}
+	items := []Profile{
+		{Name: "", Title: "Dev", Location: "Recife", URL: "https://www.linkedin.com/in/ana-souza-4b2a1/"},
+		{Name: "O status está off-line Bia Lima", Title: "SRE", Location: "sre", URL: "https://www.linkedin.com/in/bia/"},
+		{Name: "Caio Reis", Title: "Dev", Location: "Recife", URL: "https://www.linkedin.com/in/caio/"},
+	}
+	r.tidyProfiles(items)
+
+	if items[0].Name != "Ana Souza" || items[0].FirstName != "Ana" || items[0].LastName != "Souza" {
+		t.Errorf("nome da URL: %+v", items[0])
+	}
+	if items[1].Name != "Bia Lima" || items[1].Location != "" {
+		t.Errorf("status e região igual ao título: %+v", items[1])
+	}
+	if items[2].Location != "Recife" {
+		t.Errorf("região apagada sem repetir o título: %+v", items[2])
+	}
+	if r.tally.NameFromURL != 1 || r.tally.LocationIsTitle != 1 {
+		t.Errorf("tally = %+v", r.tally)
+	}
+}
@@ -0,0 +1,102 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// =============== Cliques que mudam algo ===============
+
+// action é um clique que muda algo no LinkedIn ou na busca (convite, filtro
+// aplicado). Todos passam por mutate: em dry-run (cfg.DryRun) o elemento só
+// é destacado, registrado no log e fotografado.
+type action struct {
+	Name     string // connect, send, company_option...
+	Target   string // perfil ou busca afetada
+	Selector string // como o elemento é achado (vai no log)
+	find     string // expressão JS que devolve o elemento ou null
+}
+
+// errNoElement: o elemento da ação não está na página.
+var errNoElement = errors.New("elemento não encontrado")
+
+// byQuery monta a ação de um seletor CSS.
+func byQuery(name, target, sel string) action {
+	return action{Name: name, Target: target, Selector: sel, find: fmt.Sprintf("document.querySelector(%q)", sel)}
+}
+
+// mutateJS rola até o elemento e clica, ou, em dry-run, só o contorna de
+// vermelho. Devolve o texto do botão.
+const mutateJS = `(() => {
+  const el = %s;
+  if (!el) return {found: false};
+  el.scrollIntoView({behavior:'instant', block:'center'});
+  const text = (el.getAttribute('aria-label') || el.innerText || '').replace(/\s+/g, ' ').trim();
+  if (%t) {
+    el.style.outline = '3px solid #e11d48';
+    el.style.outlineOffset = '2px';
+  } else {
+    el.click();
+  }
+  return {found: true, text};
+})()`
+
+// mutate executa a ação e devolve o texto do elemento clicado (ou que seria).
+func (r *run) mutate(ctx context.Context, a action) (string, error) {
+	var res struct {
+		Found bool   `json:"found"`
+		Text  string `json:"text"`
+	}
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(fmt.Sprintf(mutateJS, a.find, r.cfg.DryRun), &res)); err != nil {
+		return "", fmt.Errorf("%s: %w", a.Name, err)
+	}
+	if !res.Found {
+		return "", fmt.Errorf("%s: %w", a.Name, errNoElement)
+	}
+	if r.cfg.DryRun {
+		path, err := r.dryRunScreenshot(ctx, a.Name)
+		if err != nil {
+			slog.Warn("dry-run: screenshot falhou", "action", a.Name, "err", err)
+		}
+		slog.Info("dry-run: clicaria", "action", a.Name, "target", a.Target, "text", res.Text, "selector", a.Selector, "screenshot", path)
+	}
+	return res.Text, nil
+}
+
+// click é mutate como chromedp.Action para elementos opcionais (ausente não é erro).
+func (r *run) click(a action) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		_, err := r.mutate(ctx, a)
+		if errors.Is(err, errNoElement) {
+			return nil
+		}
+		return err
+	}
+}
+
+// dryRunScreenshot grava a página (com o destaque) em <saída>/dryrun/.
+func (r *run) dryRunScreenshot(ctx context.Context, name string) (string, error) {
+	var buf []byte
+	err := chromedp.Run(ctx, chromedp.ActionFunc(func(c context.Context) error {
+		var err error
+		buf, err = page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormatPng).Do(c)
+		return err
+	}))
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(r.cfg.Output.Dir, "dryrun")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	r.dryRunShots++
+	path := filepath.Join(dir, fmt.Sprintf("%03d_%s.png", r.dryRunShots, name))
+	return path, os.WriteFile(path, buf, 0o644)
+}
@@ -0,0 +1,501 @@
+package crawler
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/events"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/names"
+	"CrawlerLinkedin/internal/notes"
+	"CrawlerLinkedin/internal/results"
+	"CrawlerLinkedin/internal/summary"
+)
+
+// =============== Convites por lista ===============
+
+// Target é um perfil a convidar. Name é conferido com a página antes do
+// clique (sem ele o perfil não é convidado); os demais campos só servem
+// para a nota.
+type Target struct {
+	URL      string
+	Name     string
+	Title    string
+	Company  string
+	Location string
+}
+
+// NoteFields são os campos da nota do perfil; sem nome na lista, vale o da
+// página (found).
+func (t Target) NoteFields(found string) notes.Fields {
+	name := t.Name
+	if name == "" {
+		name = found
+	}
+	return notes.FromProfile(name, t.Title, t.Company, t.Location)
+}
+
+// Resultado de cada convite.
+const (
+	InviteSent         = "sent"
+	InvitePending      = "pending"                // já havia convite pendente
+	InviteConnected    = "connected"              // já é conexão
+	InviteNameMismatch = "name_mismatch"          // o perfil aberto não é quem a lista diz
+	InviteUnconfirmed  = "unconfirmed"            // sem nome na lista, não dá para conferir; nem abre
+	InviteNoButton     = "no_button"              // sem "Conectar" (nem em "Mais")
+	InviteNoteError    = "note_error"             // a nota não fecha (longa demais); nada é clicado
+	InviteSuppressed   = "suppressed"             // na lista de não contatar ou já convidado; nem abre
+	InviteDryRun       = "dry_run"                // dry-run: chegou ao "Conectar", sem clicar
+	InviteWeeklyLimit  = ledger.StatusWeeklyLimit // o LinkedIn avisou do limite semanal; a campanha para
+	InviteFailed       = "failed"
+)
+
+// ErrWeeklyLimit: o LinkedIn recusou convites pelo resto da semana.
+var ErrWeeklyLimit = errors.New("o LinkedIn avisou do limite semanal de convites; campanha interrompida")
+
+const addNoteSel = `button[aria-label*="Adicionar nota"], button[aria-label*="Add a note"]`
+
+// InviteResult é o desfecho de um perfil da lista.
+type InviteResult struct {
+	URL          string
+	ExpectedName string
+	FoundName    string
+	Status       string
+	Detail       string
+	Note         string // nota enviada; vazio = sem nota
+	At           time.Time
+}
+
+// ReadTargets lê a lista revisada: um CSV do crawler (colunas url e name; as
+// linhas apagadas na revisão ficam de fora) ou um texto com a URL e o nome
+// por linha ("https://www.linkedin.com/in/ana Ana Souza"; # comenta).
+// Repetidos e URLs que não são de perfil são descartados.
+func ReadTargets(path string) ([]Target, error) {
+	var raw []Target
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		rows, err := results.ReadCSV(path, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range rows {
+			raw = append(raw, Target{
+				URL:      strings.TrimSpace(r.URL),
+				Name:     strings.TrimSpace(r.Name),
+				Title:    r.Title,
+				Company:  r.Company,
+				Location: r.Location,
+			})
+		}
+	} else {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, "#") {
+				url, name, _ := strings.Cut(line, " ")
+				raw = append(raw, Target{URL: url, Name: strings.TrimSpace(name)})
+			}
+		}
+	}
+	var out []Target
+	seen := map[string]bool{}
+	for _, t := range raw {
+		k := results.Key(results.Row{URL: t.URL})
+		if !strings.Contains(k, "linkedin.com/in/") || seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, t)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: nenhuma URL de perfil (linkedin.com/in/...)", path)
+	}
+	return out, nil
+}
+
+// Invite faz login e convida os perfis de targets, na ordem, até
+// c.Defaults.MaxInvites enviados. Grava o relatório invites_*.csv e o resumo
+// em c.Output.Dir.
+func Invite(c config.Config, opts Options, targets []Target) ([]InviteResult, error) {
+	r, ui, err := setup(c, opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeouts.Run)
+	defer cancel()
+	defer r.handleStopSignals(cancel)()
+
+	sum := summary.Summary{DryRun: r.cfg.DryRun, StartedAt: time.Now()}
+	defer func() {
+		sum.EndedAt = time.Now()
+		if err := summary.Write(r.cfg.Output.Dir, sum); err != nil {
+			slog.Warn("gravando resumo", "err", err)
+		}
+	}()
+	bctx, stopBrowser, err := r.openSession(ctx, opts, &sum, ui)
+	if err != nil {
+		sum.Error = err.Error()
+		return nil, err
+	}
+	defer stopBrowser()
+
+	res, err := r.inviteAll(bctx, targets, opts)
+	sum.Invites = CountSent(res)
+	sum.InviteLimit = errors.Is(err, ErrWeeklyLimit)
+	sum.Cancelled = r.stopRequested.Load()
+	if err != nil {
+		sum.Error = err.Error()
+	}
+	return res, err
+}
+
+// CountSent conta os convites enviados.
+func CountSent(res []InviteResult) int {
+	n := 0
+	for _, r := range res {
+		if r.Status == InviteSent {
+			n++
+		}
+	}
+	return n
+}
+
+// inviteAll abre cada perfil, confere o nome e convida (com opts.Note, se
+// houver), até cfg.Defaults.MaxInvites enviados. Antes de abrir, consulta a
+// lista de supressão e o histórico (quem já foi convidado fica de fora);
+// cada tentativa entra no histórico (as de dry-run, não). As cotas da conta
+// (cfg.Invites), contadas no histórico, reduzem o máximo ou recusam a
+// execução (ledger.ErrQuota); o aviso de limite semanal do LinkedIn para tudo
+// (ErrWeeklyLimit). O relatório sai em cfg.Output.Dir mesmo se parar no meio.
+func (r *run) inviteAll(ctx context.Context, targets []Target, opts Options) ([]InviteResult, error) {
+	check, err := ledger.Load(r.cfg.Invites.Ledger, r.cfg.Invites.Suppress)
+	if err != nil {
+		return nil, err
+	}
+	account := opts.Account
+	if account == "" {
+		account = opts.Credentials.Email
+	}
+	max := r.cfg.Defaults.MaxInvites
+	left, err := ledger.Quota{Daily: r.cfg.Invites.DailyQuota, Weekly: r.cfg.Invites.WeeklyQuota}.Remaining(check.Usage(account, time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("conta %s: %w", account, err)
+	}
+	if left >= 0 && left < max {
+		slog.Info("a cota da conta limita esta execução", "account", account, "max", left)
+		max = left
+	}
+
+	var res []InviteResult
+	defer func() {
+		if len(res) == 0 {
+			return
+		}
+		path := filepath.Join(r.cfg.Output.Dir, fmt.Sprintf("invites_%s.csv", time.Now().Format("20060102_150405")))
+		if err := writeInviteReport(path, res); err != nil {
+			slog.Warn("gravando relatório de convites", "err", err)
+		} else {
+			slog.Info("relatório de convites salvo", "path", path)
+		}
+	}()
+
+	sent, opened := 0, 0
+	for i, t := range targets {
+		if sent >= max {
+			slog.Info("limite de convites atingido", "max", max, "restantes", len(targets)-i)
+			break
+		}
+		if r.stopRequested.Load() || ctx.Err() != nil {
+			slog.Warn("cancelado: convites interrompidos", "restantes", len(targets)-i)
+			break
+		}
+		if reason, ok := check.Suppressed(t.URL, t.Company); ok {
+			res = append(res, InviteResult{URL: t.URL, ExpectedName: t.Name, Status: InviteSuppressed, Detail: reason, At: time.Now()})
+			slog.Info("convite pulado", "url", t.URL, "name", t.Name, "detail", reason)
+			continue
+		}
+		if t.Name == "" {
+			// sem nome não há como confirmar que a página é da pessoa revisada
+			res = append(res, InviteResult{URL: t.URL, Status: InviteUnconfirmed, Detail: "sem nome na lista", At: time.Now()})
+			slog.Warn("convite pulado: sem nome na lista para conferir o perfil", "url", t.URL)
+			continue
+		}
+		if opened > 0 {
+			time.Sleep(r.cfg.Throttle.InviteDelay.Rand())
+		}
+		opened++
+		ir := r.inviteOne(ctx, t, opts.Note)
+		res = append(res, ir)
+		slog.Info("convite", "url", ir.URL, "status", ir.Status, "name", ir.FoundName, "detail", ir.Detail, "note", ir.Note)
+		if ir.Status == InviteSent {
+			sent++
+			r.countMetric(events.MetricInvites, "", 1)
+		}
+		if ir.Status == InviteDryRun {
+			sent++ // o limite vale como se tivesse enviado
+			continue
+		}
+
+		e := ledger.Entry{
+			At:      ir.At,
+			Account: account,
+			URL:     ir.URL,
+			Name:    cmp.Or(ir.FoundName, ir.ExpectedName),
+			Company: t.Company,
+			Status:  ir.Status,
+			Detail:  ir.Detail,
+			Note:    ir.Note,
+			JobID:   opts.JobID,
+		}
+		check.Add(e)
+		if err := ledger.Append(r.cfg.Invites.Ledger, e); err != nil {
+			// sem histórico a próxima execução convidaria de novo: melhor parar
+			return res, fmt.Errorf("gravando histórico de convites: %w", err)
+		}
+		if ir.Status == InviteWeeklyLimit {
+			// insistir arrisca a conta; o histórico trava as próximas execuções por uma semana
+			return res, ErrWeeklyLimit
+		}
+	}
+	return res, nil
+}
+
+// estado do botão de conexão no topo do perfil
+const (
+	stateConnect   = "connect"
+	statePending   = "pending"
+	stateConnected = "connected"
+	stateNone      = "none"
+)
+
+// profileStateJS lê o nome e o estado do botão de conexão no topo do perfil.
+// O "Conectar" pode estar exposto ou dentro do menu "Mais" (já aberto).
+const profileStateJS = `(() => {
+  const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+  const top = document.querySelector('main section') || document;
+  const name = clean(document.querySelector('main h1')?.textContent);
+  const label = b => clean(b.getAttribute('aria-label') || b.innerText);
+  const btns = Array.from(top.querySelectorAll('button, [role="button"]'));
+  if (btns.some(b => /^(pendente|pending)\b/i.test(label(b)) || /(retirar|withdraw).*(convite|invitation)/i.test(label(b)))) return {name, state: 'pending'};
+  if (btns.some(b => /(convidar|invite).*(conectar|connect)/i.test(label(b)))) return {name, state: 'connect'};
+  if (btns.some(b => /(remover conex|remove connection)/i.test(label(b)))) return {name, state: 'connected'};
+  if (/^1/.test(clean(top.querySelector('.dist-value')?.textContent))) return {name, state: 'connected'};
+  return {name, state: 'none'};
+})()`
+
+type profileState struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+func readProfileState(ctx context.Context) (profileState, error) {
+	var st profileState
+	err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(profileStateJS, &st))
+	return st, err
+}
+
+// inviteOne convida um perfil e diz o que aconteceu.
+func (r *run) inviteOne(ctx context.Context, t Target, note *notes.Template) InviteResult {
+	res := InviteResult{URL: t.URL, ExpectedName: t.Name, At: time.Now()}
+	fail := func(status, format string, args ...any) InviteResult {
+		res.Status, res.Detail = status, fmt.Sprintf(format, args...)
+		return res
+	}
+
+	start := time.Now()
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(t.URL),
+		chromedp.WaitVisible(`main h1`, chromedp.ByQuery),
+	); err != nil {
+		return fail(InviteFailed, "abrindo perfil: %v", err)
+	}
+	r.observeLoad("profile", start)
+
+	st, err := readProfileState(ctx)
+	if err != nil {
+		return fail(InviteFailed, "lendo perfil: %v", err)
+	}
+	res.FoundName = st.Name
+	if !namesMatch(t.Name, st.Name) {
+		return fail(InviteNameMismatch, "esperado %q", t.Name)
+	}
+	if st.State == stateNone {
+		// "Conectar" escondido no menu "Mais"
+		_ = chromedp.Run(ctx,
+			clickIfExists(`main section button[aria-label="Mais ações"], main section button[aria-label="More actions"]`),
+			chromedp.Sleep(600*time.Millisecond),
+		)
+		if st, err = readProfileState(ctx); err != nil {
+			return fail(InviteFailed, "lendo menu: %v", err)
+		}
+	}
+	switch st.State {
+	case statePending:
+		return fail(InvitePending, "convite já pendente")
+	case stateConnected:
+		return fail(InviteConnected, "já é conexão")
+	case stateNone:
+		return fail(InviteNoButton, "sem botão Conectar")
+	}
+
+	if note != nil {
+		// monta antes de clicar: nota que não fecha não vira convite sem nota
+		text, err := note.Render(t.NoteFields(st.Name))
+		if err != nil {
+			return fail(InviteNoteError, "%v", err)
+		}
+		res.Note = text
+	}
+
+	text, err := r.clickConnect(ctx, t.URL)
+	if err != nil {
+		return fail(InviteFailed, "%v", err)
+	}
+	if r.cfg.DryRun {
+		// sem o clique não abre o modal: "Adicionar nota" e "Enviar" ficam de fora
+		return fail(InviteDryRun, "clicaria %q", text)
+	}
+	// o aviso de limite semanal aparece no lugar do modal do convite
+	_ = chromedp.Run(ctx, chromedp.Sleep(700*time.Millisecond))
+	if weeklyLimit(ctx) {
+		return fail(InviteWeeklyLimit, "o LinkedIn avisou do limite semanal de convites")
+	}
+	if res.Note != "" {
+		if err := chromedp.Run(ctx,
+			chromedp.WaitVisible(addNoteSel, chromedp.ByQuery),
+			r.click(byQuery("add_note", t.URL, addNoteSel)),
+			chromedp.WaitVisible(`textarea[name="message"], textarea#custom-message`, chromedp.ByQuery),
+			chromedp.SendKeys(`textarea[name="message"], textarea#custom-message`, res.Note, chromedp.ByQuery),
+			chromedp.Sleep(500*time.Millisecond),
+		); err != nil {
+			_ = chromedp.Run(ctx, clickIfExists(`button[aria-label="Fechar"], button[aria-label="Dismiss"]`))
+			return fail(InviteFailed, "escrevendo a nota: %v", err)
+		}
+	} else {
+		_ = chromedp.Run(ctx,
+			r.click(byQuery("send_without_note", t.URL, `button[aria-label*="Enviar sem nota"], button[aria-label*="Send without a note"]`)),
+			chromedp.Sleep(300*time.Millisecond),
+		)
+	}
+	_ = chromedp.Run(ctx,
+		r.click(byQuery("send", t.URL, `button[aria-label="Enviar agora"], button[aria-label="Send now"], button[aria-label="Enviar convite"], button[aria-label="Send invitation"], button[aria-label="Enviar"], button[aria-label="Send"]`)),
+		chromedp.Sleep(1200*time.Millisecond),
+	)
+
+	if weeklyLimit(ctx) {
+		return fail(InviteWeeklyLimit, "o LinkedIn avisou do limite semanal de convites ao enviar")
+	}
+	// confirma: o botão passa a "Pendente"
+	if st, err = readProfileState(ctx); err == nil && st.State == statePending {
+		res.Status = InviteSent
+		return res
+	}
+	return fail(InviteFailed, "convite não confirmado (botão não ficou pendente)")
+}
+
+// weeklyLimitJS procura o modal do LinkedIn de limite semanal de convites.
+const weeklyLimitJS = `(() => {
+  const text = Array.from(document.querySelectorAll('[role="dialog"], [role="alertdialog"], .artdeco-modal'))
+    .map(d => d.innerText || '').join(' ');
+  return /limite semanal de convites|atingiu o limite semanal|weekly invitation limit|reached the weekly limit|out of invitations/i.test(text);
+})()`
+
+// weeklyLimit diz se o aviso de limite semanal está aberto; se estiver, fecha.
+func weeklyLimit(ctx context.Context) bool {
+	var hit bool
+	if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(weeklyLimitJS, &hit)); err != nil || !hit {
+		return false
+	}
+	_ = chromedp.Run(ctx, clickIfExists(`[role="dialog"] button[aria-label="Fechar"], [role="dialog"] button[aria-label="Dismiss"], [role="alertdialog"] button`))
+	return true
+}
+
+// connectJS acha o "Conectar" do topo do perfil (exposto ou no menu aberto).
+const connectJS = `(() => {
+  const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+  const top = document.querySelector('main section') || document;
+  return Array.from(top.querySelectorAll('button, [role="button"]'))
+    .find(b => /(convidar|invite).*(conectar|connect)/i.test(clean(b.getAttribute('aria-label') || b.innerText))) || null;
+})()`
+
+// clickConnect clica no "Conectar" do perfil url e devolve o texto do botão.
+func (r *run) clickConnect(ctx context.Context, url string) (string, error) {
+	text, err := r.mutate(ctx, action{
+		Name:     "connect",
+		Target:   url,
+		Selector: `main section button[aria-label~="Convidar … para se conectar" | "Invite … to connect"]`,
+		find:     connectJS,
+	})
+	if errors.Is(err, errNoElement) {
+		return "", errors.New("botão Conectar sumiu")
+	}
+	if err != nil {
+		return "", fmt.Errorf("clicando em Conectar: %w", err)
+	}
+	return text, nil
+}
+
+// namesMatch confere o nome da lista com o do perfil sem ligar para
+// maiúsculas, acentos, pontuação, títulos ou credenciais ("Ana Souza" casa
+// com "Dra. Ana Souza, PhD"): o primeiro nome tem de bater e algum sobrenome
+// também. Só o primeiro nome basta quando nenhum dos dois tem sobrenome ("Ana"
+// não casa com "Ana Souza").
+func namesMatch(expected, found string) bool {
+	a, b := nameTokens(expected), nameTokens(found)
+	if len(a) == 0 || len(b) == 0 || a[0] != b[0] {
+		return false
+	}
+	if len(a) == 1 || len(b) == 1 {
+		return len(a) == len(b)
+	}
+	for _, x := range a[1:] {
+		for _, y := range b[1:] {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nameTokens(s string) []string {
+	s = results.Fold(names.Normalize(s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+}
+
+// writeInviteReport grava um CSV com o desfecho de cada perfil.
+func writeInviteReport(path string, res []InviteResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	_ = w.Write([]string{"url", "expected_name", "found_name", "status", "detail", "note", "at"})
+	for _, r := range res {
+		_ = w.Write([]string{r.URL, r.ExpectedName, r.FoundName, r.Status, r.Detail, r.Note, r.At.Format(results.TimeLayout)})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,94 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"CrawlerLinkedin/internal/ledger"
+)
+
+func TestNamesMatch(t *testing.T) {
+	cases := []struct {
+		expected, found string
+		want            bool
+	}{
+		{"Ana Souza", "Ana Souza", true},
+		{"ana souza", "Ana Souza, PhD", true},
+		{"João Conceição", "Joao Conceicao", true},
+		{"Ana Souza", "Ana Maria Souza", true},
+		{"Ana", "Ana Souza", false},
+		{"Ana Souza", "Ana", false},
+		{"Ana", "ana", true},
+		{"Ana Souza", "Ana Lima", false},
+		{"Ana Souza", "Bia Souza", false},
+		{"Ana Souza", "", false},
+		{"Ana Souza", "Dra. Ana Souza 🚀", true},
+		{"Ana Souza", "O status está off-line Ana Souza", true},
+	}
+	for _, c := range cases {
+		if got := namesMatch(c.expected, c.found); got != c.want {
+			t.Errorf("namesMatch(%q, %q) = %v, quero %v", c.expected, c.found, got, c.want)
+		}
+	}
+}
+
+func TestReadTargets(t *testing.T) {
+	dir := t.TempDir()
+	txt := filepath.Join(dir, "lista.txt")
+	os.WriteFile(txt, []byte("# revisada\nhttps://www.linkedin.com/in/ana/ Ana Souza\n\nhttps://www.linkedin.com/in/ana?x=1\nhttps://www.linkedin.com/company/acme\nhttps://www.linkedin.com/in/bia\n"), 0o600)
+	got, err := ReadTargets(txt)
+	if err != nil || len(got) != 2 || got[0].URL != "https://www.linkedin.com/in/ana/" || got[1].URL != "https://www.linkedin.com/in/bia" {
+		t.Errorf("txt = %+v, %v", got, err)
+	}
+	if len(got) == 2 && (got[0].Name != "Ana Souza" || got[1].Name != "") {
+		t.Errorf("nomes = %q, %q", got[0].Name, got[1].Name)
+	}
+
+	csvPath := filepath.Join(dir, "lista.csv")
+	os.WriteFile(csvPath, []byte("name,url\nAna Souza,https://www.linkedin.com/in/ana\n"), 0o600)
+	got, err = ReadTargets(csvPath)
+	if err != nil || len(got) != 1 || got[0].Name != "Ana Souza" {
+		t.Errorf("csv = %+v, %v", got, err)
+	}
+
+	empty := filepath.Join(dir, "vazia.txt")
+	os.WriteFile(empty, []byte("# nada\n"), 0o600)
+	if _, err := ReadTargets(empty); err == nil {
+		t.Error("lista sem perfis deveria dar erro")
+	}
+}
+
+func TestSyncMatcher(t *testing.T) {
+	open := []ledger.Open{
+		{Entry: ledger.Entry{URL: "https://www.linkedin.com/in/ana-souza/", Name: "Ana Souza"}},
+		{Entry: ledger.Entry{URL: "https://www.linkedin.com/in/bia-lima", Name: "Bia Lima"}},
+		{Entry: ledger.Entry{URL: "https://www.linkedin.com/in/bia-lima-2", Name: "Bia Lima"}},
+		{Entry: ledger.Entry{URL: "https://www.linkedin.com/in/joao", Name: "João Conceição"}},
+	}
+	m := newSyncMatcher(open)
+	cases := []struct {
+		item  listItem
+		want  int
+		exact bool // casou pela URL (findURL também acha)
+		ok    bool
+	}{
+		{listItem{URL: "https://www.linkedin.com/in/ana-souza?miniProfileUrn=x"}, 0, true, true},
+		{listItem{URL: "https://www.linkedin.com/in/bia-lima-2/", Name: "Bia Lima"}, 2, true, true},
+		{listItem{URL: "https://www.linkedin.com/in/ACoAAB123", Name: "joao conceicao"}, 3, false, true},
+		{listItem{URL: "https://www.linkedin.com/in/ACoAAB456", Name: "Bia Lima"}, 0, false, false},
+		{listItem{URL: "https://www.linkedin.com/in/outro", Name: "Outro Nome"}, 0, false, false},
+		// homônimo de um convite em aberto, com outra URL: nas conexões não casa
+		{listItem{URL: "https://www.linkedin.com/in/ana-souza-sp", Name: "Ana Souza"}, 0, false, true},
+	}
+	for _, c := range cases {
+		i, exact, ok := m.find(c.item)
+		if ok != c.ok || (ok && (i != c.want || exact != c.exact)) {
+			t.Errorf("find(%+v) = %d, %v, %v; quero %d, %v, %v", c.item, i, exact, ok, c.want, c.exact, c.ok)
+		}
+		i, ok = m.findURL(c.item)
+		if ok != c.exact || (ok && i != c.want) {
+			t.Errorf("findURL(%+v) = %d, %v; quero %d, %v", c.item, i, ok, c.want, c.exact)
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package crawler
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chromedp/chromedp"
+
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/results"
+	"CrawlerLinkedin/internal/summary"
+)
+
+// =============== invites sync ===============
+
+const (
+	sentInvitesURL = "https://www.linkedin.com/mynetwork/invitation-manager/sent/"
+	connectionsURL = "https://www.linkedin.com/mynetwork/invite-connect/connections/"
+
+	listRounds        = 40 // rolagens por lista, no máximo
+	syncProfileChecks = 30 // perfis abertos para tirar dúvida, no máximo
+)
+
+// listItem é um cartão das listas de convites enviados e de conexões.
+type listItem struct {
+	URL  string `json:"url"`
+	Name string `json:"name"`
+	When string `json:"when"` // "Enviado há 2 semanas", "Conectado há 3 dias"...
+}
+
+// listItemsJS lê os cartões com link de perfil (só o mais interno de cada).
+const listItemsJS = `(() => {
+  const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+  const out = [];
+  for (const li of document.querySelectorAll('main li')) {
+    const a = li.querySelector('a[href*="/in/"]');
+    if (!a || li.querySelector('li a[href*="/in/"]')) continue;
+    const title = li.querySelector('.invitation-card__title, .mn-connection-card__name, .artdeco-entity-lockup__title');
+    out.push({
+      url: a.href.split('?')[0],
+      name: clean((title || a).innerText),
+      when: clean(li.querySelector('time, .time-badge')?.innerText),
+    });
+  }
+  return out;
+})()`
+
+// listMoreJS rola até o fim e clica em "Mostrar mais", se houver (só carrega a lista).
+const listMoreJS = `(() => {
+  window.scrollTo(0, document.body.scrollHeight);
+  const b = Array.from(document.querySelectorAll('main button'))
+    .find(b => /^(mostrar mais|exibir mais|ver mais|show more|load more)/i.test((b.innerText || '').trim()));
+  if (b) b.click();
+  return !!b;
+})()`
+
+// loadList abre a lista e rola até o fim, ou até done dizer que basta.
+// complete diz se chegou ao fim (ou a done).
+func (r *run) loadList(ctx context.Context, url string, done func([]listItem) bool) ([]listItem, bool, error) {
+	start := time.Now()
+	if err := chromedp.Run(ctx,
+		chromedp.Navigate(url),
+		chromedp.WaitVisible(`main`, chromedp.ByQuery),
+		chromedp.Sleep(1500*time.Millisecond),
+	); err != nil {
+		return nil, false, err
+	}
+	r.observeLoad("list", start)
+
+	var items []listItem
+	prev, stable := -1, 0
+	for i := 0; i < listRounds && !r.stopRequested.Load(); i++ {
+		if err := chromedp.Run(ctx, chromedp.EvaluateAsDevTools(listItemsJS, &items)); err != nil {
+			return items, false, err
+		}
+		if done != nil && done(items) {
+			return items, true, nil
+		}
+		// duas rolagens sem cartão novo: fim da lista
+		if len(items) == prev {
+			if stable++; stable == 2 {
+				return items, true, nil
+			}
+		} else {
+			stable = 0
+		}
+		prev = len(items)
+		var more bool
+		_ = chromedp.Run(ctx,
+			chromedp.EvaluateAsDevTools(listMoreJS, &more),
+			chromedp.Sleep(1500*time.Millisecond),
+		)
+	}
+	return items, false, nil
+}
+
+// syncMatcher acha o convite em aberto de um cartão: pela URL ou, na lista de
+// convites enviados (que às vezes usa outro formato de link), pelo nome (só
+// se não houver dois iguais entre os em aberto). Nas conexões, só pela URL:
+// lá pode estar qualquer pessoa com o mesmo nome.
+type syncMatcher struct {
+	byURL  map[string]int
+	byName map[string]int // -1 = nome repetido
+}
+
+func newSyncMatcher(open []ledger.Open) syncMatcher {
+	m := syncMatcher{byURL: map[string]int{}, byName: map[string]int{}}
+	for i, o := range open {
+		m.byURL[results.Key(results.Row{URL: o.URL})] = i
+		if n := results.Fold(o.Name); n != "" {
+			if _, dup := m.byName[n]; dup {
+				m.byName[n] = -1
+			} else {
+				m.byName[n] = i
+			}
+		}
+	}
+	return m
+}
+
+// find casa pela URL ou pelo nome; exact diz se foi pela URL.
+func (m syncMatcher) find(it listItem) (i int, exact, ok bool) {
+	if i, ok := m.findURL(it); ok {
+		return i, true, true
+	}
+	i, ok = m.byName[results.Fold(it.Name)]
+	return i, false, ok && i >= 0
+}
+
+// findURL casa só pela URL.
+func (m syncMatcher) findURL(it listItem) (int, bool) {
+	i, ok := m.byURL[results.Key(results.Row{URL: it.URL})]
+	return i, ok
+}
+
+// Sync confere os convites em aberto da conta (último estado sent ou pending
+// no histórico): quem segue em "Convites enviados" está pendente (e é
+// retirado se foi enviado há mais de cfg.Invites.WithdrawAfterDays, até
+// maxWithdraw); quem aparece nas conexões aceitou; o resto é conferido no
+// perfil. Cada mudança entra no histórico e no relatório
+// invites_sync_*.csv em c.Output.Dir.
+func Sync(c config.Config, opts Options, maxWithdraw int) ([]ledger.Entry, error) {
+	r, ui, err := setup(c, opts)
+	if err != nil {
+		return nil, err
+	}
+	history, err := ledger.Read(r.cfg.Invites.Ledger)
+	if err != nil {
+		return nil, fmt.Errorf("histórico de convites: %w", err)
+	}
+	account := cmp.Or(opts.Account, opts.Credentials.Email)
+	open := ledger.Outstanding(history, account)
+	if len(open) == 0 {
+		slog.Info("nenhum convite em aberto no histórico", "account", account)
+		return nil, nil
+	}
+	slog.Info("conferindo convites em aberto", "account", account, "open", len(open))
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeouts.Run)
+	defer cancel()
+	defer r.handleStopSignals(cancel)()
+
+	sum := summary.Summary{DryRun: r.cfg.DryRun, StartedAt: time.Now()}
+	var changes []ledger.Entry
+	defer func() {
+		sum.EndedAt = time.Now()
+		sum.Cancelled = r.stopRequested.Load()
+		if err := summary.Write(r.cfg.Output.Dir, sum); err != nil {
+			slog.Warn("gravando resumo", "err", err)
+		}
+		if len(changes) == 0 {
+			return
+		}
+		path := filepath.Join(r.cfg.Output.Dir, fmt.Sprintf("invites_sync_%s.csv", time.Now().Format("20060102_150405")))
+		if err := writeSyncReport(path, changes); err != nil {
+			slog.Warn("gravando relatório do sync", "err", err)
+		} else {
+			slog.Info("relatório do sync salvo", "path", path)
+		}
+	}()
+
+	bctx, stopBrowser, err := r.openSession(ctx, opts, &sum, ui)
+	if err != nil {
+		sum.Error = err.Error()
+		return nil, err
+	}
+	defer stopBrowser()
+
+	record := func(o ledger.Open, status, detail string) error {
+		e := ledger.Entry{
+			At:      time.Now(),
+			Account: account,
+			URL:     o.URL,
+			Name:    o.Name,
+			Company: o.Company,
+			Status:  status,
+			Detail:  detail,
+			JobID:   opts.JobID,
+		}
+		slog.Info("convite", "url", e.URL, "name", e.Name, "before", o.Status, "status", status, "detail", detail)
+		changes = append(changes, e)
+		return ledger.Append(r.cfg.Invites.Ledger, e)
+	}
+
+	m := newSyncMatcher(open)
+	done := make([]bool, len(open)) // já tem estado nesta execução
+
+	// 1) convites enviados: o que está lá segue pendente
+	sent, complete, err := r.loadList(bctx, sentInvitesURL, nil)
+	if err != nil {
+		sum.Error = err.Error()
+		return changes, fmt.Errorf("lendo convites enviados: %w", err)
+	}
+	slog.Info("convites enviados lidos", "cards", len(sent), "complete", complete)
+	var pending []int
+	pendingURL := map[int]string{} // só os casados pela URL podem ser retirados
+	for _, it := range sent {
+		i, exact, ok := m.find(it)
+		if !ok || done[i] {
+			continue
+		}
+		done[i] = true
+		pending = append(pending, i)
+		if exact {
+			pendingURL[i] = it.URL
+		}
+		if open[i].Status != "pending" {
+			if err := record(open[i], "pending", it.When); err != nil {
+				return changes, err
+			}
+		}
+	}
+
+	// 2) retira os pendentes velhos
+	if days := r.cfg.Invites.WithdrawAfterDays; days > 0 {
+		cutoff, n := time.Now().AddDate(0, 0, -days), 0
+		for _, i := range pending {
+			if open[i].SentAt.After(cutoff) || r.stopRequested.Load() {
+				continue
+			}
+			if pendingURL[i] == "" {
+				slog.Info("convite velho casado só pelo nome: não retiro", "url", open[i].URL, "name", open[i].Name)
+				continue
+			}
+			if n >= maxWithdraw {
+				slog.Info("limite de convites retirados atingido", "max", maxWithdraw)
+				break
+			}
+			n++
+			if err := r.withdrawInvite(bctx, pendingURL[i]); err != nil {
+				slog.Warn("não consegui retirar o convite", "url", open[i].URL, "err", err)
+				continue
+			}
+			if r.cfg.DryRun {
+				continue // o clique simulado já está no log
+			}
+			age := int(time.Since(open[i].SentAt).Hours() / 24)
+			if err := record(open[i], ledger.StatusWithdrawn, fmt.Sprintf("retirado pelo sync (pendente havia %d dias)", age)); err != nil {
+				return changes, err
+			}
+		}
+	}
+
+	// 3) conexões: quem está lá aceitou (a lista vem das mais recentes). Só
+	// pela URL: um homônimo nas conexões não aceita o convite de outro; quem
+	// não casar vai para o passo 4
+	missing := 0
+	for i := range open {
+		if !done[i] {
+			missing++
+		}
+	}
+	if missing > 0 && !r.stopRequested.Load() {
+		found := func(items []listItem) bool {
+			n := 0
+			for _, it := range items {
+				if i, ok := m.findURL(it); ok && !done[i] {
+					n++
+				}
+			}
+			return n >= missing
+		}
+		conns, _, err := r.loadList(bctx, connectionsURL, found)
+		if err != nil {
+			slog.Warn("lendo conexões", "err", err)
+		}
+		for _, it := range conns {
+			i, ok := m.findURL(it)
+			if !ok || done[i] {
+				continue
+			}
+			done[i] = true
+			if err := record(open[i], ledger.StatusAccepted, cmp.Or(it.When, "na lista de conexões")); err != nil {
+				return changes, err
+			}
+		}
+	}
+
+	// 4) o resto: abre o perfil e vê o botão
+	checked := 0
+	for i, o := range open {
+		if done[i] {
+			continue
+		}
+		if r.stopRequested.Load() || ctx.Err() != nil {
+			slog.Warn("cancelado: sync interrompido")
+			break
+		}
+		if checked >= syncProfileChecks {
+			slog.Info("limite de perfis conferidos atingido; o resto fica para o próximo sync", "max", syncProfileChecks)
+			break
+		}
+		if checked > 0 {
+			time.Sleep(r.cfg.Throttle.InviteDelay.Rand())
+		}
+		checked++
+		start := time.Now()
+		if err := chromedp.Run(bctx, chromedp.Navigate(o.URL), chromedp.WaitVisible(`main h1`, chromedp.ByQuery)); err != nil {
+			slog.Warn("abrindo perfil", "url", o.URL, "err", err)
+			continue
+		}
+		r.observeLoad("profile", start)
+		st, err := readProfileState(bctx)
+		if err != nil {
+			slog.Warn("lendo perfil", "url", o.URL, "err", err)
+			continue
+		}
+		switch st.State {
+		case stateConnected:
+			err = record(o, ledger.StatusAccepted, "conferido no perfil")
+		case stateConnect:
+			err = record(o, ledger.StatusWithdrawn, "convite não está mais pendente (retirado, recusado ou expirado)")
+		case statePending:
+			if o.Status != "pending" {
+				err = record(o, "pending", "conferido no perfil")
+			}
+		}
+		if err != nil {
+			return changes, err
+		}
+	}
+	return changes, nil
+}
+
+// withdrawInvite clica em "Retirar" no cartão de url (na página de convites
+// enviados) e confirma.
+func (r *run) withdrawInvite(ctx context.Context, url string) error {
+	if _, err := r.mutate(ctx, action{
+		Name:     "withdraw",
+		Target:   url,
+		Selector: fmt.Sprintf(`main li:has(a[href=%q]) button "Retirar"/"Withdraw"`, url),
+		find: fmt.Sprintf(`(() => {
+		  const li = Array.from(document.querySelectorAll('main li'))
+		    .find(li => Array.from(li.querySelectorAll('a[href*="/in/"]')).some(a => a.href.split('?')[0] === %q));
+		  return li && Array.from(li.querySelectorAll('button'))
+		    .find(b => /retirar|withdraw/i.test(b.getAttribute('aria-label') || b.innerText)) || null;
+		})()`, url),
+	}); err != nil {
+		return err
+	}
+	if r.cfg.DryRun {
+		return nil
+	}
+	_ = chromedp.Run(ctx, chromedp.Sleep(800*time.Millisecond))
+	if _, err := r.mutate(ctx, action{
+		Name:     "withdraw_confirm",
+		Target:   url,
+		Selector: `[role="dialog"] button "Retirar"/"Withdraw"`,
+		find: `Array.from(document.querySelectorAll('[role="dialog"] button, [role="alertdialog"] button'))
+		  .find(b => /^(retirar|withdraw)/i.test((b.innerText || '').trim())) || null`,
+	}); err != nil {
+		return fmt.Errorf("confirmando: %w", err)
+	}
+	return chromedp.Run(ctx, chromedp.Sleep(1200*time.Millisecond))
+}
+
+func writeSyncReport(path string, entries []ledger.Entry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := ledger.WriteCSV(f, entries); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,179 @@
+// Package cron interpreta expressões cron de 5 campos (minuto hora dia mês
+// dia-da-semana) e calcula o próximo horário em que disparam.
+//
+// Aceita *, listas (1,15), faixas (1-5), passos (*/15, 8-18/2), nomes de mês e
+// dia em inglês (jan, mon) e os atalhos @hourly, @daily, @weekly, @monthly e
+// @yearly. Como no cron clássico, se dia e dia-da-semana forem restritos, basta
+// um dos dois bater.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule é uma expressão já interpretada.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // bit i = valor i permitido
+	domAny, dowAny                bool   // campo começou com "*"
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minuto", min: 0, max: 59}
+	hourField   = field{name: "hora", min: 0, max: 23}
+	domField    = field{name: "dia", min: 1, max: 31}
+	monthField  = field{name: "mês", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 também é domingo
+	dowField = field{name: "dia da semana", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// Parse interpreta expr. Os erros dizem qual campo está inválido.
+func Parse(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if m, ok := macros[strings.ToLower(expr)]; ok {
+		expr = m
+	}
+	parts := strings.Fields(expr)
+	if len(parts) != 5 {
+		return Schedule{}, fmt.Errorf("expressão cron precisa de 5 campos (minuto hora dia mês dia-da-semana), tem %d", len(parts))
+	}
+
+	var s Schedule
+	var err error
+	if s.minute, err = parseField(parts[0], minuteField); err != nil {
+		return Schedule{}, err
+	}
+	if s.hour, err = parseField(parts[1], hourField); err != nil {
+		return Schedule{}, err
+	}
+	if s.dom, err = parseField(parts[2], domField); err != nil {
+		return Schedule{}, err
+	}
+	if s.month, err = parseField(parts[3], monthField); err != nil {
+		return Schedule{}, err
+	}
+	if s.dow, err = parseField(parts[4], dowField); err != nil {
+		return Schedule{}, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domAny = strings.HasPrefix(parts[2], "*")
+	s.dowAny = strings.HasPrefix(parts[4], "*")
+	return s, nil
+}
+
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for item := range strings.SplitSeq(expr, ",") {
+		rng, stepStr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%s: passo inválido em %q", f.name, item)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%s: faixa invertida em %q", f.name, item)
+			}
+		default:
+			v, err := f.value(rng)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if !hasStep { // "5/10" = de 5 até o fim, de 10 em 10
+				hi = v
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s: %q fora de %d-%d", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next devolve o primeiro horário depois de after (com minuto cheio, no fuso
+// de after) em que a expressão dispara. Zero se não disparar em 5 anos
+// (ex.: "0 0 30 2 *").
+func (s Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		y, mo, d := t.Date()
+		h, mi := t.Hour(), t.Minute()
+		switch {
+		case s.month&(1<<uint(mo)) == 0:
+			t = time.Date(y, mo+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(y, mo, d+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(h)) == 0:
+			t = time.Date(y, mo, d, h+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(mi)) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,74 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	cases := []struct {
+		expr, after, want string
+	}{
+		{"* * * * *", "2026-10-19 09:00", "2026-10-19 09:01"},
+		{"0 9 * * 1", "2026-10-18 12:00", "2026-10-19 09:00"}, // domingo → segunda
+		{"0 9 * * mon", "2026-10-19 09:00", "2026-10-26 09:00"},
+		{"*/15 8-18 * * 1-5", "2026-10-16 18:50", "2026-10-19 08:00"}, // sexta à noite → segunda
+		{"30 8-18/2 * * *", "2026-10-19 09:00", "2026-10-19 10:30"},
+		{"0 0 1 * *", "2026-12-15 00:00", "2027-01-01 00:00"},
+		{"0 0 29 2 *", "2026-03-01 00:00", "2028-02-29 00:00"},
+		{"0 12 13 * 5", "2026-10-17 00:00", "2026-10-23 12:00"}, // dia 13 OU sexta
+		{"0 0 * * 7", "2026-10-19 00:00", "2026-10-25 00:00"},   // 7 = domingo
+		{"5/20 * * * *", "2026-10-19 09:26", "2026-10-19 09:45"},
+		{"@weekly", "2026-10-19 00:00", "2026-10-25 00:00"},
+		{"0 6 * JAN,jul *", "2026-08-01 00:00", "2027-01-01 06:00"},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", c.expr, err)
+			continue
+		}
+		if got := s.Next(at(c.after)); !got.Equal(at(c.want)) {
+			t.Errorf("%q depois de %s = %s, quero %s", c.expr, c.after, got.Format("2006-01-02 15:04 Mon"), c.want)
+		}
+	}
+}
+
+func TestNextNever(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Next(at("2026-01-01 00:00")); !got.IsZero() {
+		t.Errorf("30 de fevereiro disparou em %s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"x * * * *",
+		"@sometimes",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) deveria falhar", expr)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package events é o protocolo entre o servidor e o processo do crawler
+// quando rodando com --interactive: eventos saem no stdout como linhas
+// "@@event {json}" e comandos chegam no stdin como linhas JSON.
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+)
+
+const Prefix = "@@event "
+
+const (
+	TypeChallenge    = "challenge"     // screenshot da página durante um desafio
+	TypeChallengeEnd = "challenge_end" // desafio resolvido (ou desistimos)
+	TypeMetric       = "metric"        // amostra de métrica (--metrics)
+)
+
+// Event é emitido pelo crawler.
+type Event struct {
+	Type  string `json:"type"`
+	Kind  string `json:"kind,omitempty"`  // "captcha", "checkpoint", "2fa"
+	Image string `json:"image,omitempty"` // JPEG em base64
+}
+
+// Métricas que o crawler reporta; o servidor soma no /metrics.
+const (
+	MetricPages          = "pages_scraped"      // páginas de resultado lidas
+	MetricProfiles       = "profiles_extracted" // perfis extraídos
+	MetricEmptyPages     = "empty_pages"        // páginas lidas sem nenhum perfil
+	MetricChallenges     = "login_challenges"   // Label = captcha, checkpoint, 2fa
+	MetricInvites        = "invites_sent"
+	MetricPageLoad       = "page_load_seconds" // Label = login, search, next_page, scrape
+	MetricChromeRestarts = "chrome_restarts"
+)
+
+// Metric é uma amostra: contadores somam Value, histogramas observam Value.
+type Metric struct {
+	Type  string  `json:"type"` // TypeMetric
+	Name  string  `json:"name"`
+	Label string  `json:"label,omitempty"`
+	Value float64 `json:"value"`
+}
+
+const (
+	CmdCode  = "code"  // preencher o código 2FA
+	CmdClick = "click" // clique na posição (X, Y) relativa ao screenshot, 0..1
+)
+
+// Command é enviado pela UI ao crawler.
+type Command struct {
+	Type string  `json:"type"`
+	Code string  `json:"code,omitempty"`
+	X    float64 `json:"x,omitempty"`
+	Y    float64 `json:"y,omitempty"`
+}
+
+// Writer serializa eventos numa saída compartilhada com outras escritas.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer { return &Writer{w: w} }
+
+func (w *Writer) Emit(ev any) error {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = io.WriteString(w.w, Prefix+string(b)+"\n")
+	return err
+}
+
+// Parse devolve o JSON cru de uma linha de evento e o tipo dela.
+func Parse(line string) (json.RawMessage, string, bool) {
+	raw, ok := strings.CutPrefix(line, Prefix)
+	if !ok {
+		return nil, "", false
+	}
+	var head struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(raw), &head); err != nil || head.Type == "" {
+		return nil, "", false
+	}
+	return json.RawMessage(raw), head.Type, true
+}
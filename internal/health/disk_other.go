@@ -0,0 +1,9 @@
+//go:build !unix
+
+package health
+
+import "errors"
+
+func diskSpace(dir string) (free, total uint64, err error) {
+	return 0, 0, errors.New("espaço em disco: não suportado neste sistema")
+}
@@ -0,0 +1,219 @@
+// Package health responde /healthz (o processo está vivo) e /readyz (dá para
+// rodar jobs: o Chrome abre, há disco livre e os arquivos de dados estão
+// legíveis). O /readyz serve de healthcheck do container.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/chromedp"
+)
+
+// Status geral de um relatório.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// Check é o resultado de uma verificação.
+type Check struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report é o corpo do /readyz.
+type Report struct {
+	Status         string    `json:"status"`
+	BrowserVersion string    `json:"browser_version,omitempty"`
+	DiskFreeBytes  uint64    `json:"disk_free_bytes,omitempty"`
+	DiskTotalBytes uint64    `json:"disk_total_bytes,omitempty"`
+	Checks         []Check   `json:"checks"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// Checker faz as verificações do /readyz.
+type Checker struct {
+	ChromePath    string        // vazio = o chromedp procura no PATH
+	ChromeTimeout time.Duration // abrir + about:blank
+	ChromeTTL     time.Duration // reaproveita o último teste do Chrome por este tempo
+	DataDir       string
+	MinFreeBytes  uint64
+	Stores        []string // arquivos JSON dos stores
+
+	// chrome troca o teste real nos testes do pacote
+	chrome func(ctx context.Context) (string, error)
+
+	mu         sync.Mutex // um teste do Chrome por vez
+	chromeLast Check
+	chromeVer  string
+	chromeAt   time.Time
+}
+
+// Ready roda as verificações; o teste do Chrome vem do cache se for recente.
+func (c *Checker) Ready(ctx context.Context) Report {
+	rep := Report{Status: StatusOK, CheckedAt: time.Now()}
+	add := func(ch Check) {
+		rep.Checks = append(rep.Checks, ch)
+		if !ch.OK {
+			rep.Status = StatusFail
+		}
+	}
+
+	chrome, version := c.chromeCheck(ctx)
+	rep.BrowserVersion = version
+	add(chrome)
+
+	start := time.Now()
+	free, total, err := diskSpace(c.DataDir)
+	disk := Check{Name: "disk", OK: err == nil && free >= c.MinFreeBytes}
+	switch {
+	case err != nil:
+		disk.Detail = err.Error()
+	default:
+		rep.DiskFreeBytes, rep.DiskTotalBytes = free, total
+		disk.Detail = fmt.Sprintf("%s livres de %s em %s", bytesText(free), bytesText(total), c.DataDir)
+		if !disk.OK {
+			disk.Detail += fmt.Sprintf(" (mínimo %s)", bytesText(c.MinFreeBytes))
+		}
+	}
+	disk.Duration = since(start)
+	add(disk)
+
+	start = time.Now()
+	err = c.checkStores()
+	store := Check{Name: "stores", OK: err == nil, Duration: since(start)}
+	if err != nil {
+		store.Detail = err.Error()
+	} else {
+		store.Detail = fmt.Sprintf("%d arquivos legíveis; %s gravável", len(c.Stores), c.DataDir)
+	}
+	add(store)
+	return rep
+}
+
+func (c *Checker) chromeCheck(ctx context.Context) (Check, string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.chromeAt.IsZero() && time.Since(c.chromeAt) < c.ChromeTTL {
+		return c.chromeLast, c.chromeVer
+	}
+	run := c.chrome
+	if run == nil {
+		run = c.launchChrome
+	}
+	start := time.Now()
+	version, err := run(ctx)
+	ch := Check{Name: "chrome", OK: err == nil, Detail: version, Duration: since(start)}
+	if err != nil {
+		ch.Detail = err.Error()
+	}
+	c.chromeLast, c.chromeVer, c.chromeAt = ch, version, time.Now()
+	return ch, version
+}
+
+// launchChrome abre o Chrome headless (perfil temporário), navega para
+// about:blank e devolve a versão.
+func (c *Checker) launchChrome(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.ChromeTimeout)
+	defer cancel()
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.Flag("headless", true),
+		chromedp.Flag("no-sandbox", true),
+		chromedp.Flag("disable-gpu", true),
+		chromedp.Flag("disable-dev-shm-usage", true),
+	)
+	if c.ChromePath != "" {
+		opts = append(opts, chromedp.ExecPath(c.ChromePath))
+	}
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, opts...)
+	defer allocCancel()
+	bctx, bcancel := chromedp.NewContext(allocCtx)
+	defer bcancel()
+
+	var product string
+	err := chromedp.Run(bctx,
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			_, product, _, _, _, err = browser.GetVersion().Do(ctx)
+			return err
+		}),
+	)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("Chrome não abriu about:blank em %s", c.ChromeTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("Chrome: %w", err)
+	}
+	return product, nil
+}
+
+// checkStores confere que os arquivos dos stores são JSON legíveis (ausente
+// é ok: nasce no primeiro save) e que a pasta de dados aceita escrita.
+func (c *Checker) checkStores() error {
+	for _, p := range c.Stores {
+		b, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !json.Valid(b) {
+			return fmt.Errorf("%s: JSON inválido", filepath.Base(p))
+		}
+	}
+	f, err := os.CreateTemp(c.DataDir, ".readyz-*")
+	if err != nil {
+		return fmt.Errorf("pasta de dados sem escrita: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// =============== HTTP ===============
+
+// Healthz só diz que o processo responde.
+func Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Readyz responde 200 com tudo ok e 503 se alguma verificação falhou.
+func (c *Checker) Readyz(w http.ResponseWriter, r *http.Request) {
+	rep := c.Ready(r.Context())
+	status := http.StatusOK
+	if rep.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, rep)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func since(t time.Time) string {
+	return time.Since(t).Round(time.Millisecond).String()
+}
+
+func bytesText(n uint64) string {
+	const gb, mb = 1 << 30, 1 << 20
+	if n >= gb {
+		return fmt.Sprintf("%.1f GB", float64(n)/gb)
+	}
+	return fmt.Sprintf("%d MB", n/mb)
+}
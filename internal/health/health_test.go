@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newChecker(t *testing.T, chrome func(context.Context) (string, error)) (*Checker, string) {
+	dir := t.TempDir()
+	return &Checker{DataDir: dir, ChromeTTL: time.Minute, Stores: []string{filepath.Join(dir, "jobs.json")}, chrome: chrome}, dir
+}
+
+func readyz(t *testing.T, c *Checker) (int, Report) {
+	rec := httptest.NewRecorder()
+	c.Readyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var rep Report
+	if err := json.Unmarshal(rec.Body.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+	return rec.Code, rep
+}
+
+func TestReadyOK(t *testing.T) {
+	c, dir := newChecker(t, func(context.Context) (string, error) { return "HeadlessChrome/125.0", nil })
+	os.WriteFile(filepath.Join(dir, "jobs.json"), []byte(`{"jobs":[]}`), 0o600)
+
+	code, rep := readyz(t, c)
+	if code != http.StatusOK || rep.Status != StatusOK {
+		t.Fatalf("readyz = %d %+v", code, rep)
+	}
+	if rep.BrowserVersion != "HeadlessChrome/125.0" || rep.DiskTotalBytes == 0 || len(rep.Checks) != 3 {
+		t.Errorf("relatório = %+v", rep)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dir, ".readyz-*")); len(left) > 0 {
+		t.Errorf("sobrou arquivo de teste: %v", left)
+	}
+}
+
+func TestReadyFailures(t *testing.T) {
+	c, dir := newChecker(t, func(context.Context) (string, error) { return "", errors.New("chrome ausente") })
+	os.WriteFile(filepath.Join(dir, "jobs.json"), []byte(`{quebrado`), 0o600)
+	c.MinFreeBytes = 1 << 62
+
+	code, rep := readyz(t, c)
+	if code != http.StatusServiceUnavailable || rep.Status != StatusFail {
+		t.Fatalf("readyz = %d %+v", code, rep)
+	}
+	for _, ch := range rep.Checks {
+		if ch.OK {
+			t.Errorf("%s passou: %+v", ch.Name, ch)
+		}
+	}
+}
+
+func TestChromeCached(t *testing.T) {
+	calls := 0
+	c, _ := newChecker(t, func(context.Context) (string, error) { calls++; return "v1", nil })
+	c.Ready(context.Background())
+	c.Ready(context.Background())
+	if calls != 1 {
+		t.Errorf("Chrome testado %d vezes dentro do TTL", calls)
+	}
+	c.chromeAt = time.Now().Add(-2 * time.Minute)
+	c.Ready(context.Background())
+	if calls != 2 {
+		t.Errorf("Chrome não foi retestado após o TTL (%d)", calls)
+	}
+}
+
+func TestHealthz(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Healthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"status\":\"ok\"}\n" {
+		t.Errorf("healthz = %d %q", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,215 @@
+// Package jobs mantém o registro das execuções do crawler disparadas pela UI.
+package jobs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusDone      Status = "done"
+	StatusFailed    Status = "failed"
+	StatusCancelled Status = "cancelled"
+)
+
+// Statuses lista todos os status, na ordem do ciclo de vida.
+var Statuses = []Status{StatusQueued, StatusRunning, StatusDone, StatusFailed, StatusCancelled}
+
+// Finished diz se o job já terminou (com ou sem sucesso).
+func (st Status) Finished() bool {
+	return st == StatusDone || st == StatusFailed || st == StatusCancelled
+}
+
+var ErrNotFound = errors.New("job não encontrado")
+
+// Facets são os filtros da busca de pessoas. Job sem Facets usa os padrões do
+// crawler (São Paulo e o 1º item de "Empresa atual").
+type Facets struct {
+	GeoURNs      []string `json:"geo_urns"`              // IDs de localidade do LinkedIn; vazio = qualquer lugar
+	FirstCompany bool     `json:"first_current_company"` // aplica o 1º item do filtro "Empresa atual"
+}
+
+type Job struct {
+	ID         string  `json:"id"`
+	Owner      string  `json:"owner"`
+	Query      string  `json:"query"`
+	MaxPages   int     `json:"max_pages"`
+	Headless   bool    `json:"headless"`
+	InviteNote string  `json:"invite_note,omitempty"` // modelo da nota dos convites; vazio = sem nota
+	DumpHTML   bool    `json:"dump_html"`
+	DryRun     bool    `json:"dry_run,omitempty"` // navega e captura sem clicar em convites e filtros
+	Account    string  `json:"account,omitempty"` // conta pedida; vazio = qualquer uma com orçamento
+	Facets     *Facets `json:"facets,omitempty"`
+	SearchID   string  `json:"search_id,omitempty"` // busca salva que originou o job
+
+	// Job de convites: em vez de buscar, convida os perfis InviteURLs (a lista
+	// revisada) do CSV do job InviteFrom. Busca nenhuma convida sozinha.
+	InviteFrom string   `json:"invite_from,omitempty"`
+	InviteURLs []string `json:"invite_urls,omitempty"`
+
+	Status          Status    `json:"status"`
+	Message         string    `json:"message,omitempty"`
+	AssignedAccount string    `json:"assigned_account,omitempty"`
+	Dir             string    `json:"dir"`
+	CSVPath         string    `json:"csv_path,omitempty"`
+	QualityPath     string    `json:"quality_path,omitempty"`
+	Degraded        bool      `json:"degraded,omitempty"`       // a captura passou de algum limite de qualidade
+	QualityIssues   []string  `json:"quality_issues,omitempty"` // os limites que passaram
+	Pages           int       `json:"pages"`
+	Profiles        int       `json:"profiles"`
+	Invites         int       `json:"invites"`
+	CreatedAt       time.Time `json:"created_at"`
+	StartedAt       time.Time `json:"started_at,omitzero"`
+	EndedAt         time.Time `json:"ended_at,omitzero"`
+}
+
+// IsInvite diz se o job convida uma lista revisada (senão é uma busca).
+func (j Job) IsInvite() bool {
+	return j.InviteFrom != ""
+}
+
+// Store guarda os jobs em memória e persiste tudo num arquivo JSON.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	jobs map[string]*Job
+}
+
+type fileData struct {
+	Jobs []*Job `json:"jobs"`
+}
+
+// Open carrega o arquivo de jobs. Jobs que estavam rodando quando o
+// processo anterior morreu são marcados como falhos; os da fila continuam nela.
+func Open(path string) (*Store, error) {
+	var fd fileData
+	if err := jsonfile.Load(path, &fd); err != nil {
+		return nil, err
+	}
+	s := &Store{path: path, jobs: map[string]*Job{}}
+	dirty := false
+	for _, j := range fd.Jobs {
+		if j.Status == StatusRunning {
+			j.Status = StatusFailed
+			j.Message = "interrompido (servidor reiniciado)"
+			dirty = true
+		}
+		s.jobs[j.ID] = j
+	}
+	if dirty {
+		if err := s.saveLocked(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Create enfileira j (parâmetros preenchidos pelo chamador); dirFor recebe o
+// ID gerado e devolve o diretório de saída do job.
+func (s *Store) Create(j Job, dirFor func(id string) string) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := newID()
+	j.ID = id
+	j.Status = StatusQueued
+	j.Dir = dirFor(id)
+	j.CreatedAt = time.Now()
+	s.jobs[id] = &j
+	if err := s.saveLocked(); err != nil {
+		delete(s.jobs, id)
+		return Job{}, err
+	}
+	return j, nil
+}
+
+// Update aplica fn ao job e persiste.
+func (s *Store) Update(id string, fn func(j *Job)) (Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	fn(j)
+	return *j, s.saveLocked()
+}
+
+func (s *Store) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *j, true
+}
+
+// List devolve os jobs de owner, do mais recente para o mais antigo.
+func (s *Store) List(owner string) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Job, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		if j.Owner == owner {
+			out = append(out, *j)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].CreatedAt.After(out[b].CreatedAt) })
+	return out
+}
+
+// Queued devolve os jobs na fila, do mais antigo para o mais novo.
+func (s *Store) Queued() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var out []Job
+	for _, j := range s.jobs {
+		if j.Status == StatusQueued {
+			out = append(out, *j)
+		}
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].CreatedAt.Before(out[b].CreatedAt) })
+	return out
+}
+
+// CountByStatus conta os jobs de todos os usuários por status.
+func (s *Store) CountByStatus() map[Status]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := map[Status]int{}
+	for _, j := range s.jobs {
+		out[j.Status]++
+	}
+	return out
+}
+
+func (s *Store) saveLocked() error {
+	fd := fileData{Jobs: make([]*Job, 0, len(s.jobs))}
+	for _, j := range s.jobs {
+		fd.Jobs = append(fd.Jobs, j)
+	}
+	sort.Slice(fd.Jobs, func(a, b int) bool { return fd.Jobs[a].CreatedAt.Before(fd.Jobs[b].CreatedAt) })
+	return jsonfile.Save(s.path, fd)
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
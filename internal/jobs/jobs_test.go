@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStorePersists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jobs.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirFor := func(id string) string { return filepath.Join(dir, id) }
+
+	done, err := s.Create(Job{Owner: "ana", Query: "golang"}, dirFor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done.ID == "" || done.Status != StatusQueued || done.Dir != filepath.Join(dir, done.ID) {
+		t.Fatalf("Create = %+v", done)
+	}
+	running, _ := s.Create(Job{Owner: "ana", Query: "rust"}, dirFor)
+	queued, _ := s.Create(Job{Owner: "ana", Query: "java"}, dirFor)
+	if _, err := s.Update(done.ID, func(j *Job) { j.Status, j.Profiles = StatusDone, 7 }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update(running.ID, func(j *Job) { j.Status = StatusRunning }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update("nada", func(*Job) {}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update de job inexistente: %v", err)
+	}
+
+	// reaberto: o que rodava quando o processo morreu vira falho, a fila fica
+	again, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j, ok := again.Get(done.ID); !ok || j.Status != StatusDone || j.Profiles != 7 || j.Query != "golang" {
+		t.Errorf("done = %+v, %v", j, ok)
+	}
+	if j, _ := again.Get(running.ID); j.Status != StatusFailed || j.Message == "" {
+		t.Errorf("running = %+v", j)
+	}
+	if q := again.Queued(); len(q) != 1 || q[0].ID != queued.ID {
+		t.Errorf("Queued = %+v", q)
+	}
+	if n := again.CountByStatus(); n[StatusDone] != 1 || n[StatusFailed] != 1 || n[StatusQueued] != 1 {
+		t.Errorf("CountByStatus = %v", n)
+	}
+	// a marcação de falho foi gravada
+	third, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := third.Get(running.ID); j.Status != StatusFailed {
+		t.Errorf("falho não gravado: %+v", j)
+	}
+}
+
+func TestListByOwner(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(filepath.Join(dir, "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirFor := func(id string) string { return filepath.Join(dir, id) }
+	first, _ := s.Create(Job{Owner: "ana"}, dirFor)
+	if _, err := s.Create(Job{Owner: "bia"}, dirFor); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := s.Create(Job{Owner: "ana"}, dirFor)
+
+	l := s.List("ana")
+	if len(l) != 2 || l[0].ID != second.ID || l[1].ID != first.ID {
+		t.Errorf("List(ana) = %+v", l)
+	}
+	if l := s.List("caio"); len(l) != 0 {
+		t.Errorf("List(caio) = %+v", l)
+	}
+	if n := s.CountByStatus(); n[StatusQueued] != 3 {
+		t.Errorf("CountByStatus = %v", n)
+	}
+}
@@ -0,0 +1,380 @@
+// Package ledger é o histórico de convites (um JSON por linha, só cresce) e a
+// lista de supressão que o fluxo de convites consulta antes de clicar:
+// URLs e empresas que não devem ser contatadas, mais quem já foi convidado.
+package ledger
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/results"
+)
+
+// Entry é um convite tentado, ou uma mudança de estado vista pelo "invites
+// sync". Status usa os valores do crawler (sent, pending, connected,
+// name_mismatch, no_button, note_error, weekly_limit, failed) e os do sync
+// (accepted, withdrawn). O estado atual de um perfil é a última entrada dele.
+type Entry struct {
+	At      time.Time `json:"at"`
+	Account string    `json:"account"`
+	URL     string    `json:"url"`
+	Name    string    `json:"name,omitempty"`
+	Company string    `json:"company,omitempty"`
+	Status  string    `json:"status"`
+	Detail  string    `json:"detail,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	JobID   string    `json:"job_id,omitempty"`
+}
+
+// Estados gravados pelo "invites sync".
+const (
+	StatusAccepted  = "accepted"  // virou conexão
+	StatusWithdrawn = "withdrawn" // retirado (pelo sync ou à mão) ou expirado
+)
+
+// Contacted diz se o perfil já recebeu (ou tem) convite: não se convida de
+// novo, nem quem teve o convite retirado.
+func (e Entry) Contacted() bool {
+	switch e.Status {
+	case "sent", "pending", "connected", StatusAccepted, StatusWithdrawn:
+		return true
+	}
+	return false
+}
+
+// Append acrescenta e ao fim do arquivo. Cada entrada é uma linha gravada de
+// uma vez, então execuções simultâneas não se misturam.
+func Append(path string, e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Read lê o histórico na ordem em que foi gravado. Arquivo inexistente = vazio;
+// linha corrompida (gravação interrompida) é pulada.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		var e Entry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.URL != "" {
+			out = append(out, e)
+		}
+	}
+	return out, sc.Err()
+}
+
+// Latest devolve a última entrada de cada perfil (o estado atual), na ordem
+// em que cada um apareceu pela primeira vez.
+func Latest(entries []Entry) []Entry {
+	idx := map[string]int{}
+	var out []Entry
+	for _, e := range entries {
+		k := results.Key(results.Row{URL: e.URL})
+		if i, ok := idx[k]; ok {
+			out[i] = e
+			continue
+		}
+		idx[k] = len(out)
+		out = append(out, e)
+	}
+	return out
+}
+
+// Open é um convite da conta ainda sem resposta.
+type Open struct {
+	Entry            // último estado (sent ou pending)
+	SentAt time.Time // primeiro envio em diante sem interrupção
+}
+
+// Outstanding devolve os perfis cujo último estado pela conta é sent ou
+// pending, isto é, os que o "invites sync" tem de conferir.
+func Outstanding(entries []Entry, account string) []Open {
+	var order []string
+	open := map[string]*Open{}
+	for _, e := range entries {
+		if e.Account != account {
+			continue
+		}
+		k := results.Key(results.Row{URL: e.URL})
+		switch o := open[k]; {
+		case e.Status != "sent" && e.Status != "pending":
+			delete(open, k) // aceito, retirado, falha...: fechado
+		case o == nil:
+			open[k] = &Open{Entry: e, SentAt: e.At}
+			order = append(order, k)
+		default:
+			o.Entry = e // continua aberto: SentAt fica o do primeiro envio
+		}
+	}
+	var out []Open
+	for _, k := range order {
+		if o := open[k]; o != nil {
+			out = append(out, *o)
+			delete(open, k)
+		}
+	}
+	return out
+}
+
+// CSVHeader são as colunas de WriteCSV.
+var CSVHeader = []string{"at", "account", "url", "name", "company", "status", "detail", "note", "job_id"}
+
+// WriteCSV exporta o histórico (com BOM, para o Excel abrir com acentos).
+func WriteCSV(w io.Writer, entries []Entry) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	_ = cw.Write(CSVHeader)
+	for _, e := range entries {
+		_ = cw.Write([]string{e.At.Format(results.TimeLayout), e.Account, e.URL, e.Name, e.Company, e.Status, e.Detail, e.Note, e.JobID})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// =============== Supressão ===============
+
+// Suppression é a lista de "não contatar". No arquivo, uma entrada por linha:
+// a URL do perfil ou "empresa: Nome" (também "company:"); # comenta.
+type Suppression struct {
+	URLs      []string `json:"urls"`
+	Companies []string `json:"companies"`
+}
+
+// ReadSuppression lê o arquivo; inexistente = lista vazia.
+func ReadSuppression(path string) (Suppression, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Suppression{}, nil
+	}
+	if err != nil {
+		return Suppression{}, err
+	}
+	return ParseSuppression(string(b))
+}
+
+// ParseSuppression lê o formato do arquivo; linhas que não são URL de
+// perfil nem empresa são erro (melhor avisar que ignorar um "não contatar").
+func ParseSuppression(text string) (Suppression, error) {
+	var s Suppression
+	var errs []error
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			switch strings.ToLower(strings.TrimSpace(k)) {
+			case "empresa", "company":
+				if v = strings.TrimSpace(v); v != "" {
+					s.Companies = append(s.Companies, v)
+					continue
+				}
+			}
+		}
+		if strings.Contains(results.Key(results.Row{URL: line}), "linkedin.com/in/") {
+			s.URLs = append(s.URLs, line)
+			continue
+		}
+		errs = append(errs, fmt.Errorf("linha %d: %q não é URL de perfil nem \"empresa: Nome\"", i+1, line))
+	}
+	return s, errors.Join(errs...)
+}
+
+// Format devolve s no formato do arquivo.
+func (s Suppression) Format() string {
+	var b strings.Builder
+	b.WriteString("# Não contatar: uma URL de perfil ou \"empresa: Nome\" por linha.\n")
+	for _, c := range s.Companies {
+		fmt.Fprintf(&b, "empresa: %s\n", c)
+	}
+	for _, u := range s.URLs {
+		fmt.Fprintln(&b, u)
+	}
+	return b.String()
+}
+
+// WriteSuppression grava s em path.
+func WriteSuppression(path string, s Suppression) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(s.Format()), 0o600)
+}
+
+// Checker responde se um perfil pode ser convidado e quanto resta das cotas.
+type Checker struct {
+	urls      map[string]string // chave da URL → motivo
+	companies []string          // já normalizadas
+	history   []Entry
+}
+
+// NewChecker junta a lista de supressão com quem o histórico já contatou.
+func NewChecker(s Suppression, history []Entry) *Checker {
+	c := &Checker{urls: map[string]string{}, history: history}
+	for _, e := range history {
+		if e.Contacted() {
+			c.urls[results.Key(results.Row{URL: e.URL})] = fmt.Sprintf("já convidado (%s em %s por %s)", e.Status, e.At.Format("2006-01-02"), e.Account)
+		}
+	}
+	for _, u := range s.URLs {
+		c.urls[results.Key(results.Row{URL: u})] = "na lista de não contatar"
+	}
+	for _, co := range s.Companies {
+		if co = fold(co); co != "" {
+			c.companies = append(c.companies, co)
+		}
+	}
+	return c
+}
+
+// Load monta o Checker a partir dos arquivos (vazios = ninguém suprimido).
+func Load(ledgerPath, suppressPath string) (*Checker, error) {
+	var history []Entry
+	var s Suppression
+	var err error
+	if ledgerPath != "" {
+		if history, err = Read(ledgerPath); err != nil {
+			return nil, fmt.Errorf("histórico de convites: %w", err)
+		}
+	}
+	if suppressPath != "" {
+		if s, err = ReadSuppression(suppressPath); err != nil {
+			return nil, fmt.Errorf("lista de supressão: %w", err)
+		}
+	}
+	return NewChecker(s, history), nil
+}
+
+// Suppressed diz se o perfil não deve ser convidado e por quê. A empresa
+// casa por "contém", sem maiúsculas nem acentos.
+func (c *Checker) Suppressed(url, company string) (string, bool) {
+	if reason, ok := c.urls[results.Key(results.Row{URL: url})]; ok {
+		return reason, true
+	}
+	if company = fold(company); company != "" {
+		for _, co := range c.companies {
+			if strings.Contains(company, co) {
+				return "empresa na lista de não contatar (" + co + ")", true
+			}
+		}
+	}
+	return "", false
+}
+
+// Add registra a tentativa desta execução: url contatada e cotas.
+func (c *Checker) Add(e Entry) {
+	c.history = append(c.history, e)
+	if e.Contacted() {
+		c.urls[results.Key(results.Row{URL: e.URL})] = "já convidado nesta execução"
+	}
+}
+
+// =============== Cotas ===============
+
+// StatusWeeklyLimit é gravado quando o LinkedIn avisa do limite semanal de
+// convites; a conta fica sem convites por Week a partir dele.
+const StatusWeeklyLimit = "weekly_limit"
+
+// Week é a janela da cota semanal: 7 dias corridos, como a do LinkedIn.
+const Week = 7 * 24 * time.Hour
+
+// ErrQuota: a conta não pode enviar mais convites agora.
+var ErrQuota = errors.New("cota de convites esgotada")
+
+// Quota limita os convites enviados por conta; 0 = sem limite.
+type Quota struct {
+	Daily  int // por dia (data local)
+	Weekly int // em Week
+}
+
+// Usage é o consumo de uma conta segundo o histórico.
+type Usage struct {
+	Today   int       // enviados hoje
+	Week    int       // enviados nos últimos 7 dias
+	LimitAt time.Time // último aviso de limite semanal nos últimos 7 dias
+}
+
+// Usage conta os convites enviados por account até now.
+func (c *Checker) Usage(account string, now time.Time) Usage {
+	var u Usage
+	today := now.Local().Format("2006-01-02")
+	for _, e := range c.history {
+		if e.Account != account || now.Sub(e.At) >= Week {
+			continue
+		}
+		switch e.Status {
+		case "sent":
+			u.Week++
+			if e.At.Local().Format("2006-01-02") == today {
+				u.Today++
+			}
+		case StatusWeeklyLimit:
+			if e.At.After(u.LimitAt) {
+				u.LimitAt = e.At
+			}
+		}
+	}
+	return u
+}
+
+// Remaining diz quantos convites ainda cabem (-1 = sem limite). Sem nenhum,
+// o erro (ErrQuota) diz qual cota acabou e até quando.
+func (q Quota) Remaining(u Usage) (int, error) {
+	if !u.LimitAt.IsZero() {
+		return 0, fmt.Errorf("%w: o LinkedIn avisou do limite semanal em %s; volte depois de %s",
+			ErrQuota, u.LimitAt.Local().Format("02/01 15:04"), u.LimitAt.Add(Week).Local().Format("02/01 15:04"))
+	}
+	left := -1
+	if q.Daily > 0 {
+		if u.Today >= q.Daily {
+			return 0, fmt.Errorf("%w: %d de %d hoje (cota diária)", ErrQuota, u.Today, q.Daily)
+		}
+		left = q.Daily - u.Today
+	}
+	if q.Weekly > 0 {
+		if u.Week >= q.Weekly {
+			return 0, fmt.Errorf("%w: %d de %d nos últimos 7 dias (cota semanal)", ErrQuota, u.Week, q.Weekly)
+		}
+		if w := q.Weekly - u.Week; left < 0 || w < left {
+			left = w
+		}
+	}
+	return left, nil
+}
+
+func fold(s string) string {
+	return strings.Join(strings.Fields(results.Fold(s)), " ")
+}
@@ -0,0 +1,153 @@
+package ledger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "invites.jsonl")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, e := range []Entry{
+		{At: at, Account: "rec1", URL: "https://www.linkedin.com/in/ana", Status: "sent", Note: "Oi Ana"},
+		{At: at, Account: "rec2", URL: "https://www.linkedin.com/in/bia", Status: "failed", Detail: "timeout"},
+	} {
+		if err := Append(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// gravação interrompida no meio de uma linha não estraga o resto
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	f.WriteString(`{"at":"2026-`)
+	f.Close()
+
+	got, err := Read(path)
+	if err != nil || len(got) != 2 || got[0].Note != "Oi Ana" || got[1].Account != "rec2" {
+		t.Fatalf("Read = %+v, %v", got, err)
+	}
+	if none, err := Read(filepath.Join(t.TempDir(), "nada.jsonl")); err != nil || none != nil {
+		t.Errorf("arquivo inexistente = %v, %v", none, err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(buf.String(), "\ufeff")), "\n")
+	if len(lines) != 3 || lines[0] != strings.Join(CSVHeader, ",") || !strings.Contains(lines[1], "Oi Ana") {
+		t.Errorf("CSV =\n%s", buf.String())
+	}
+}
+
+func TestSuppression(t *testing.T) {
+	s, err := ParseSuppression("# comentário\nempresa: Ação Ltda\nCompany: Acme\nhttps://www.linkedin.com/in/caio/\n")
+	if err != nil || len(s.Companies) != 2 || len(s.URLs) != 1 {
+		t.Fatalf("Parse = %+v, %v", s, err)
+	}
+	again, err := ParseSuppression(s.Format())
+	if err != nil || len(again.Companies) != 2 || len(again.URLs) != 1 {
+		t.Errorf("Format não volta: %+v, %v", again, err)
+	}
+	if _, err := ParseSuppression("https://www.linkedin.com/company/acme\nempresa:\n"); err == nil || !strings.Contains(err.Error(), "linha 2") {
+		t.Errorf("linhas inválidas: %v", err)
+	}
+
+	history := []Entry{
+		{URL: "https://www.linkedin.com/in/ana", Status: "sent", Account: "rec1"},
+		{URL: "https://www.linkedin.com/in/bia", Status: "failed"},
+	}
+	c := NewChecker(s, history)
+	cases := []struct {
+		url, company string
+		want         bool
+	}{
+		{"https://WWW.linkedin.com/in/ana?x=1", "", true},       // já convidado
+		{"https://www.linkedin.com/in/bia", "", false},          // falhou: pode tentar de novo
+		{"https://www.linkedin.com/in/caio", "", true},          // na lista
+		{"https://www.linkedin.com/in/duda", "acao ltda", true}, // empresa, sem acento
+		{"https://www.linkedin.com/in/edu", "ACME Brasil", true},
+		{"https://www.linkedin.com/in/fabi", "Beta", false},
+	}
+	for _, tc := range cases {
+		if reason, got := c.Suppressed(tc.url, tc.company); got != tc.want {
+			t.Errorf("Suppressed(%q, %q) = %v (%s), quero %v", tc.url, tc.company, got, reason, tc.want)
+		}
+	}
+	c.Add(Entry{URL: "https://www.linkedin.com/in/bia", Status: "sent"})
+	if _, ok := c.Suppressed("https://www.linkedin.com/in/bia", ""); !ok {
+		t.Error("convidado nesta execução deveria ficar suprimido")
+	}
+}
+
+func TestQuota(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 0, 0, 0, time.Local)
+	sent := func(account string, ago time.Duration) Entry {
+		return Entry{At: now.Add(-ago), Account: account, URL: "https://www.linkedin.com/in/x", Status: "sent"}
+	}
+	c := NewChecker(Suppression{}, []Entry{
+		sent("rec1", time.Hour),
+		sent("rec1", 2*time.Hour),
+		sent("rec1", 26*time.Hour),   // ontem
+		sent("rec1", 8*24*time.Hour), // fora da semana
+		sent("rec2", time.Hour),      // outra conta
+		{At: now, Account: "rec1", URL: "https://www.linkedin.com/in/y", Status: "failed"},
+	})
+	u := c.Usage("rec1", now)
+	if u.Today != 2 || u.Week != 3 || !u.LimitAt.IsZero() {
+		t.Fatalf("Usage = %+v", u)
+	}
+
+	for _, tc := range []struct {
+		q    Quota
+		left int
+		err  string
+	}{
+		{Quota{}, -1, ""},
+		{Quota{Daily: 5}, 3, ""},
+		{Quota{Daily: 5, Weekly: 4}, 1, ""},
+		{Quota{Daily: 2, Weekly: 100}, 0, "cota diária"},
+		{Quota{Weekly: 3}, 0, "cota semanal"},
+	} {
+		left, err := tc.q.Remaining(u)
+		if left != tc.left || (tc.err == "") != (err == nil) || (err != nil && (!errors.Is(err, ErrQuota) || !strings.Contains(err.Error(), tc.err))) {
+			t.Errorf("%+v: Remaining = %d, %v", tc.q, left, err)
+		}
+	}
+
+	// o aviso do LinkedIn trava a conta por uma semana, qualquer que seja a cota
+	c.Add(Entry{At: now.Add(-time.Minute), Account: "rec1", URL: "https://www.linkedin.com/in/z", Status: StatusWeeklyLimit})
+	if _, err := (Quota{}).Remaining(c.Usage("rec1", now)); !errors.Is(err, ErrQuota) || !strings.Contains(err.Error(), "limite semanal") {
+		t.Errorf("após o aviso: %v", err)
+	}
+	if _, err := (Quota{}).Remaining(c.Usage("rec1", now.Add(Week))); err != nil {
+		t.Errorf("uma semana depois: %v", err)
+	}
+}
+
+func TestOutstanding(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 10, 0, 0, 0, time.UTC) }
+	entries := []Entry{
+		{At: day(1), Account: "rec1", URL: "https://www.linkedin.com/in/ana", Status: "sent"},
+		{At: day(1), Account: "rec1", URL: "https://www.linkedin.com/in/bia", Status: "sent"},
+		{At: day(2), Account: "rec1", URL: "https://www.linkedin.com/in/caio", Status: "failed"},
+		{At: day(2), Account: "rec2", URL: "https://www.linkedin.com/in/duda", Status: "sent"},
+		{At: day(5), Account: "rec1", URL: "https://www.linkedin.com/in/ana/", Status: "pending"},
+		{At: day(6), Account: "rec1", URL: "https://www.linkedin.com/in/bia", Status: StatusAccepted},
+	}
+	open := Outstanding(entries, "rec1")
+	if len(open) != 1 || open[0].Status != "pending" || !open[0].SentAt.Equal(day(1)) {
+		t.Fatalf("Outstanding = %+v", open)
+	}
+	latest := Latest(entries)
+	if len(latest) != 4 || latest[0].Status != "pending" || latest[1].Status != StatusAccepted {
+		t.Errorf("Latest = %+v", latest)
+	}
+	if !latest[1].Contacted() || !(Entry{Status: StatusWithdrawn}).Contacted() {
+		t.Error("aceito e retirado não se convidam de novo")
+	}
+}
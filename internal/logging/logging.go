@@ -0,0 +1,119 @@
+// Package logging configura o log/slog do crawler e do servidor (texto para
+// gente, JSON para máquinas) e lê de volta as linhas JSON: o servidor roda o
+// crawler com --log-format=json e repassa cada registro para a UI.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Formatos aceitos em --log-format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Campos usados nos registros.
+const (
+	KeyJobID        = "job_id"
+	KeyQuery        = "query"
+	KeyPage         = "page"
+	KeyProfileCount = "profile_count"
+	KeyDuration     = "duration"
+)
+
+// New devolve um logger que escreve em w no formato pedido. Durações saem
+// como texto ("1.52s") nos dois formatos.
+func New(w io.Writer, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindDuration {
+			return slog.String(a.Key, a.Value.Duration().Round(time.Millisecond).String())
+		}
+		return a
+	}}
+	switch format {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("--log-format %q inválido (use text ou json)", format)
+	}
+}
+
+// Setup troca o logger padrão (slog e o pacote log) por um em stderr.
+func Setup(format string) error {
+	l, err := New(os.Stderr, format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(l)
+	return nil
+}
+
+// Fatal registra um erro e encerra o processo.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// Record é uma linha JSON do slog lida de volta.
+type Record struct {
+	Time  time.Time      `json:"time"`
+	Level string         `json:"level"` // debug, info, warn, error
+	Msg   string         `json:"msg"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// Parse lê uma linha do JSONHandler. Linhas que não são registros (panic,
+// saída do Chrome) devolvem false.
+func Parse(line string) (Record, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return Record{}, false
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		return Record{}, false
+	}
+	msg, ok1 := m[slog.MessageKey].(string)
+	level, ok2 := m[slog.LevelKey].(string)
+	if !ok1 || !ok2 {
+		return Record{}, false
+	}
+	rec := Record{Level: Level(level), Msg: msg}
+	if ts, ok := m[slog.TimeKey].(string); ok {
+		rec.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	delete(m, slog.MessageKey)
+	delete(m, slog.LevelKey)
+	delete(m, slog.TimeKey)
+	if len(m) > 0 {
+		rec.Attrs = m
+	}
+	return rec, true
+}
+
+// Level normaliza o nível do slog ("WARN", "ERROR+2"…) para debug, info,
+// warn ou error.
+func Level(s string) string {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return "info"
+	}
+	switch {
+	case l >= slog.LevelError:
+		return "error"
+	case l >= slog.LevelWarn:
+		return "warn"
+	case l >= slog.LevelInfo:
+		return "info"
+	default:
+		return "debug"
+	}
+}
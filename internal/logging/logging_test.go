@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.With(KeyJobID, "j1").Warn("página sem perfis", KeyPage, 3, KeyProfileCount, 0, KeyDuration, 1520*time.Millisecond)
+
+	rec, ok := Parse(strings.TrimSpace(buf.String()))
+	if !ok {
+		t.Fatalf("não leu %q", buf.String())
+	}
+	if rec.Level != "warn" || rec.Msg != "página sem perfis" || rec.Time.IsZero() {
+		t.Errorf("registro = %+v", rec)
+	}
+	want := map[string]any{KeyJobID: "j1", KeyPage: 3.0, KeyProfileCount: 0.0, KeyDuration: "1.52s"}
+	for k, v := range want {
+		if rec.Attrs[k] != v {
+			t.Errorf("%s = %#v, quero %#v", k, rec.Attrs[k], v)
+		}
+	}
+}
+
+func TestTextFormat(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := New(&buf, FormatText)
+	l.Info("login ok", KeyDuration, 2*time.Second)
+	if out := buf.String(); !strings.Contains(out, `level=INFO msg="login ok" duration=2s`) {
+		t.Errorf("texto = %q", out)
+	}
+	if _, err := New(&buf, "xml"); err == nil {
+		t.Error("aceitou formato xml")
+	}
+}
+
+func TestParseRejectsOtherLines(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"panic: runtime error",
+		`{"type":"challenge"}`,
+		`{"msg":1,"level":"INFO"}`,
+		`{broken`,
+	} {
+		if _, ok := Parse(line); ok {
+			t.Errorf("Parse(%q) aceitou", line)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for in, want := range map[string]string{"DEBUG": "debug", "INFO": "info", "WARN": "warn", "ERROR": "error", "ERROR+4": "error", "WARN-2": "info", "???": "info"} {
+		if got := Level(in); got != want {
+			t.Errorf("Level(%q) = %q, quero %q", in, got, want)
+		}
+	}
+}
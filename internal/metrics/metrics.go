@@ -0,0 +1,148 @@
+// Package metrics expõe o /metrics (Prometheus) do servidor: jobs por status,
+// contas e convites, mais as amostras que cada crawler manda pelo stdout
+// (events.TypeMetric) enquanto roda.
+package metrics
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"CrawlerLinkedin/internal/events"
+	"CrawlerLinkedin/internal/jobs"
+)
+
+const namespace = "golinkedin"
+
+// Metrics guarda os coletores de um servidor.
+type Metrics struct {
+	reg *prometheus.Registry
+
+	pages          prometheus.Counter
+	profiles       prometheus.Counter
+	emptyPages     prometheus.Counter
+	challenges     *prometheus.CounterVec
+	invites        prometheus.Counter
+	pageLoad       *prometheus.HistogramVec
+	chromeRestarts prometheus.Counter
+}
+
+// New registra os coletores. jobCounts é lido a cada scrape.
+func New(jobCounts func() map[jobs.Status]int) *Metrics {
+	m := &Metrics{
+		reg: prometheus.NewRegistry(),
+		pages: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "pages_scraped_total",
+			Help: "Páginas de resultado lidas pelos crawlers.",
+		}),
+		profiles: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "profiles_extracted_total",
+			Help: "Perfis extraídos das páginas de resultado.",
+		}),
+		emptyPages: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "empty_pages_total",
+			Help: "Páginas lidas sem nenhum perfil extraído (seletores quebrados ou fim da busca).",
+		}),
+		challenges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "login_challenges_total",
+			Help: "Verificações pedidas no login, por tipo.",
+		}, []string{"kind"}),
+		invites: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "invites_sent_total",
+			Help: "Convites de conexão enviados.",
+		}),
+		pageLoad: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "page_load_seconds",
+			Help:    "Tempo de carga de cada etapa no navegador.",
+			Buckets: []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60, 120},
+		}, []string{"step"}),
+		chromeRestarts: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "chrome_restarts_total",
+			Help: "Vezes que o Chrome precisou ser relançado.",
+		}),
+	}
+	// os tipos de desafio e etapas conhecidos aparecem zerados desde o início
+	for _, kind := range []string{"captcha", "checkpoint", "2fa"} {
+		m.challenges.WithLabelValues(kind)
+	}
+	for _, step := range []string{"login", "search", "next_page", "scrape"} {
+		m.pageLoad.WithLabelValues(step)
+	}
+	m.reg.MustRegister(
+		m.pages, m.profiles, m.emptyPages, m.challenges, m.invites, m.pageLoad, m.chromeRestarts,
+		&jobsCollector{counts: jobCounts},
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Record aplica uma linha events.TypeMetric vinda do crawler. Amostras
+// desconhecidas são ignoradas (crawler mais novo que o servidor).
+func (m *Metrics) Record(raw json.RawMessage) {
+	var s events.Metric
+	if err := json.Unmarshal(raw, &s); err != nil || s.Value < 0 {
+		return
+	}
+	switch s.Name {
+	case events.MetricPages:
+		m.pages.Add(s.Value)
+	case events.MetricProfiles:
+		m.profiles.Add(s.Value)
+	case events.MetricEmptyPages:
+		m.emptyPages.Add(s.Value)
+	case events.MetricChallenges:
+		if s.Label != "" {
+			m.challenges.WithLabelValues(s.Label).Add(s.Value)
+		}
+	case events.MetricInvites:
+		m.invites.Add(s.Value)
+	case events.MetricPageLoad:
+		if s.Label != "" {
+			m.pageLoad.WithLabelValues(s.Label).Observe(s.Value)
+		}
+	case events.MetricChromeRestarts:
+		m.chromeRestarts.Add(s.Value)
+	}
+}
+
+// Handler serve o formato de texto do Prometheus. Com token, exige
+// "Authorization: Bearer <token>".
+func (m *Metrics) Handler(token string) http.Handler {
+	h := promhttp.HandlerFor(m.reg, promhttp.HandlerOpts{})
+	if token == "" {
+		return h
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "não autorizado", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// =============== Jobs ===============
+
+var jobsDesc = prometheus.NewDesc(namespace+"_jobs", "Jobs por status.", []string{"status"}, nil)
+
+// jobsCollector lê o store no scrape, então o número bate com o jobs.json
+// mesmo depois de um restart.
+type jobsCollector struct {
+	counts func() map[jobs.Status]int
+}
+
+func (c *jobsCollector) Describe(ch chan<- *prometheus.Desc) { ch <- jobsDesc }
+
+func (c *jobsCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := c.counts()
+	for _, st := range jobs.Statuses {
+		ch <- prometheus.MustNewConstMetric(jobsDesc, prometheus.GaugeValue, float64(counts[st]), string(st))
+	}
+}
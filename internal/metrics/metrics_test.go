@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"CrawlerLinkedin/internal/events"
+	"CrawlerLinkedin/internal/jobs"
+)
+
+func scrape(t *testing.T, h http.Handler, auth string) (int, string) {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if auth != "" {
+		r.Header.Set("Authorization", auth)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	body, _ := io.ReadAll(w.Body)
+	return w.Code, string(body)
+}
+
+func sample(name, label string, v float64) json.RawMessage {
+	b, _ := json.Marshal(events.Metric{Type: events.TypeMetric, Name: name, Label: label, Value: v})
+	return b
+}
+
+func TestRecordAndExpose(t *testing.T) {
+	m := New(func() map[jobs.Status]int {
+		return map[jobs.Status]int{jobs.StatusDone: 3, jobs.StatusRunning: 1}
+	})
+	m.Record(sample(events.MetricPages, "", 1))
+	m.Record(sample(events.MetricPages, "", 1))
+	m.Record(sample(events.MetricProfiles, "", 10))
+	m.Record(sample(events.MetricEmptyPages, "", 1))
+	m.Record(sample(events.MetricChallenges, "captcha", 1))
+	m.Record(sample(events.MetricInvites, "", 4))
+	m.Record(sample(events.MetricPageLoad, "search", 1.5))
+	m.Record(sample(events.MetricPageLoad, "search", 3))
+	m.Record(sample(events.MetricChromeRestarts, "", 1))
+	// ignorados
+	m.Record(sample("desconhecida", "", 1))
+	m.Record(sample(events.MetricPages, "", -5))
+	m.Record(json.RawMessage(`{`))
+
+	code, body := scrape(t, m.Handler(""), "")
+	if code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+	for _, want := range []string{
+		`golinkedin_jobs{status="done"} 3`,
+		`golinkedin_jobs{status="running"} 1`,
+		`golinkedin_jobs{status="queued"} 0`,
+		`golinkedin_pages_scraped_total 2`,
+		`golinkedin_profiles_extracted_total 10`,
+		`golinkedin_empty_pages_total 1`,
+		`golinkedin_login_challenges_total{kind="captcha"} 1`,
+		`golinkedin_login_challenges_total{kind="2fa"} 0`,
+		`golinkedin_invites_sent_total 4`,
+		`golinkedin_page_load_seconds_bucket{step="search",le="2"} 1`,
+		`golinkedin_page_load_seconds_count{step="search"} 2`,
+		`golinkedin_page_load_seconds_count{step="login"} 0`,
+		`golinkedin_chrome_restarts_total 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("faltou %q", want)
+		}
+	}
+}
+
+func TestHandlerToken(t *testing.T) {
+	h := New(func() map[jobs.Status]int { return nil }).Handler("s3cr3t")
+	if code, _ := scrape(t, h, ""); code != http.StatusUnauthorized {
+		t.Errorf("sem token: %d", code)
+	}
+	if code, _ := scrape(t, h, "Bearer errado"); code != http.StatusUnauthorized {
+		t.Errorf("token errado: %d", code)
+	}
+	if code, _ := scrape(t, h, "Bearer s3cr3t"); code != http.StatusOK {
+		t.Errorf("token certo: %d", code)
+	}
+}
@@ -0,0 +1,197 @@
+// Package names limpa o nome que o LinkedIn mostra ("O status está
+// off-line", emoji, selos, "Dra.", "MBA, PMP") e o separa em primeiro nome,
+// nomes do meio, partículas (de, da, dos) e sobrenome.
+package names
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Name é um nome já limpo e separado. "Dra. Ana Maria dos Santos Filho, PMP"
+// vira First "Ana", Middle ["Maria"], Particle "dos", Last "Santos Filho".
+type Name struct {
+	Full     string   // nome limpo
+	First    string   // primeiro nome
+	Middle   []string // entre o primeiro nome e o sobrenome (com partículas)
+	Particle string   // partícula antes do sobrenome ("da", "dos", "van der")
+	Last     string   // último sobrenome, com Filho/Júnior/Neto se houver
+}
+
+// Surname é o sobrenome com a partícula ("dos Santos Filho").
+func (n Name) Surname() string {
+	return strings.TrimSpace(n.Particle + " " + n.Last)
+}
+
+// Parse limpa e separa raw.
+func Parse(raw string) Name {
+	full := Normalize(raw)
+	n := Name{Full: full}
+	words := strings.Fields(full)
+	if len(words) == 0 {
+		return n
+	}
+	n.First = words[0]
+	rest := words[1:]
+	if len(rest) == 0 {
+		return n
+	}
+	// sobrenome: a última palavra, mais a anterior se a última é Filho/Júnior...
+	i := len(rest) - 1
+	if i > 0 && generational[fold(rest[i])] {
+		i--
+	}
+	n.Last = strings.Join(rest[i:], " ")
+	j := i
+	for j > 0 && particles[fold(rest[j-1])] {
+		j--
+	}
+	n.Particle = strings.Join(rest[j:i], " ")
+	if j > 0 {
+		n.Middle = rest[:j]
+	}
+	return n
+}
+
+// =============== Limpeza ===============
+
+var (
+	// "O status está off-line", "Status is reachable"...
+	reStatus = regexp.MustCompile(`(?i)^\s*(?:o\s+)?status\s+(?:está|esta|is)\s+(?:off-?line|on-?line|disponível|disponivel|ausente|ocupado|reachable|away|busy|offline|online)\b`)
+	// selos e avisos colados no nome
+	reBadges = regexp.MustCompile(`(?i)(?:#?open\s*to\s*work|#?opentowork|#?hiring|aberto a (?:trabalho|oportunidades)|contratando|verificad[oa]|verified|premium|linkedin top voice|top voice|•\s*\d+(?:º|ª|st|nd|rd|th)\+?|\b\d+(?:º|ª|st|nd|rd|th)\+?(?:\s*(?:grau|degree))?|conexão de \d+º grau|\d+(?:st|nd|rd|th) degree connection)`)
+	// (Ela/Dela), (he/him), apelidos entre parênteses ou colchetes
+	reParens = regexp.MustCompile(`\s*[\(\[][^\)\]]*[\)\]]`)
+)
+
+// honorifics só saem do começo do nome.
+var honorifics = set("dr", "dra", "doutor", "doutora", "prof", "profa", "profª", "professor", "professora", "eng", "enga", "engª",
+	"sr", "sra", "srta", "mr", "mrs", "ms", "miss", "me", "ma")
+
+// credentials saem de qualquer posição (comparadas sem pontos e sem acento).
+var credentials = set("mba", "pmp", "phd", "msc", "bsc", "mphil", "cpa", "cfa", "cfp", "frm", "acca", "cima", "cissp", "cisa",
+	"cism", "ceh", "oscp", "csm", "cspo", "psm", "pspo", "itil", "capm", "shrm-cp", "shrm-scp", "sphr", "phr", "pmi-acp", "lion")
+
+// particles são as partículas de sobrenome, sempre em minúsculas.
+var particles = set("de", "da", "do", "das", "dos", "d", "e", "di", "del", "della", "dello", "du", "van", "von", "der", "den", "la", "le", "y", "ten", "ter")
+
+// generational fica junto do sobrenome ("Silva Filho").
+var generational = set("filho", "filha", "neto", "neta", "sobrinho", "sobrinha", "junior", "jr", "segundo", "terceiro", "ii", "iii", "iv")
+
+// Normalize tira status, selos, emoji, parênteses e credenciais e acerta
+// maiúsculas de nomes todos em caixa alta ou baixa. "Membro do LinkedIn"
+// (perfil fora da rede) vira vazio.
+func Normalize(raw string) string {
+	s := reStatus.ReplaceAllString(raw, "")
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u00a0':
+			return ' '
+		case unicode.Is(unicode.So, r), unicode.Is(unicode.Sk, r), unicode.Is(unicode.Cf, r),
+			unicode.Is(unicode.Mn, r) && r >= 0xfe00, unicode.Is(unicode.Co, r):
+			return -1 // emoji, tons de pele, variação, ZWJ
+		}
+		return r
+	}, s)
+	s = reParens.ReplaceAllString(s, " ")
+	s = reBadges.ReplaceAllString(s, " ")
+	switch f := fold(strings.Join(strings.Fields(s), " ")); f {
+	case "membro do linkedin", "linkedin member", "usuario do linkedin", "linkedin user":
+		return ""
+	}
+
+	// palavras: vírgulas e traços soltos separam credenciais ("Ana Souza, PhD - PMP")
+	var words []string
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == '|' || r == '/' || r == '•' || r == '·'
+	}) {
+		w = strings.Trim(w, "-–—_*~:;!?")
+		if w == "" || w == "." {
+			continue
+		}
+		k := fold(w)
+		if credentials[k] && (len(words) > 0 || !isWord(w)) {
+			continue
+		}
+		words = append(words, w)
+	}
+	// no começo: "Dra.", "Prof." e credenciais com pontos ou em caixa alta
+	// ("MBA Ana"), desde que sobre um nome
+	for len(words) > 1 && (honorifics[fold(words[0])] || credentials[fold(words[0])] && upperOrDotted(words[0])) {
+		words = words[1:]
+	}
+	return fixCase(words)
+}
+
+// FromSlug monta um nome a partir do slug da URL do perfil ("ana-souza-12ab34"
+// vira "Ana Souza"): trechos com dígito são o sufixo do LinkedIn.
+func FromSlug(slug string) string {
+	var kept []string
+	for _, p := range strings.Split(slug, "-") {
+		if p = strings.TrimSpace(p); p != "" && !strings.ContainsFunc(p, unicode.IsDigit) {
+			kept = append(kept, strings.ToLower(p))
+		}
+	}
+	return Normalize(strings.Join(kept, " "))
+}
+
+// fixCase só mexe em nomes todos em caixa alta ou todos em caixa baixa;
+// "McDonald" e "DiCaprio" ficam como vieram. Partículas ficam minúsculas.
+func fixCase(words []string) string {
+	joined := strings.Join(words, " ")
+	if joined != strings.ToUpper(joined) && joined != strings.ToLower(joined) {
+		return joined
+	}
+	for i, w := range words {
+		lw := strings.ToLower(w)
+		if i > 0 && particles[fold(lw)] {
+			words[i] = lw
+			continue
+		}
+		words[i] = title(lw)
+	}
+	return strings.Join(words, " ")
+}
+
+// title põe maiúscula no começo de cada parte ("ana-maria" → "Ana-Maria",
+// "d'ávila" → "D'Ávila").
+func title(w string) string {
+	rs := []rune(w)
+	up := true
+	for i, r := range rs {
+		if up && unicode.IsLetter(r) {
+			rs[i] = unicode.ToUpper(r)
+		}
+		up = r == '-' || r == '\''
+	}
+	return string(rs)
+}
+
+func isWord(w string) bool {
+	for _, r := range w {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func upperOrDotted(w string) bool {
+	return strings.Contains(w, ".") || w == strings.ToUpper(w)
+}
+
+var accents = strings.NewReplacer("á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "ô", "o", "õ", "o", "ú", "u", "ü", "u", "ç", "c")
+
+// fold compara sem maiúsculas, acentos e pontos ("Ph.D." = "phd", "Júnior" = "junior").
+func fold(s string) string {
+	return strings.ReplaceAll(accents.Replace(strings.ToLower(s)), ".", "")
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
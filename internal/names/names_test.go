@@ -0,0 +1,72 @@
+package names
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"O status está off-line Ana Souza": "Ana Souza",
+		"Status is reachable Bob Smith":    "Bob Smith",
+		"Ana Souza, PhD":                   "Ana Souza",
+		"Ana Souza, MBA, PMP®":             "Ana Souza",
+		"Dra. Ana Souza":                   "Ana Souza",
+		"Prof. Dr. João Silva":             "João Silva",
+		"Ana Souza 🚀✨":                     "Ana Souza",
+		"👩🏽‍💻 Bia Lima":                    "Bia Lima",
+		"Carla Dias (Ela/Dela)":            "Carla Dias",
+		"Davi Reis #OpenToWork":            "Davi Reis",
+		"Eva Rocha • 2º":                   "Eva Rocha",
+		"Eva Rocha 3rd+ degree":            "Eva Rocha",
+		"Fábio Nunes | Verificado":         "Fábio Nunes",
+		"MARIA DA SILVA":                   "Maria da Silva",
+		"joão pedro dos santos":            "João Pedro dos Santos",
+		"Ronald McDonald":                  "Ronald McDonald",
+		"Ph.D. Lucas Alves":                "Lucas Alves",
+		"Ana Ma":                           "Ana Ma", // sobrenome, não título
+		"Sr":                               "Sr",     // não sobra nome: fica como veio
+		"Membro do LinkedIn":               "",
+		"":                                 "",
+	}
+	for in, want := range cases {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, quero %q", in, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		in                             string
+		first, particle, last, surname string
+		middle                         []string
+	}{
+		{"Ana Souza", "Ana", "", "Souza", "Souza", nil},
+		{"Ana", "Ana", "", "", "", nil},
+		{"Maria da Silva", "Maria", "da", "Silva", "da Silva", nil},
+		{"Dra. Ana Maria dos Santos Filho, PMP", "Ana", "dos", "Santos Filho", "dos Santos Filho", []string{"Maria"}},
+		{"Ludwig van der Berg", "Ludwig", "van der", "Berg", "van der Berg", nil},
+		{"Pedro Neto", "Pedro", "", "Neto", "Neto", nil},
+		{"José de Souza e Silva Jr.", "José", "e", "Silva Jr.", "e Silva Jr.", []string{"de", "Souza"}},
+	}
+	for _, c := range cases {
+		n := Parse(c.in)
+		if n.First != c.first || n.Particle != c.particle || n.Last != c.last || n.Surname() != c.surname || !slices.Equal(n.Middle, c.middle) {
+			t.Errorf("Parse(%q) = %+v (Surname %q)", c.in, n, n.Surname())
+		}
+	}
+}
+
+func TestFromSlug(t *testing.T) {
+	for slug, want := range map[string]string{
+		"ana-souza-12ab34":      "Ana Souza",
+		"maria-da-silva":        "Maria da Silva",
+		"joao-pedro-a1b2c3d4e5": "Joao Pedro",
+		"123456":                "",
+	} {
+		if got := FromSlug(slug); got != want {
+			t.Errorf("FromSlug(%q) = %q, quero %q", slug, got, want)
+		}
+	}
+}
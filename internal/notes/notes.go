@@ -0,0 +1,127 @@
+// Package notes monta a nota dos convites a partir de um modelo text/template
+// com os campos do perfil ({{.FirstName}}, {{.Company}}, {{.Title}}...) e
+// garante o limite de tamanho do LinkedIn.
+package notes
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"CrawlerLinkedin/internal/names"
+)
+
+// MaxLen é o limite de caracteres da nota de convite do LinkedIn.
+const MaxLen = 300
+
+var ErrTooLong = errors.New("nota passa do limite")
+
+// Fields são os dados do perfil disponíveis no modelo.
+type Fields struct {
+	Name      string
+	FirstName string
+	LastName  string
+	Title     string
+	Company   string
+	Location  string
+}
+
+// Sample é o perfil usado para validar modelos e na prévia sem resultados.
+var Sample = Fields{
+	Name:      "Ana Souza",
+	FirstName: "Ana",
+	LastName:  "Souza",
+	Title:     "Engenheira de Software Sênior",
+	Company:   "Acme Tecnologia",
+	Location:  "São Paulo, SP",
+}
+
+// FromProfile monta os campos a partir do que o crawler captura. O nome passa
+// por names.Parse: sem "Dra.", emoji ou "MBA"; LastName é o último sobrenome,
+// sem partícula ("Silva" em "Ana da Silva").
+func FromProfile(name, title, company, location string) Fields {
+	n := names.Parse(name)
+	return Fields{
+		Name:      cmp.Or(n.Full, strings.TrimSpace(name)),
+		FirstName: n.First,
+		LastName:  n.Last,
+		Title:     title,
+		Company:   company,
+		Location:  location,
+	}
+}
+
+// Template é um modelo de nota já compilado.
+type Template struct {
+	t *template.Template
+}
+
+// Parse compila o modelo e o executa com Sample, então campos inexistentes
+// ({{.Empresa}}) e notas que já passam do limite com o perfil de exemplo
+// são recusados aqui, antes de qualquer convite.
+func Parse(text string) (*Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("modelo vazio")
+	}
+	t, err := template.New("nota").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("modelo inválido: %w", err)
+	}
+	tmpl := &Template{t: t}
+	if _, err := tmpl.Render(Sample); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// Render monta a nota do perfil: espaços repetidos viram um, as pontas são
+// aparadas e o resultado tem de caber em MaxLen.
+func (t *Template) Render(f Fields) (string, error) {
+	var b strings.Builder
+	if err := t.t.Execute(&b, f); err != nil {
+		return "", fmt.Errorf("modelo inválido: %w", err)
+	}
+	note := tidy(b.String())
+	if note == "" {
+		return "", errors.New("nota vazia")
+	}
+	if n := utf8.RuneCountInString(note); n > MaxLen {
+		return note, fmt.Errorf("%w: %d de %d caracteres", ErrTooLong, n, MaxLen)
+	}
+	return note, nil
+}
+
+// tidy apara cada linha, junta espaços repetidos e tira linhas em branco
+// das pontas (sobram dos {{if}} do modelo).
+func tidy(s string) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Preview é a nota de um perfil na prévia.
+type Preview struct {
+	Name   string `json:"name"`
+	Note   string `json:"note"`
+	Length int    `json:"length"`
+	Error  string `json:"error,omitempty"`
+}
+
+// PreviewAll monta a nota de cada perfil; os que falham vêm com Error.
+func (t *Template) PreviewAll(list []Fields) []Preview {
+	out := make([]Preview, 0, len(list))
+	for _, f := range list {
+		note, err := t.Render(f)
+		p := Preview{Name: f.Name, Note: note, Length: utf8.RuneCountInString(note)}
+		if err != nil {
+			p.Error = err.Error()
+		}
+		out = append(out, p)
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package notes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tmpl, err := Parse("Olá {{.FirstName}},\n\nvi que você é {{.Title}}{{if .Company}} na {{.Company}}{{end}}.  Vamos conectar?\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := tmpl.Render(FromProfile("Bia  Lima", "QA", "", ""))
+	want := "Olá Bia,\n\nvi que você é QA. Vamos conectar?"
+	if err != nil || got != want {
+		t.Errorf("Render = %q, %v; quero %q", got, err, want)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"Olá {{.FirstName",
+		"Olá {{.Empresa}}",
+		strings.Repeat("a", MaxLen+1),
+	} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%.20q) deveria falhar", text)
+		}
+	}
+}
+
+func TestTooLong(t *testing.T) {
+	tmpl, err := Parse("Oi {{.FirstName}}! " + strings.Repeat("é", MaxLen-12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tmpl.Render(FromProfile("Ana", "", "", "")); err != nil {
+		t.Errorf("cabe no limite (contado em caracteres): %v", err)
+	}
+	_, err = tmpl.Render(FromProfile("Maximiliano", "", "", ""))
+	if !errors.Is(err, ErrTooLong) {
+		t.Errorf("err = %v, quero ErrTooLong", err)
+	}
+	p := tmpl.PreviewAll([]Fields{FromProfile("Maximiliano", "", "", "")})
+	if len(p) != 1 || p[0].Error == "" || p[0].Length <= MaxLen {
+		t.Errorf("prévia = %+v", p)
+	}
+}
+
+func TestFromProfile(t *testing.T) {
+	f := FromProfile("Dra. Ana Maria da Silva, PhD 🚀", "CTO", "Acme", "")
+	if f.Name != "Ana Maria da Silva" || f.FirstName != "Ana" || f.LastName != "Silva" {
+		t.Errorf("FromProfile = %+v", f)
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP envia emails por um servidor SMTP (STARTTLS quando o servidor oferece).
+type SMTP struct {
+	Addr     string // host:porta
+	Username string // vazio = sem autenticação
+	Password string
+	From     string
+}
+
+func (m *SMTP) Send(to, subject, body string) error {
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("SMTP_ADDR inválido: %w", err)
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	msg := strings.Join([]string{
+		"From: " + m.From,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: 8bit",
+		"",
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	}, "\r\n")
+	return smtp.SendMail(m.Addr, auth, m.From, []string{to}, []byte(msg))
+}
+
+var eventTitles = map[string]string{
+	EventJobCompleted: "job concluído",
+	EventJobFailed:    "job falhou",
+	EventChallenge:    "verificação pendente",
+	EventNewProfiles:  "perfis novos",
+	EventPing:         "teste de notificação",
+}
+
+// emailText monta assunto e corpo do email de um payload.
+func emailText(p Payload) (subject, body string) {
+	title := eventTitles[p.Event]
+	if title == "" {
+		title = p.Event
+	}
+	subject = "[GoLinkedIn] " + title
+	var b strings.Builder
+	if p.Job != nil {
+		subject += ": " + p.Job.Query
+		fmt.Fprintf(&b, "Busca: %s\nJob: %s\nStatus: %s\n", p.Job.Query, p.Job.ID, p.Job.Status)
+		if p.Job.Message != "" {
+			fmt.Fprintf(&b, "Mensagem: %s\n", p.Job.Message)
+		}
+		fmt.Fprintf(&b, "Páginas: %d  Perfis: %d  Convites: %d\n", p.Job.Pages, p.Job.Profiles, p.Job.Invites)
+	}
+	switch p.Event {
+	case EventChallenge:
+		fmt.Fprintf(&b, "\nO LinkedIn pediu verificação (%s). Resolva pelo painel de execução da UI.\n", p.Challenge)
+	case EventNewProfiles:
+		fmt.Fprintf(&b, "\n%d perfis que não apareciam em buscas anteriores:\n", p.NewProfilesTotal)
+		for _, r := range p.NewProfiles {
+			fmt.Fprintf(&b, "- %s — %s (%s) %s\n", r.Name, r.Title, r.Company, r.URL)
+		}
+		if extra := p.NewProfilesTotal - len(p.NewProfiles); extra > 0 {
+			fmt.Fprintf(&b, "… e mais %d.\n", extra)
+		}
+	case EventPing:
+		b.WriteString("Se você recebeu isto, as notificações estão funcionando.\n")
+	}
+	if p.Job != nil && p.Job.ExportURL != "" {
+		fmt.Fprintf(&b, "\nCSV: %s\n", p.Job.ExportURL)
+	}
+	return subject, b.String()
+}
@@ -0,0 +1,303 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/results"
+)
+
+// Headers de cada webhook. A assinatura é o HMAC-SHA256 (hex) de
+// "<timestamp>.<corpo>" com o segredo do destino.
+const (
+	HeaderEvent     = "X-GoLinkedIn-Event"
+	HeaderDelivery  = "X-GoLinkedIn-Delivery"
+	HeaderTimestamp = "X-GoLinkedIn-Timestamp"
+	HeaderSignature = "X-GoLinkedIn-Signature"
+)
+
+// maxPayloadProfiles limita os perfis enviados em profiles.new.
+const maxPayloadProfiles = 100
+
+// Event é o que o servidor publica; vira uma entrega por destino interessado.
+type Event struct {
+	Type        string
+	Owner       string
+	Job         jobs.Job
+	Challenge   string        // captcha, checkpoint, 2fa
+	NewProfiles []results.Row // profiles.new
+}
+
+// Payload é o corpo JSON enviado aos webhooks.
+type Payload struct {
+	ID               string        `json:"id"` // igual ao header X-GoLinkedIn-Delivery
+	Event            string        `json:"event"`
+	CreatedAt        time.Time     `json:"created_at"`
+	Job              *Job          `json:"job,omitempty"`
+	Challenge        string        `json:"challenge,omitempty"`
+	NewProfilesTotal int           `json:"new_profiles_total,omitempty"`
+	NewProfiles      []results.Row `json:"new_profiles,omitempty"` // até 100
+}
+
+// Job resume o job no payload.
+type Job struct {
+	ID        string      `json:"id"`
+	Query     string      `json:"query"`
+	Status    jobs.Status `json:"status"`
+	Message   string      `json:"message,omitempty"`
+	Account   string      `json:"account,omitempty"`
+	SearchID  string      `json:"search_id,omitempty"`
+	Pages     int         `json:"pages"`
+	Profiles  int         `json:"profiles"`
+	Invites   int         `json:"invites"`
+	StartedAt time.Time   `json:"started_at,omitzero"`
+	EndedAt   time.Time   `json:"ended_at,omitzero"`
+	ExportURL string      `json:"export_url,omitempty"` // CSV; abre no navegador logado
+}
+
+// Mailer envia um email de texto simples.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type Notifier struct {
+	store   *Store
+	mailer  Mailer // nil = email desligado
+	baseURL string // prefixo dos links (ex.: https://golinkedin.exemplo.com)
+	client  *http.Client
+	wake    chan struct{}
+}
+
+func New(st *Store, mailer Mailer, baseURL string) *Notifier {
+	return &Notifier{
+		store:   st,
+		mailer:  mailer,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// EmailEnabled diz se há SMTP configurado.
+func (n *Notifier) EmailEnabled() bool { return n.mailer != nil }
+
+// Publish enfileira o evento para os destinos de ev.Owner que o assinam.
+func (n *Notifier) Publish(ev Event) {
+	for _, h := range n.store.Hooks(ev.Owner) {
+		if h.wants(ev.Type) {
+			if _, err := n.enqueue(h, ev); err != nil {
+				slog.Warn("enfileirando notificação", "event", ev.Type, "hook", h.ID, "err", err)
+			}
+		}
+	}
+}
+
+// Test envia um ping ao destino, mesmo desativado.
+func (n *Notifier) Test(owner, id string) (Delivery, error) {
+	h, err := n.store.Hook(owner, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	return n.enqueue(h, Event{Type: EventPing, Owner: owner})
+}
+
+func (n *Notifier) enqueue(h Hook, ev Event) (Delivery, error) {
+	now := time.Now()
+	p := Payload{ID: newID(), Event: ev.Type, CreatedAt: now, Challenge: ev.Challenge}
+	if ev.Job.ID != "" {
+		p.Job = n.jobInfo(ev.Job)
+	}
+	if len(ev.NewProfiles) > 0 {
+		p.NewProfilesTotal = len(ev.NewProfiles)
+		p.NewProfiles = ev.NewProfiles[:min(len(ev.NewProfiles), maxPayloadProfiles)]
+	}
+	body, err := json.Marshal(p)
+	if err != nil {
+		return Delivery{}, err
+	}
+	d := Delivery{
+		ID:          p.ID,
+		HookID:      h.ID,
+		Owner:       h.Owner,
+		Event:       ev.Type,
+		Payload:     body,
+		Status:      StatusPending,
+		Attempts:    []Attempt{},
+		NextAttempt: now,
+		CreatedAt:   now,
+	}
+	if err := n.store.addDelivery(d); err != nil {
+		return Delivery{}, err
+	}
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+	return d, nil
+}
+
+func (n *Notifier) jobInfo(j jobs.Job) *Job {
+	info := &Job{
+		ID:        j.ID,
+		Query:     j.Query,
+		Status:    j.Status,
+		Message:   j.Message,
+		Account:   j.AssignedAccount,
+		SearchID:  j.SearchID,
+		Pages:     j.Pages,
+		Profiles:  j.Profiles,
+		Invites:   j.Invites,
+		StartedAt: j.StartedAt,
+		EndedAt:   j.EndedAt,
+	}
+	if j.CSVPath != "" {
+		info.ExportURL = n.baseURL + "/api/v1/exports/" + j.ID
+	}
+	return info
+}
+
+// =============== Entrega ===============
+
+// Run entrega a fila até ctx terminar. Entregas pendentes ficam gravadas e
+// continuam depois de um restart.
+func (n *Notifier) Run(ctx context.Context) {
+	for {
+		n.flush(ctx, time.Now())
+		wait := 30 * time.Second
+		if next := n.store.nextDue(); !next.IsZero() {
+			wait = min(wait, max(time.Until(next), 0))
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+		case <-n.wake:
+			t.Stop()
+		}
+	}
+}
+
+// flush faz uma tentativa de cada entrega vencida.
+func (n *Notifier) flush(ctx context.Context, now time.Time) {
+	for _, d := range n.store.due(now) {
+		h, err := n.store.Hook(d.Owner, d.HookID)
+		if err != nil {
+			continue // destino removido; o log foi junto
+		}
+		start := time.Now()
+		code, permanent, err := n.send(ctx, h, d)
+		if ctx.Err() != nil {
+			return // desligando: a entrega continua pendente
+		}
+		a := Attempt{At: now, StatusCode: code, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			a.Error = err.Error()
+		}
+		_, uerr := n.store.updateDelivery(d.ID, func(d *Delivery) {
+			d.Attempts = append(d.Attempts, a)
+			switch {
+			case err == nil:
+				d.Status, d.NextAttempt = StatusDelivered, time.Time{}
+			case permanent || len(d.Attempts) >= MaxAttempts:
+				d.Status, d.NextAttempt = StatusFailed, time.Time{}
+			default:
+				d.NextAttempt = now.Add(Backoff(len(d.Attempts)))
+			}
+		})
+		if uerr != nil {
+			slog.Warn("gravando entrega", "delivery", d.ID, "err", uerr)
+		}
+		if err != nil {
+			slog.Warn("notificação não entregue", "delivery", d.ID, "event", d.Event, "hook", h.ID, "err", err)
+		}
+	}
+}
+
+// send faz uma tentativa. permanent = não adianta repetir.
+func (n *Notifier) send(ctx context.Context, h Hook, d Delivery) (code int, permanent bool, err error) {
+	switch h.Type {
+	case TypeWebhook:
+		return n.post(ctx, h, d)
+	case TypeEmail:
+		if n.mailer == nil {
+			return 0, true, errors.New("SMTP não configurado no servidor")
+		}
+		var p Payload
+		_ = json.Unmarshal(d.Payload, &p)
+		subject, body := emailText(p)
+		return 0, false, n.mailer.Send(h.Email, subject, body)
+	default:
+		return 0, true, fmt.Errorf("tipo de destino desconhecido: %q", h.Type)
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, h Hook, d Delivery) (int, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, true, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoLinkedIn-Webhook/1")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(h.Secret, ts, d.Payload))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return 0, false, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return resp.StatusCode, false, fmt.Errorf("HTTP %d", resp.StatusCode)
+	default: // 4xx: o destino recusou; repetir não muda nada
+		return resp.StatusCode, true, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+}
+
+// Sign calcula a assinatura (hex) do corpo enviado no timestamp ts.
+func Sign(secret, ts string, body []byte) string {
+	m := hmac.New(sha256.New, []byte(secret))
+	m.Write([]byte(ts))
+	m.Write([]byte("."))
+	m.Write(body)
+	return hex.EncodeToString(m.Sum(nil))
+}
+
+// Verify confere os headers de um webhook recebido; para quem implementa o
+// receptor em Go. maxAge recusa replays antigos (0 = não confere).
+func Verify(secret string, hdr http.Header, body []byte, maxAge time.Duration) bool {
+	ts := hdr.Get(HeaderTimestamp)
+	sig, ok := strings.CutPrefix(hdr.Get(HeaderSignature), "sha256=")
+	if !ok || ts == "" {
+		return false
+	}
+	if maxAge > 0 {
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil || time.Since(time.Unix(sec, 0)).Abs() > maxAge {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(sig), []byte(Sign(secret, ts, body)))
+}
@@ -0,0 +1,304 @@
+// Package notify avisa os usuários sobre os jobs: webhooks com corpo JSON
+// assinado (HMAC-SHA256) e email via SMTP. As entregas ficam numa fila
+// persistida, com novas tentativas e um log por destino.
+package notify
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+var ErrNotFound = errors.New("notificação não encontrada")
+
+// Eventos que um destino pode assinar.
+const (
+	EventJobCompleted = "job.completed"
+	EventJobFailed    = "job.failed"
+	EventChallenge    = "job.challenge_required"
+	EventNewProfiles  = "profiles.new"
+
+	// EventPing só é enviado pelo botão "testar".
+	EventPing = "ping"
+)
+
+var Events = []string{EventJobCompleted, EventJobFailed, EventChallenge, EventNewProfiles}
+
+// Tipos de destino.
+const (
+	TypeWebhook = "webhook"
+	TypeEmail   = "email"
+)
+
+// Estados de uma entrega.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+const (
+	// MaxAttempts é o total de tentativas de uma entrega.
+	MaxAttempts = 6
+	// entregas guardadas por destino (as mais antigas já encerradas saem)
+	maxDeliveriesPerHook = 50
+)
+
+// Hook é um destino de notificações de um usuário.
+type Hook struct {
+	ID        string    `json:"id"`
+	Owner     string    `json:"owner"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url,omitempty"`    // webhook
+	Secret    string    `json:"secret,omitempty"` // webhook: chave do HMAC
+	Email     string    `json:"email,omitempty"`  // email
+	Events    []string  `json:"events"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (h Hook) wants(event string) bool {
+	return h.Enabled && slices.Contains(h.Events, event)
+}
+
+// Delivery é uma notificação para um destino, com todas as tentativas.
+type Delivery struct {
+	ID          string          `json:"id"`
+	HookID      string          `json:"hook_id"`
+	Owner       string          `json:"owner"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    []Attempt       `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt,omitzero"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type Attempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Backoff é a espera depois da n-ésima tentativa falha: 10s, 30s, 1m30s,
+// 4m30s, 13m30s.
+func Backoff(n int) time.Duration {
+	d := 10 * time.Second
+	for i := 1; i < n && d < time.Hour; i++ {
+		d *= 3
+	}
+	return min(d, time.Hour)
+}
+
+// =============== Store ===============
+
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data fileData
+}
+
+type fileData struct {
+	Hooks      []*Hook     `json:"hooks"`
+	Deliveries []*Delivery `json:"deliveries"`
+}
+
+func Open(path string) (*Store, error) {
+	st := &Store{path: path}
+	if err := jsonfile.Load(path, &st.data); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Hooks devolve os destinos de owner, do mais antigo para o mais novo.
+func (st *Store) Hooks(owner string) []Hook {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	out := []Hook{}
+	for _, h := range st.data.Hooks {
+		if h.Owner == owner {
+			out = append(out, *h)
+		}
+	}
+	return out
+}
+
+func (st *Store) Hook(owner, id string) (Hook, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	h := st.hookLocked(owner, id)
+	if h == nil {
+		return Hook{}, ErrNotFound
+	}
+	return *h, nil
+}
+
+// CreateHook grava um destino novo; webhooks sem segredo ganham um aleatório.
+func (st *Store) CreateHook(h Hook) (Hook, error) {
+	h.ID = newID()
+	h.CreatedAt = time.Now()
+	if h.Type == TypeWebhook && h.Secret == "" {
+		h.Secret = NewSecret()
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.data.Hooks = append(st.data.Hooks, &h)
+	if err := st.saveLocked(); err != nil {
+		st.data.Hooks = st.data.Hooks[:len(st.data.Hooks)-1]
+		return Hook{}, err
+	}
+	return h, nil
+}
+
+func (st *Store) UpdateHook(owner, id string, fn func(h *Hook)) (Hook, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	cur := st.hookLocked(owner, id)
+	if cur == nil {
+		return Hook{}, ErrNotFound
+	}
+	h := *cur
+	fn(&h)
+	h.ID, h.Owner, h.Type, h.CreatedAt = cur.ID, cur.Owner, cur.Type, cur.CreatedAt
+
+	prev := *cur
+	*cur = h
+	if err := st.saveLocked(); err != nil {
+		*cur = prev
+		return Hook{}, err
+	}
+	return h, nil
+}
+
+// DeleteHook remove o destino e o seu log de entregas.
+func (st *Store) DeleteHook(owner, id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	i := slices.IndexFunc(st.data.Hooks, func(h *Hook) bool { return h.Owner == owner && h.ID == id })
+	if i < 0 {
+		return ErrNotFound
+	}
+	st.data.Hooks = slices.Delete(st.data.Hooks, i, i+1)
+	st.data.Deliveries = slices.DeleteFunc(st.data.Deliveries, func(d *Delivery) bool { return d.HookID == id })
+	return st.saveLocked()
+}
+
+// Deliveries devolve o log do destino, da entrega mais recente para a mais antiga.
+func (st *Store) Deliveries(owner, hookID string) []Delivery {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	out := []Delivery{}
+	for _, d := range st.data.Deliveries {
+		if d.Owner == owner && d.HookID == hookID {
+			out = append(out, *d)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// addDelivery enfileira d, descartando as entregas encerradas mais antigas
+// do destino além do limite.
+func (st *Store) addDelivery(d Delivery) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.data.Deliveries = append(st.data.Deliveries, &d)
+	n := 0
+	for _, x := range st.data.Deliveries {
+		if x.HookID == d.HookID {
+			n++
+		}
+	}
+	// as mais antigas vêm primeiro
+	st.data.Deliveries = slices.DeleteFunc(st.data.Deliveries, func(x *Delivery) bool {
+		if n > maxDeliveriesPerHook && x.HookID == d.HookID && x.Status != StatusPending {
+			n--
+			return true
+		}
+		return false
+	})
+	return st.saveLocked()
+}
+
+// due devolve as entregas pendentes cuja próxima tentativa já chegou.
+func (st *Store) due(now time.Time) []Delivery {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var out []Delivery
+	for _, d := range st.data.Deliveries {
+		if d.Status == StatusPending && !d.NextAttempt.After(now) {
+			out = append(out, *d)
+		}
+	}
+	return out
+}
+
+// nextDue é o horário da próxima tentativa pendente (zero se não há).
+func (st *Store) nextDue() time.Time {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var next time.Time
+	for _, d := range st.data.Deliveries {
+		if d.Status == StatusPending && (next.IsZero() || d.NextAttempt.Before(next)) {
+			next = d.NextAttempt
+		}
+	}
+	return next
+}
+
+func (st *Store) updateDelivery(id string, fn func(d *Delivery)) (Delivery, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for _, d := range st.data.Deliveries {
+		if d.ID == id {
+			fn(d)
+			return *d, st.saveLocked()
+		}
+	}
+	return Delivery{}, ErrNotFound
+}
+
+func (st *Store) hookLocked(owner, id string) *Hook {
+	for _, h := range st.data.Hooks {
+		if h.Owner == owner && h.ID == id {
+			return h
+		}
+	}
+	return nil
+}
+
+func (st *Store) saveLocked() error {
+	return jsonfile.Save(st.path, st.data)
+}
+
+// NewSecret gera um segredo de webhook (32 bytes em hex).
+func NewSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
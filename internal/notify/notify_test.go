@@ -0,0 +1,324 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/results"
+)
+
+// receiver é o servidor de webhooks de mentira: responde com os códigos de
+// statuses em ordem (depois, 200) e guarda o que recebeu.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	got      []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	rc := &receiver{statuses: statuses}
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		rc.got = append(rc.got, r)
+		rc.bodies = append(rc.bodies, body)
+		status := http.StatusOK
+		if len(rc.statuses) > 0 {
+			status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rc.Close)
+	return rc
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.got)
+}
+
+type fakeMailer struct {
+	to, subject, body string
+}
+
+func (m *fakeMailer) Send(to, subject, body string) error {
+	m.to, m.subject, m.body = to, subject, body
+	return nil
+}
+
+func newNotifier(t *testing.T, mailer Mailer) (*Notifier, *Store) {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "notifications.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mailer == nil {
+		return New(st, nil, "https://golinkedin.test/"), st
+	}
+	return New(st, mailer, "https://golinkedin.test/"), st
+}
+
+func addHook(t *testing.T, st *Store, h Hook) Hook {
+	t.Helper()
+	if h.Owner == "" {
+		h.Owner = "ana"
+	}
+	h.Enabled = true
+	h, err := st.CreateHook(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+var doneJob = jobs.Job{
+	ID: "j1", Owner: "ana", Query: "golang", Status: jobs.StatusDone,
+	AssignedAccount: "rec1", Pages: 2, Profiles: 20, CSVPath: "/data/j1/out.csv",
+}
+
+func TestWebhookSignedDelivery(t *testing.T) {
+	rc := newReceiver(t)
+	n, st := newNotifier(t, nil)
+	h := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: []string{EventJobCompleted}})
+	if len(h.Secret) != 64 {
+		t.Fatalf("segredo gerado = %q", h.Secret)
+	}
+
+	n.Publish(Event{Type: EventJobCompleted, Owner: "ana", Job: doneJob})
+	n.flush(context.Background(), time.Now())
+
+	if rc.count() != 1 {
+		t.Fatalf("recebidos = %d", rc.count())
+	}
+	r, body := rc.got[0], rc.bodies[0]
+	if !Verify(h.Secret, r.Header, body, time.Minute) {
+		t.Error("assinatura não confere")
+	}
+	if Verify("outro-segredo", r.Header, body, 0) {
+		t.Error("assinatura confere com o segredo errado")
+	}
+	if Verify(h.Secret, r.Header, append(body, ' '), 0) {
+		t.Error("assinatura confere com o corpo alterado")
+	}
+	if r.Header.Get(HeaderEvent) != EventJobCompleted || r.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("headers = %v", r.Header)
+	}
+
+	var p Payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.ID != r.Header.Get(HeaderDelivery) || p.Job == nil || p.Job.Profiles != 20 || p.Job.Account != "rec1" ||
+		p.Job.ExportURL != "https://golinkedin.test/api/v1/exports/j1" {
+		t.Errorf("payload = %s", body)
+	}
+
+	log := st.Deliveries("ana", h.ID)
+	if len(log) != 1 || log[0].Status != StatusDelivered || len(log[0].Attempts) != 1 || log[0].Attempts[0].StatusCode != 200 {
+		t.Errorf("log = %+v", log)
+	}
+}
+
+func TestWebhookRetriesWithBackoff(t *testing.T) {
+	rc := newReceiver(t, 500, 503)
+	n, st := newNotifier(t, nil)
+	h := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: Events})
+	ctx := context.Background()
+
+	n.Publish(Event{Type: EventJobFailed, Owner: "ana", Job: doneJob})
+	t0 := time.Now()
+	n.flush(ctx, t0)
+	d := st.Deliveries("ana", h.ID)[0]
+	if d.Status != StatusPending || !d.NextAttempt.Equal(t0.Add(10*time.Second)) {
+		t.Fatalf("após 1ª falha: %+v", d)
+	}
+
+	n.flush(ctx, t0.Add(5*time.Second)) // antes do backoff: nada
+	if rc.count() != 1 {
+		t.Fatalf("tentou antes da hora: %d", rc.count())
+	}
+	n.flush(ctx, t0.Add(10*time.Second))
+	d = st.Deliveries("ana", h.ID)[0]
+	if rc.count() != 2 || !d.NextAttempt.Equal(t0.Add(40*time.Second)) {
+		t.Fatalf("após 2ª falha: %+v", d)
+	}
+	n.flush(ctx, t0.Add(40*time.Second))
+
+	d = st.Deliveries("ana", h.ID)[0]
+	if d.Status != StatusDelivered || len(d.Attempts) != 3 {
+		t.Fatalf("final: %+v", d)
+	}
+	if d.Attempts[0].StatusCode != 500 || d.Attempts[0].Error == "" || d.Attempts[2].Error != "" {
+		t.Errorf("tentativas = %+v", d.Attempts)
+	}
+	// toda tentativa leva o mesmo ID de entrega
+	for _, r := range rc.got {
+		if r.Header.Get(HeaderDelivery) != d.ID {
+			t.Errorf("delivery id = %s, quero %s", r.Header.Get(HeaderDelivery), d.ID)
+		}
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	ctx := context.Background()
+
+	// 4xx não é repetido
+	rc := newReceiver(t, 410)
+	n, st := newNotifier(t, nil)
+	h := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: Events})
+	n.Publish(Event{Type: EventJobCompleted, Owner: "ana", Job: doneJob})
+	n.flush(ctx, time.Now())
+	if d := st.Deliveries("ana", h.ID)[0]; d.Status != StatusFailed || len(d.Attempts) != 1 {
+		t.Errorf("410: %+v", d)
+	}
+
+	// 5xx até esgotar as tentativas
+	statuses := make([]int, MaxAttempts+1)
+	for i := range statuses {
+		statuses[i] = 502
+	}
+	rc = newReceiver(t, statuses...)
+	h = addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: Events})
+	n.Publish(Event{Type: EventJobCompleted, Owner: "ana", Job: doneJob})
+	now := time.Now()
+	for range MaxAttempts + 2 {
+		n.flush(ctx, now)
+		now = now.Add(time.Hour)
+	}
+	if d := st.Deliveries("ana", h.ID)[0]; d.Status != StatusFailed || len(d.Attempts) != MaxAttempts || rc.count() != MaxAttempts {
+		t.Errorf("5xx: status %s, %d tentativas, %d recebidas", d.Status, len(d.Attempts), rc.count())
+	}
+}
+
+func TestPublishFiltersHooks(t *testing.T) {
+	rc := newReceiver(t)
+	n, st := newNotifier(t, nil)
+	failures := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: []string{EventJobFailed}})
+	off := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: Events})
+	if _, err := st.UpdateHook("ana", off.ID, func(h *Hook) { h.Enabled = false }); err != nil {
+		t.Fatal(err)
+	}
+	addHook(t, st, Hook{Owner: "bia", Type: TypeWebhook, URL: rc.URL, Events: Events})
+
+	n.Publish(Event{Type: EventJobCompleted, Owner: "ana", Job: doneJob})
+	n.flush(context.Background(), time.Now())
+	if rc.count() != 0 {
+		t.Fatalf("entregou %d sem ninguém assinar", rc.count())
+	}
+
+	n.Publish(Event{Type: EventJobFailed, Owner: "ana", Job: doneJob})
+	n.flush(context.Background(), time.Now())
+	if rc.count() != 1 || len(st.Deliveries("ana", failures.ID)) != 1 || len(st.Deliveries("ana", off.ID)) != 0 {
+		t.Errorf("recebidos = %d", rc.count())
+	}
+
+	// o teste manda ping mesmo para destino desativado
+	if _, err := n.Test("ana", off.ID); err != nil {
+		t.Fatal(err)
+	}
+	n.flush(context.Background(), time.Now())
+	if rc.count() != 2 || rc.got[1].Header.Get(HeaderEvent) != EventPing {
+		t.Errorf("ping não chegou")
+	}
+	if _, err := n.Test("bia", off.ID); err != ErrNotFound {
+		t.Errorf("ping em destino de outro usuário: %v", err)
+	}
+}
+
+func TestEmailNotification(t *testing.T) {
+	m := &fakeMailer{}
+	n, st := newNotifier(t, m)
+	h := addHook(t, st, Hook{Type: TypeEmail, Email: "ana@exemplo.com", Events: []string{EventNewProfiles, EventChallenge}})
+
+	rows := make([]results.Row, 150)
+	for i := range rows {
+		rows[i] = results.Row{Name: "Pessoa " + strconv.Itoa(i), URL: fmt.Sprintf("https://www.linkedin.com/in/p%d", i)}
+	}
+	n.Publish(Event{Type: EventNewProfiles, Owner: "ana", Job: doneJob, NewProfiles: rows})
+	n.flush(context.Background(), time.Now())
+
+	if m.to != "ana@exemplo.com" || !strings.Contains(m.subject, "perfis novos") || !strings.Contains(m.subject, "golang") {
+		t.Errorf("email: to=%q subject=%q", m.to, m.subject)
+	}
+	if !strings.Contains(m.body, "150 perfis") || !strings.Contains(m.body, "e mais 50") || !strings.Contains(m.body, "/in/p99") {
+		t.Errorf("corpo:\n%s", m.body)
+	}
+	d := st.Deliveries("ana", h.ID)[0]
+	var p Payload
+	_ = json.Unmarshal(d.Payload, &p)
+	if d.Status != StatusDelivered || p.NewProfilesTotal != 150 || len(p.NewProfiles) != maxPayloadProfiles {
+		t.Errorf("entrega = %s, %d/%d perfis", d.Status, len(p.NewProfiles), p.NewProfilesTotal)
+	}
+
+	// sem SMTP o email falha de vez, sem novas tentativas
+	n2, st2 := newNotifier(t, nil)
+	h2 := addHook(t, st2, Hook{Type: TypeEmail, Email: "ana@exemplo.com", Events: Events})
+	n2.Publish(Event{Type: EventChallenge, Owner: "ana", Job: doneJob, Challenge: "captcha"})
+	n2.flush(context.Background(), time.Now())
+	if d := st2.Deliveries("ana", h2.ID)[0]; d.Status != StatusFailed {
+		t.Errorf("sem SMTP: %+v", d)
+	}
+}
+
+func TestDeliveryLogIsTrimmed(t *testing.T) {
+	rc := newReceiver(t)
+	n, st := newNotifier(t, nil)
+	h := addHook(t, st, Hook{Type: TypeWebhook, URL: rc.URL, Events: Events})
+	for range maxDeliveriesPerHook + 5 {
+		if _, err := n.Test("ana", h.ID); err != nil {
+			t.Fatal(err)
+		}
+		n.flush(context.Background(), time.Now())
+	}
+	if got := len(st.Deliveries("ana", h.ID)); got != maxDeliveriesPerHook {
+		t.Errorf("log com %d entregas", got)
+	}
+
+	if err := st.DeleteHook("ana", h.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(st.Deliveries("ana", h.ID)); got != 0 {
+		t.Errorf("log sobrou depois de remover o destino: %d", got)
+	}
+}
+
+func TestVerifyRejectsOldTimestamp(t *testing.T) {
+	body := []byte(`{"event":"ping"}`)
+	ts := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	hdr := http.Header{}
+	hdr.Set(HeaderTimestamp, ts)
+	hdr.Set(HeaderSignature, "sha256="+Sign("s3cr3t", ts, body))
+	if !Verify("s3cr3t", hdr, body, 0) {
+		t.Error("assinatura válida recusada")
+	}
+	if Verify("s3cr3t", hdr, body, 5*time.Minute) {
+		t.Error("aceitou timestamp de 1h atrás")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	want := []time.Duration{10 * time.Second, 30 * time.Second, 90 * time.Second, 270 * time.Second, 810 * time.Second}
+	for i, w := range want {
+		if got := Backoff(i + 1); got != w {
+			t.Errorf("Backoff(%d) = %v, quero %v", i+1, got, w)
+		}
+	}
+	if got := Backoff(20); got != time.Hour {
+		t.Errorf("Backoff(20) = %v", got)
+	}
+}
@@ -0,0 +1,158 @@
+// Package quality é o relatório de qualidade de cada execução (quality.json
+// na pasta de saída): quantos perfis vieram com campos vazios, nomes tirados
+// da URL, região ou empresa suspeitas, repetidos descartados e páginas sem
+// nenhum card. Passar de um limite marca a execução como degradada, o sinal
+// de que o LinkedIn mudou a página e a extração precisa de ajuste.
+package quality
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"CrawlerLinkedin/internal/company"
+	"CrawlerLinkedin/internal/jsonfile"
+	"CrawlerLinkedin/internal/results"
+)
+
+const FileName = "quality.json"
+
+// Nomes das verificações, na ordem do relatório.
+const (
+	EmptyPages      = "empty_pages" // páginas sem nenhum card (% das páginas)
+	Duplicates      = "duplicates"  // cards repetidos descartados (% dos cards)
+	EmptyName       = "empty_name"  // o resto é % dos perfis
+	EmptyTitle      = "empty_title"
+	EmptyLocation   = "empty_location"
+	EmptyCompany    = "empty_company"
+	NameFromURL     = "name_from_url"     // nome montado a partir da URL
+	LocationIsTitle = "location_is_title" // região igual ao título (apagada)
+	SuspectLocation = "suspect_location"  // região que não parece região
+	SuspectCompany  = "suspect_company"   // empresa que parece região ou repete o título
+)
+
+var labels = map[string]string{
+	EmptyPages:      "páginas sem cards",
+	Duplicates:      "repetidos descartados",
+	EmptyName:       "nome vazio",
+	EmptyTitle:      "título vazio",
+	EmptyLocation:   "região vazia",
+	EmptyCompany:    "empresa vazia",
+	NameFromURL:     "nome tirado da URL",
+	LocationIsTitle: "região igual ao título",
+	SuspectLocation: "região suspeita",
+	SuspectCompany:  "empresa suspeita",
+}
+
+// Thresholds são os limites, em % (0 a 100): passar de um marca a execução
+// como degradada; 100 desliga a verificação. Os campos batem com
+// config.Quality, que converte direto para este tipo.
+type Thresholds struct {
+	MinProfiles        int // abaixo disso só páginas sem cards contam (amostra pequena demais)
+	MaxEmptyPages      int
+	MaxDuplicates      int
+	MaxEmptyName       int
+	MaxEmptyTitle      int
+	MaxEmptyLocation   int
+	MaxEmptyCompany    int
+	MaxNameFromURL     int
+	MaxLocationIsTitle int
+	MaxSuspectLocation int
+	MaxSuspectCompany  int
+}
+
+// Tally é o que só a coleta sabe; o crawler conta página a página.
+type Tally struct {
+	Pages           int // páginas lidas
+	EmptyPages      int // páginas lidas sem nenhum card
+	Duplicates      int // cards repetidos, na página ou entre páginas
+	NameFromURL     int
+	LocationIsTitle int
+}
+
+// Check é uma verificação do relatório.
+type Check struct {
+	Name   string  `json:"name"`
+	Count  int     `json:"count"`
+	Pct    float64 `json:"pct"`
+	MaxPct int     `json:"max_pct"`
+	Over   bool    `json:"over,omitempty"` // passou do limite
+}
+
+type Report struct {
+	Pages    int      `json:"pages"`
+	Profiles int      `json:"profiles"`
+	Checks   []Check  `json:"checks"`
+	Degraded bool     `json:"degraded"`
+	Reasons  []string `json:"reasons,omitempty"` // verificações que passaram do limite, para humanos
+}
+
+// Build monta o relatório a partir das contagens da coleta e dos perfis
+// finais (já sem repetidos).
+func Build(t Tally, rows []results.Row, th Thresholds) Report {
+	r := Report{Pages: t.Pages, Profiles: len(rows)}
+	var emptyName, emptyTitle, emptyLocation, emptyCompany, suspectLocation, suspectCompany int
+	for _, row := range rows {
+		if row.Name == "" {
+			emptyName++
+		}
+		if row.Title == "" {
+			emptyTitle++
+		}
+		if row.Location == "" {
+			emptyLocation++
+		} else if !company.LooksLikeLocation(row.Location) {
+			suspectLocation++
+		}
+		if row.Company == "" {
+			emptyCompany++
+		} else if suspiciousCompany(row) {
+			suspectCompany++
+		}
+	}
+
+	sample := len(rows) >= th.MinProfiles
+	add := func(name string, count, total, max int, judge bool) {
+		c := Check{Name: name, Count: count, MaxPct: max}
+		if total > 0 {
+			c.Pct = math.Round(float64(count)*1000/float64(total)) / 10
+		}
+		c.Over = judge && total > 0 && c.Pct > float64(max)
+		if c.Over {
+			r.Degraded = true
+			r.Reasons = append(r.Reasons, fmt.Sprintf("%s: %g%% (máx. %d%%)", labels[name], c.Pct, max))
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	add(EmptyPages, t.EmptyPages, t.Pages, th.MaxEmptyPages, true)
+	add(Duplicates, t.Duplicates, len(rows)+t.Duplicates, th.MaxDuplicates, sample)
+	add(EmptyName, emptyName, len(rows), th.MaxEmptyName, sample)
+	add(EmptyTitle, emptyTitle, len(rows), th.MaxEmptyTitle, sample)
+	add(EmptyLocation, emptyLocation, len(rows), th.MaxEmptyLocation, sample)
+	add(EmptyCompany, emptyCompany, len(rows), th.MaxEmptyCompany, sample)
+	add(NameFromURL, t.NameFromURL, len(rows), th.MaxNameFromURL, sample)
+	add(LocationIsTitle, t.LocationIsTitle, len(rows), th.MaxLocationIsTitle, sample)
+	add(SuspectLocation, suspectLocation, len(rows), th.MaxSuspectLocation, sample)
+	add(SuspectCompany, suspectCompany, len(rows), th.MaxSuspectCompany, sample)
+	return r
+}
+
+// suspiciousCompany: empresa que parece região ou é o próprio título. A do
+// filtro "Empresa atual" vem do LinkedIn e não entra ("Porto Seguro").
+func suspiciousCompany(row results.Row) bool {
+	if row.CompanySource == company.SourceFacet {
+		return false
+	}
+	c := results.Fold(row.Company)
+	return company.LooksLikeLocation(row.Company) || c == results.Fold(row.Location) || c == results.Fold(row.Title)
+}
+
+func Write(dir string, r Report) error {
+	return jsonfile.Save(filepath.Join(dir, FileName), r)
+}
+
+// Read lê o relatório de dir; ok = false quando não há relatório.
+func Read(dir string) (r Report, ok bool, err error) {
+	err = jsonfile.Load(filepath.Join(dir, FileName), &r)
+	return r, err == nil && r.Checks != nil, err
+}
@@ -0,0 +1,86 @@
+package quality
+
+import (
+	"os"
+	"testing"
+
+	"CrawlerLinkedin/internal/company"
+	"CrawlerLinkedin/internal/results"
+)
+
+var limits = Thresholds{
+	MinProfiles: 4, MaxEmptyPages: 0, MaxDuplicates: 30, MaxEmptyName: 5, MaxEmptyTitle: 20, MaxEmptyLocation: 50,
+	MaxEmptyCompany: 90, MaxNameFromURL: 20, MaxLocationIsTitle: 10, MaxSuspectLocation: 10, MaxSuspectCompany: 20,
+}
+
+func check(r Report, name string) Check {
+	for _, c := range r.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	return Check{}
+}
+
+func TestBuild(t *testing.T) {
+	rows := []results.Row{
+		{Name: "Ana Souza", Title: "Engenheira at Nubank", Company: "Nubank", CompanySource: company.SourceHeadline, Location: "São Paulo, SP"},
+		{Name: "Bia Lima", Title: "", Company: "", Location: "Recife"},
+		{Name: "Caio Reis", Title: "Dev", Company: "São Paulo", CompanySource: company.SourceSubtitle, Location: "Engenheiro de Dados"},
+		{Name: "Davi Rocha", Title: "Dev", Company: "Porto Seguro", CompanySource: company.SourceFacet, Location: "Brasil"},
+	}
+	r := Build(Tally{Pages: 2, Duplicates: 1, NameFromURL: 1}, rows, limits)
+
+	for name, want := range map[string]Check{
+		EmptyPages:      {Name: EmptyPages, Count: 0, Pct: 0, MaxPct: 0},
+		Duplicates:      {Name: Duplicates, Count: 1, Pct: 20, MaxPct: 30},
+		EmptyTitle:      {Name: EmptyTitle, Count: 1, Pct: 25, MaxPct: 20, Over: true},
+		EmptyCompany:    {Name: EmptyCompany, Count: 1, Pct: 25, MaxPct: 90},
+		NameFromURL:     {Name: NameFromURL, Count: 1, Pct: 25, MaxPct: 20, Over: true},
+		SuspectLocation: {Name: SuspectLocation, Count: 1, Pct: 25, MaxPct: 10, Over: true},
+		SuspectCompany:  {Name: SuspectCompany, Count: 1, Pct: 25, MaxPct: 20, Over: true}, // o do filtro não conta
+	} {
+		if got := check(r, name); got != want {
+			t.Errorf("%s = %+v, quero %+v", name, got, want)
+		}
+	}
+	if !r.Degraded || len(r.Reasons) != 4 {
+		t.Fatalf("Degraded = %v, Reasons = %q", r.Degraded, r.Reasons)
+	}
+	if r.Reasons[0] != "título vazio: 25% (máx. 20%)" {
+		t.Errorf("Reasons[0] = %q", r.Reasons[0])
+	}
+}
+
+func TestBuildSmallSample(t *testing.T) {
+	rows := []results.Row{{Name: "Ana Souza"}} // tudo vazio, mas é um perfil só
+	if r := Build(Tally{Pages: 1}, rows, limits); r.Degraded {
+		t.Errorf("amostra pequena marcou degradada: %q", r.Reasons)
+	}
+	// página sem cards conta mesmo sem amostra
+	r := Build(Tally{Pages: 2, EmptyPages: 1}, rows, limits)
+	if !r.Degraded || r.Reasons[0] != "páginas sem cards: 50% (máx. 0%)" {
+		t.Errorf("Degraded = %v, Reasons = %q", r.Degraded, r.Reasons)
+	}
+	if r := Build(Tally{}, nil, limits); r.Degraded {
+		t.Errorf("execução sem páginas marcou degradada: %q", r.Reasons)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	dir := t.TempDir()
+	if _, ok, err := Read(dir); ok || err != nil {
+		t.Fatalf("sem arquivo: ok = %v, err = %v", ok, err)
+	}
+	want := Build(Tally{Pages: 1, EmptyPages: 1}, nil, limits)
+	if err := Write(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := Read(dir)
+	if err != nil || !ok || got.Degraded != want.Degraded || len(got.Checks) != len(want.Checks) {
+		t.Fatalf("Read = %+v, %v, %v", got, ok, err)
+	}
+	if _, err := os.Stat(dir + "/" + FileName); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,344 @@
+// Package results lê, grava e compara os CSVs gerados pelo crawler.
+package results
+
+import (
+	"encoding/csv"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"CrawlerLinkedin/internal/names"
+)
+
+// Header é o cabeçalho dos CSVs do crawler, na ordem das colunas.
+var Header = []string{"name", "title", "company", "location", "role", "url", "source_query", "captured_at", "seniority", "function", "company_source", "company_confidence", "first_name", "last_name"}
+
+// TimeLayout é o formato de captured_at.
+const TimeLayout = "2006-01-02 15:04:05"
+
+// Row é uma linha do CSV do crawler.
+type Row struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Company     string `json:"company"`
+	Location    string `json:"location"`
+	Role        string `json:"role"`
+	URL         string `json:"url"`
+	SourceQuery string `json:"source_query"`
+	CapturedAt  string `json:"captured_at"`
+	Seniority   string `json:"seniority"` // do classificador (internal/classify); vazio = não deu para dizer
+	Function    string `json:"function"`
+	// de onde veio Company (internal/company) e a confiança, de 0 a 1
+	CompanySource     string  `json:"company_source"`
+	CompanyConfidence float64 `json:"company_confidence"`
+	// de names.Parse(Name); CSVs antigos sem as colunas são preenchidos na leitura
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// ReadCSV lê até limit linhas de path (limit <= 0 = todas). As colunas são
+// achadas pelo cabeçalho, então CSVs antigos com menos colunas continuam lendo.
+func ReadCSV(path string, limit int) ([]Row, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	idx := map[string]int{}
+	for i, h := range records[0] {
+		h = strings.TrimPrefix(h, "\ufeff") // writeCSV grava BOM para o Excel
+		idx[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	var out []Row
+	for i := 1; i < len(records) && (limit <= 0 || len(out) < limit); i++ {
+		rec := records[i]
+		get := func(k string) string {
+			j, ok := idx[k]
+			if !ok || j >= len(rec) {
+				return ""
+			}
+			return rec[j]
+		}
+		out = append(out, Row{
+			Name:        get("name"),
+			Title:       get("title"),
+			Company:     get("company"),
+			Location:    get("location"),
+			Role:        get("role"),
+			URL:         get("url"),
+			SourceQuery: get("source_query"),
+			CapturedAt:  get("captured_at"),
+			Seniority:   get("seniority"),
+			Function:    get("function"),
+
+			CompanySource:     get("company_source"),
+			CompanyConfidence: confidence(get("company_confidence")),
+
+			FirstName: get("first_name"),
+			LastName:  get("last_name"),
+		})
+		if _, ok := idx["first_name"]; !ok {
+			n := names.Parse(get("name"))
+			out[len(out)-1].FirstName, out[len(out)-1].LastName = n.First, n.Last
+		}
+	}
+	return out, nil
+}
+
+func (r Row) record() []string {
+	conf := ""
+	if r.CompanySource != "" {
+		conf = strconv.FormatFloat(r.CompanyConfidence, 'f', 2, 64)
+	}
+	return []string{r.Name, r.Title, r.Company, r.Location, r.Role, r.URL, r.SourceQuery, r.CapturedAt, r.Seniority, r.Function, r.CompanySource, conf, r.FirstName, r.LastName}
+}
+
+// confidence lê company_confidence; vazio ou inválido = 0.
+func confidence(s string) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// WriteCSV grava as linhas no formato do crawler, com BOM (o Excel reconhece UTF-8).
+func WriteCSV(w io.Writer, rows []Row) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Header); err != nil {
+		return err
+	}
+	for _, r := range rows {
+		if err := cw.Write(r.record()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// =============== Merge e diff ===============
+
+// Key identifica o perfil de uma linha: a URL sem query, barra final nem
+// diferença de maiúsculas. Vazia quando a linha não tem URL.
+func Key(r Row) string {
+	raw := strings.TrimSpace(r.URL)
+	if u, err := url.Parse(raw); err == nil && u.Host != "" {
+		raw = u.Host + u.Path
+	}
+	return strings.ToLower(strings.TrimRight(raw, "/"))
+}
+
+// Merge junta listas sem repetir perfil: fica a captura mais recente (empate:
+// a da lista que vem depois), na posição em que o perfil apareceu primeiro.
+// Linhas sem URL entram todas.
+func Merge(lists ...[]Row) []Row {
+	var out []Row
+	pos := map[string]int{}
+	for _, rows := range lists {
+		for _, r := range rows {
+			k := Key(r)
+			if k == "" {
+				out = append(out, r)
+				continue
+			}
+			i, seen := pos[k]
+			switch {
+			case !seen:
+				pos[k] = len(out)
+				out = append(out, r)
+			case r.CapturedAt >= out[i].CapturedAt: // TimeLayout ordena como texto
+				out[i] = r
+			}
+		}
+	}
+	return out
+}
+
+// Change é um campo que mudou entre duas capturas do mesmo perfil.
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Changed é um perfil presente nos dois CSVs com campos diferentes.
+type Changed struct {
+	Row     Row      `json:"row"`
+	Changes []Change `json:"changes"`
+}
+
+// Diff é o resultado de Compare.
+type Diff struct {
+	Added   []Row     `json:"added"`
+	Removed []Row     `json:"removed"`
+	Changed []Changed `json:"changed"`
+}
+
+// Compare casa os perfis pela URL (Key) e lista os novos, os que saíram e os
+// que mudaram de nome, título, empresa, região ou cargo.
+func Compare(old, cur []Row) Diff {
+	d := Diff{Added: []Row{}, Removed: []Row{}, Changed: []Changed{}}
+	before := map[string]Row{}
+	for _, r := range Merge(old) {
+		if k := Key(r); k != "" {
+			before[k] = r
+		}
+	}
+	seen := map[string]bool{}
+	for _, r := range Merge(cur) {
+		k := Key(r)
+		if k == "" {
+			continue
+		}
+		seen[k] = true
+		prev, ok := before[k]
+		if !ok {
+			d.Added = append(d.Added, r)
+			continue
+		}
+		var changes []Change
+		for _, f := range []struct {
+			name     string
+			old, new string
+		}{
+			{"name", prev.Name, r.Name},
+			{"title", prev.Title, r.Title},
+			{"company", prev.Company, r.Company},
+			{"location", prev.Location, r.Location},
+			{"role", prev.Role, r.Role},
+		} {
+			if f.old != f.new {
+				changes = append(changes, Change{f.name, f.old, f.new})
+			}
+		}
+		if len(changes) > 0 {
+			d.Changed = append(d.Changed, Changed{r, changes})
+		}
+	}
+	for _, r := range Merge(old) {
+		if k := Key(r); k != "" && !seen[k] {
+			d.Removed = append(d.Removed, r)
+		}
+	}
+	return d
+}
+
+// =============== Busca ===============
+
+// Query filtra, ordena e pagina linhas. Filtros comparam por "contém", sem
+// diferenciar maiúsculas nem acentos.
+type Query struct {
+	Q        string // texto livre: nome, título, empresa, região ou cargo
+	Company  string
+	Location string
+	Title    string
+	// Seniority e Function casam o rótulo exato; vírgula separa alternativas
+	// ("senior,lead").
+	Seniority string
+	Function  string
+	Sort      string // campo de SortFields; vazio = ordem do CSV
+	Desc      bool
+	Offset    int
+	Limit     int // <= 0 = sem limite
+}
+
+// SortFields são os campos aceitos em Query.Sort.
+var SortFields = map[string]func(Row) string{
+	"name":        func(r Row) string { return r.Name },
+	"title":       func(r Row) string { return r.Title },
+	"company":     func(r Row) string { return r.Company },
+	"location":    func(r Row) string { return r.Location },
+	"captured_at": func(r Row) string { return r.CapturedAt },
+}
+
+// Apply devolve a página pedida e o total de linhas que passaram nos filtros.
+func Apply(rows []Row, q Query) ([]Row, int) {
+	text, company, location, title := Fold(q.Q), Fold(q.Company), Fold(q.Location), Fold(q.Title)
+	seniority, function := labelSet(q.Seniority), labelSet(q.Function)
+	out := make([]Row, 0, len(rows))
+	for _, r := range rows {
+		if seniority != nil && !seniority[r.Seniority] {
+			continue
+		}
+		if function != nil && !function[r.Function] {
+			continue
+		}
+		if company != "" && !strings.Contains(Fold(r.Company), company) {
+			continue
+		}
+		if location != "" && !strings.Contains(Fold(r.Location), location) {
+			continue
+		}
+		if title != "" && !strings.Contains(Fold(r.Title), title) {
+			continue
+		}
+		if text != "" && !strings.Contains(Fold(r.Name+"\n"+r.Title+"\n"+r.Company+"\n"+r.Location+"\n"+r.Role), text) {
+			continue
+		}
+		out = append(out, r)
+	}
+
+	if key, ok := SortFields[q.Sort]; ok {
+		sort.SliceStable(out, func(i, j int) bool {
+			a, b := Fold(key(out[i])), Fold(key(out[j]))
+			if q.Desc {
+				return a > b
+			}
+			return a < b
+		})
+	}
+
+	total := len(out)
+	start := min(max(q.Offset, 0), total)
+	end := total
+	if q.Limit > 0 {
+		end = min(start+q.Limit, total)
+	}
+	return out[start:end], total
+}
+
+// labelSet separa "senior, lead" em {senior, lead}; nil = sem filtro.
+func labelSet(s string) map[string]bool {
+	var set map[string]bool
+	for _, l := range strings.Split(s, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			if set == nil {
+				set = map[string]bool{}
+			}
+			set[l] = true
+		}
+	}
+	return set
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+)
+
+// Fold normaliza para comparação: minúsculas, sem acento, sem espaço nas pontas.
+func Fold(s string) string {
+	return accents.Replace(strings.ToLower(strings.TrimSpace(s)))
+}
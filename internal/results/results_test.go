@@ -0,0 +1,84 @@
+package results
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReadCSV(t *testing.T) {
+	rows := []Row{{Name: "Ana, a", Title: "Dev \"Go\"", URL: "https://www.linkedin.com/in/ana", CapturedAt: "2026-01-02 03:04:05",
+		Company: "Acme", CompanySource: "headline", CompanyConfidence: 0.8}}
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), "x.csv")
+	os.WriteFile(p, buf.Bytes(), 0o600)
+	got, err := ReadCSV(p, 0)
+	if err != nil || len(got) != 1 || got[0] != rows[0] {
+		t.Errorf("ida e volta = %+v, %v", got, err)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := []Row{
+		{Name: "Ana", URL: "https://www.linkedin.com/in/ana/", CapturedAt: "2026-01-01 10:00:00"},
+		{Name: "Bia", URL: "https://www.linkedin.com/in/bia", CapturedAt: "2026-01-05 10:00:00"},
+		{Name: "Sem URL"},
+	}
+	b := []Row{
+		{Name: "Ana Souza", URL: "https://WWW.linkedin.com/in/ana?miniProfile=1", CapturedAt: "2026-01-03 10:00:00"},
+		{Name: "Bia antiga", URL: "https://www.linkedin.com/in/bia", CapturedAt: "2026-01-02 10:00:00"},
+		{Name: "Caio", URL: "https://www.linkedin.com/in/caio", CapturedAt: "2026-01-03 10:00:00"},
+		{Name: "Sem URL"},
+	}
+	got := Merge(a, b)
+	var names []string
+	for _, r := range got {
+		names = append(names, r.Name)
+	}
+	want := []string{"Ana Souza", "Bia", "Sem URL", "Caio", "Sem URL"}
+	if len(names) != len(want) {
+		t.Fatalf("Merge = %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Merge = %v, quero %v", names, want)
+			break
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := []Row{
+		{Name: "Ana", Title: "Dev", Company: "X", URL: "https://www.linkedin.com/in/ana"},
+		{Name: "Bia", Title: "QA", URL: "https://www.linkedin.com/in/bia"},
+		{Name: "Caio", Title: "PM", URL: "https://www.linkedin.com/in/caio"},
+	}
+	cur := []Row{
+		{Name: "Ana", Title: "Tech Lead", Company: "Y", URL: "https://www.linkedin.com/in/ana/"},
+		{Name: "Caio", Title: "PM", URL: "https://www.linkedin.com/in/caio"},
+		{Name: "Duda", URL: "https://www.linkedin.com/in/duda"},
+	}
+	d := Compare(old, cur)
+	if len(d.Added) != 1 || d.Added[0].Name != "Duda" {
+		t.Errorf("novos = %+v", d.Added)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].Name != "Bia" {
+		t.Errorf("saíram = %+v", d.Removed)
+	}
+	if len(d.Changed) != 1 || len(d.Changed[0].Changes) != 2 || d.Changed[0].Changes[0] != (Change{"title", "Dev", "Tech Lead"}) {
+		t.Errorf("alterados = %+v", d.Changed)
+	}
+}
+
+func TestReadOldCSV(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "old.csv")
+	os.WriteFile(p, []byte("name,title,url\nDra. Maria da Silva,CTO,https://www.linkedin.com/in/maria\n"), 0o600)
+	got, err := ReadCSV(p, 0)
+	if err != nil || len(got) != 1 || got[0].Name != "Dra. Maria da Silva" || got[0].FirstName != "Maria" || got[0].LastName != "Silva" {
+		t.Errorf("CSV sem first_name/last_name = %+v, %v", got, err)
+	}
+}
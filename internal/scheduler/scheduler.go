@@ -0,0 +1,303 @@
+// Package scheduler distribui os jobs da fila entre as contas do LinkedIn do
+// dono do job, respeitando orçamento diário, cooldown e uma execução por conta.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/summary"
+)
+
+// Budget é o quanto a execução pode consumir da conta.
+type Budget struct {
+	Pages   int
+	Invites int // -1 = a conta não tem cota de convites
+}
+
+// Result é o que o RunFunc devolve ao terminar.
+type Result struct {
+	Summary summary.Summary
+	Err     error
+}
+
+// RunFunc executa o job com a conta escolhida e bloqueia até o fim.
+type RunFunc func(ctx context.Context, j jobs.Job, a accounts.Account, b Budget) Result
+
+// DoneFunc é chamada com o job já atualizado (status final, contadores).
+type DoneFunc func(j jobs.Job)
+
+const pollInterval = 15 * time.Second
+
+// ErrCancelled é a causa do cancelamento pedido pelo usuário; sem ela (ctx
+// do Run encerrado) o job foi interrompido pelo desligamento do servidor.
+var ErrCancelled = errors.New("cancelado pelo usuário")
+
+type Scheduler struct {
+	jobs     *jobs.Store
+	accounts *accounts.Registry
+	run      RunFunc
+	done     DoneFunc
+	wake     chan struct{}
+
+	mu      sync.Mutex
+	busy    map[string]bool // owner + "/" + alias
+	running map[string]context.CancelCauseFunc
+	wg      sync.WaitGroup
+}
+
+func New(js *jobs.Store, ar *accounts.Registry, run RunFunc, done DoneFunc) *Scheduler {
+	return &Scheduler{
+		jobs:     js,
+		accounts: ar,
+		run:      run,
+		done:     done,
+		wake:     make(chan struct{}, 1),
+		busy:     map[string]bool{},
+		running:  map[string]context.CancelCauseFunc{},
+	}
+}
+
+// Wake pede uma nova rodada de distribuição (ex.: job novo na fila).
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run distribui jobs até ctx terminar e então espera as execuções em curso.
+func (s *Scheduler) Run(ctx context.Context) {
+	t := time.NewTicker(pollInterval)
+	defer t.Stop()
+	for {
+		s.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+			return
+		case <-t.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *Scheduler) dispatch(ctx context.Context) {
+	now := time.Now()
+	for _, j := range s.jobs.Queued() {
+		acct, budget, why := s.pick(j, now)
+		if why != "" {
+			if j.Message != why {
+				_, _ = s.jobs.Update(j.ID, func(j *jobs.Job) { j.Message = why })
+			}
+			continue
+		}
+
+		key := acct.Owner + "/" + acct.Alias
+		jctx, jcancel := context.WithCancelCause(ctx)
+		s.mu.Lock()
+		s.busy[key] = true
+		s.running[j.ID] = jcancel
+		s.mu.Unlock()
+
+		ok := false
+		started, err := s.jobs.Update(j.ID, func(j *jobs.Job) {
+			if j.Status != jobs.StatusQueued { // cancelado enquanto escolhíamos a conta
+				return
+			}
+			j.Status = jobs.StatusRunning
+			j.AssignedAccount = acct.Alias
+			j.Message = ""
+			j.StartedAt = now
+			ok = true
+		})
+		if err != nil || !ok {
+			if err != nil {
+				slog.Warn("iniciando job", "job_id", j.ID, "err", err)
+			}
+			s.release(key, j.ID)
+			jcancel(nil)
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.release(key, started.ID)
+			res := s.run(jctx, started, acct, budget)
+			s.finish(started, acct, res, context.Cause(jctx))
+			jcancel(nil)
+		}()
+	}
+}
+
+// pick escolhe a conta livre com mais páginas restantes hoje (ou, para um
+// job de convites, com mais convites restantes). Se nenhuma servir, devolve
+// o motivo para mostrar no job.
+func (s *Scheduler) pick(j jobs.Job, now time.Time) (accounts.Account, Budget, string) {
+	var cands []accounts.Status
+	if j.Account != "" {
+		st, err := s.accounts.Status(j.Owner, j.Account, now)
+		if err != nil {
+			return accounts.Account{}, Budget{}, "conta " + j.Account + " não existe"
+		}
+		cands = []accounts.Status{st}
+	} else {
+		cands = s.accounts.List(j.Owner, now)
+		if len(cands) == 0 {
+			return accounts.Account{}, Budget{}, "nenhuma conta cadastrada"
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	left := func(c *accounts.Status) int {
+		switch {
+		case !j.IsInvite():
+			return c.RemainingPages
+		case c.RemainingInvites < 0:
+			return math.MaxInt // sem cota
+		}
+		return c.RemainingInvites
+	}
+	var best *accounts.Status
+	why := "aguardando conta livre"
+	for i := range cands {
+		c := &cands[i]
+		switch {
+		case s.busy[c.Owner+"/"+c.Alias]:
+		case c.CoolingDown:
+			why = "conta em cooldown até " + c.CooldownUntil.Local().Format("02/01 15:04")
+		case j.IsInvite() && c.InvitesPaused:
+			why = "convites da conta pausados até " + c.InvitesPausedUntil.Local().Format("02/01 15:04") + " (limite semanal do LinkedIn)"
+		case j.IsInvite() && c.RemainingInvites == 0:
+			why = "cota de convites da conta esgotada"
+		case !j.IsInvite() && c.RemainingPages <= 0:
+			why = "orçamento diário de páginas esgotado"
+		default:
+			if best == nil || left(c) > left(best) {
+				best = c
+			}
+		}
+	}
+	if best == nil {
+		return accounts.Account{}, Budget{}, why
+	}
+	if j.IsInvite() {
+		return best.Account, Budget{Invites: best.RemainingInvites}, ""
+	}
+	return best.Account, Budget{Pages: min(max(j.MaxPages, 1), best.RemainingPages)}, ""
+}
+
+// Cancel interrompe o job: se está na fila, sai dela; se está rodando, o
+// contexto da execução é cancelado (o RunFunc deve parar e guardar o parcial).
+// Devolve false se o job não existe ou já terminou.
+func (s *Scheduler) Cancel(id string) bool {
+	for range 2 {
+		s.mu.Lock()
+		cancel, running := s.running[id]
+		s.mu.Unlock()
+		if running {
+			cancel(ErrCancelled)
+			return true
+		}
+
+		var dequeued bool
+		final, err := s.jobs.Update(id, func(j *jobs.Job) {
+			if j.Status == jobs.StatusQueued {
+				j.Status = jobs.StatusCancelled
+				j.Message = "cancelado antes de iniciar"
+				j.EndedAt = time.Now()
+				dequeued = true
+			}
+		})
+		if err != nil {
+			return false
+		}
+		if dequeued {
+			if s.done != nil {
+				s.done(final)
+			}
+			return true
+		}
+		if final.Status.Finished() {
+			return false
+		}
+		// saiu da fila agora há pouco: na segunda volta já está em running
+	}
+	return false
+}
+
+func (s *Scheduler) finish(j jobs.Job, acct accounts.Account, res Result, cause error) {
+	now := time.Now()
+	sum := res.Summary
+	if err := s.accounts.RecordUsage(acct.Owner, acct.Alias, now, sum.Pages); err != nil {
+		slog.Warn("registrando uso da conta", "account", acct.Alias, "err", err)
+	}
+	if sum.NeedsCooldown() {
+		until, err := s.accounts.StartCooldown(acct.Owner, acct.Alias, now)
+		if err != nil {
+			slog.Warn("cooldown da conta", "account", acct.Alias, "err", err)
+		} else {
+			slog.Warn("conta em cooldown", "account", acct.Alias, "until", until.Format(time.RFC3339), "challenges", sum.Challenges)
+		}
+	}
+
+	if sum.InviteLimit {
+		// o aviso já está no histórico de convites, que pausa a conta (aqui e no crawler)
+		slog.Warn("limite semanal de convites do LinkedIn: convites da conta pausados", "account", acct.Alias, "until", now.Add(7*24*time.Hour).Format(time.RFC3339))
+	}
+
+	status, msg := jobs.StatusDone, "ok"
+	switch {
+	case errors.Is(cause, ErrCancelled):
+		status, msg = jobs.StatusCancelled, ErrCancelled.Error()
+	case cause != nil:
+		status, msg = jobs.StatusCancelled, "interrompido (servidor encerrado)"
+	case sum.Cancelled:
+		status, msg = jobs.StatusCancelled, "cancelado"
+	case res.Err != nil:
+		status, msg = jobs.StatusFailed, res.Err.Error()
+	case sum.InviteLimit:
+		msg = "ok, mas o LinkedIn avisou do limite semanal de convites (convites pausados por 7 dias)"
+	case sum.Degraded:
+		msg = "ok, mas a captura parece degradada (o LinkedIn pode ter mudado a página): " + strings.Join(sum.Issues, "; ")
+	case sum.DryRun:
+		msg = "ok (dry-run: nada foi clicado)"
+	}
+	final, err := s.jobs.Update(j.ID, func(j *jobs.Job) {
+		j.Status = status
+		j.Message = msg
+		j.CSVPath = sum.CSVPath
+		j.QualityPath = sum.QualityPath
+		j.Degraded = sum.Degraded
+		j.QualityIssues = sum.Issues
+		j.Pages = sum.Pages
+		j.Profiles = sum.Profiles
+		j.Invites = sum.Invites
+		j.DryRun = j.DryRun || sum.DryRun // dry_run da configuração também conta
+		j.EndedAt = now
+	})
+	if err != nil {
+		slog.Warn("atualizando job", "job_id", j.ID, "err", err)
+	}
+	if s.done != nil {
+		s.done(final)
+	}
+	s.Wake()
+}
+
+func (s *Scheduler) release(key, id string) {
+	s.mu.Lock()
+	delete(s.busy, key)
+	delete(s.running, id)
+	s.mu.Unlock()
+}
@@ -0,0 +1,422 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/summary"
+)
+
+type call struct {
+	job    jobs.Job
+	acct   accounts.Account
+	budget Budget
+}
+
+// fake é um RunFunc que anota as chamadas; block faz a execução esperar o
+// cancelamento do contexto.
+type fake struct {
+	mu    sync.Mutex
+	calls []call
+	block bool
+	res   Result
+}
+
+func (f *fake) run(ctx context.Context, j jobs.Job, a accounts.Account, b Budget) Result {
+	f.mu.Lock()
+	f.calls = append(f.calls, call{j, a, b})
+	f.mu.Unlock()
+	if f.block {
+		<-ctx.Done()
+		return Result{Summary: summary.Summary{Cancelled: true}}
+	}
+	return f.res
+}
+
+func (f *fake) Calls() []call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]call(nil), f.calls...)
+}
+
+// wait espera o RunFunc ter sido chamado n vezes (ele roda numa goroutine).
+func (f *fake) wait(t *testing.T, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); len(f.Calls()) < n; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d execuções, quero %d", len(f.Calls()), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func newScheduler(t *testing.T, f *fake, accts ...accounts.Account) (*Scheduler, *jobs.Store, *accounts.Registry) {
+	t.Helper()
+	dir := t.TempDir()
+	js, err := jobs.Open(filepath.Join(dir, "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ar, err := accounts.Open(filepath.Join(dir, "accounts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range accts {
+		if err := ar.Put(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return New(js, ar, f.run, nil), js, ar
+}
+
+// history é o histórico de convites visto pelo registro de contas (o que o
+// crawler gravaria).
+type history []ledger.Entry
+
+// useHistory liga o registro a um histórico em memória, com a cota def da
+// configuração.
+func useHistory(ar *accounts.Registry, def ledger.Quota) *history {
+	h := &history{}
+	ar.UseLedger(def, func(string) (*ledger.Checker, error) {
+		return ledger.NewChecker(ledger.Suppression{}, *h), nil
+	})
+	return h
+}
+
+func (h *history) add(alias, status string, at time.Time, n int) {
+	for range n {
+		*h = append(*h, ledger.Entry{At: at, Account: alias, URL: fmt.Sprintf("https://www.linkedin.com/in/p%d", len(*h)), Status: status})
+	}
+}
+
+func enqueue(t *testing.T, js *jobs.Store, j jobs.Job) jobs.Job {
+	t.Helper()
+	if j.Owner == "" {
+		j.Owner = "ana"
+	}
+	j, err := js.Create(j, func(id string) string { return filepath.Join(t.TempDir(), id) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return j
+}
+
+func get(t *testing.T, js *jobs.Store, id string) jobs.Job {
+	t.Helper()
+	j, ok := js.Get(id)
+	if !ok {
+		t.Fatalf("job %s sumiu", id)
+	}
+	return j
+}
+
+func TestPickBudget(t *testing.T) {
+	s, js, ar := newScheduler(t, &fake{},
+		accounts.Account{Owner: "ana", Alias: "rec-1", DailyPages: 10, DailyInvites: 5},
+		accounts.Account{Owner: "ana", Alias: "rec-2", DailyPages: 30, DailyInvites: 7},
+	)
+	now := time.Now()
+	if err := ar.RecordUsage("ana", "rec-2", now, 26); err != nil { // rec-2 fica com 4 páginas
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		maxPages  int
+		alias     string
+		wantAlias string
+		want      Budget
+	}{
+		{0, "", "rec-1", Budget{Pages: 1}},       // MaxPages 0 vira 1 página
+		{3, "", "rec-1", Budget{Pages: 3}},       // rec-1 tem mais páginas restantes
+		{50, "", "rec-1", Budget{Pages: 10}},     // limitado ao que sobra na conta
+		{50, "rec-2", "rec-2", Budget{Pages: 4}}, // conta pedida, mesmo com menos páginas
+	} {
+		j := enqueue(t, js, jobs.Job{MaxPages: tc.maxPages, Account: tc.alias})
+		a, b, why := s.pick(j, now)
+		if why != "" {
+			t.Fatalf("MaxPages %d: %s", tc.maxPages, why)
+		}
+		if a.Alias != tc.wantAlias {
+			t.Errorf("MaxPages %d: conta %s", tc.maxPages, a.Alias)
+		}
+		if b != tc.want {
+			t.Errorf("MaxPages %d, conta %q: orçamento %+v, quero %+v", tc.maxPages, tc.alias, b, tc.want)
+		}
+	}
+
+	if err := ar.RecordUsage("ana", "rec-1", now, 10); err != nil {
+		t.Fatal(err)
+	}
+	if err := ar.RecordUsage("ana", "rec-2", now, 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{MaxPages: 5}), now); why != "orçamento diário de páginas esgotado" {
+		t.Errorf("sem páginas: %q", why)
+	}
+}
+
+func TestPickInviteJob(t *testing.T) {
+	s, js, ar := newScheduler(t, &fake{},
+		accounts.Account{Owner: "ana", Alias: "rec-1", DailyPages: 10, DailyInvites: 5},
+		accounts.Account{Owner: "ana", Alias: "rec-2", DailyPages: 10, DailyInvites: 12},
+	)
+	h := useHistory(ar, ledger.Quota{Daily: 20, Weekly: 100})
+	now := time.Now()
+	// sem páginas hoje: não importa para convites
+	if err := ar.RecordUsage("ana", "rec-2", now, 10); err != nil {
+		t.Fatal(err)
+	}
+	h.add("rec-2", "sent", now, 2)
+	h.add("rec-2", "failed", now, 3) // só os enviados contam
+	invite := jobs.Job{InviteFrom: "job-1", InviteURLs: []string{"https://www.linkedin.com/in/ana"}}
+
+	a, b, why := s.pick(enqueue(t, js, invite), now)
+	if why != "" || a.Alias != "rec-2" || b != (Budget{Invites: 10}) {
+		t.Errorf("pick = %s %+v %q", a.Alias, b, why)
+	}
+
+	h.add("rec-2", ledger.StatusWeeklyLimit, now, 1)
+	if a, b, why := s.pick(enqueue(t, js, invite), now); why != "" || a.Alias != "rec-1" || b != (Budget{Invites: 5}) {
+		t.Errorf("com rec-2 pausada: %s %+v %q", a.Alias, b, why)
+	}
+	invite.Account = "rec-2"
+	if _, _, why := s.pick(enqueue(t, js, invite), now); !strings.HasPrefix(why, "convites da conta pausados até ") {
+		t.Errorf("conta pedida pausada: %q", why)
+	}
+	invite.Account = "rec-1"
+	h.add("rec-1", "sent", now, 5)
+	if _, _, why := s.pick(enqueue(t, js, invite), now); why != "cota de convites da conta esgotada" {
+		t.Errorf("sem convites: %q", why)
+	}
+}
+
+func TestInviteQuotaFromLedger(t *testing.T) {
+	s, js, ar := newScheduler(t, &fake{},
+		accounts.Account{Owner: "ana", Alias: "rec-1"},                   // cotas da configuração
+		accounts.Account{Owner: "ana", Alias: "rec-2", WeeklyInvites: 9}, // semanal própria
+	)
+	h := useHistory(ar, ledger.Quota{Daily: 4, Weekly: 30})
+	now := time.Now()
+	h.add("rec-1", "sent", now.AddDate(0, 0, -2), 3)
+	h.add("rec-2", "sent", now.AddDate(0, 0, -2), 7)
+	h.add("rec-2", "sent", now.AddDate(0, 0, -8), 50) // fora dos 7 dias
+
+	for _, tc := range []struct {
+		alias           string
+		today, week, to int
+	}{
+		{"rec-1", 0, 3, 4}, // diária da configuração
+		{"rec-2", 0, 7, 2}, // semanal da conta
+	} {
+		st, err := ar.Status("ana", tc.alias, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.InvitesToday != tc.today || st.InvitesWeek != tc.week || st.RemainingInvites != tc.to {
+			t.Errorf("%s: hoje %d, semana %d, restam %d", tc.alias, st.InvitesToday, st.InvitesWeek, st.RemainingInvites)
+		}
+	}
+
+	// 0 na configuração também = sem limite, como no crawler
+	useHistory(ar, ledger.Quota{})
+	st, _ := ar.Status("ana", "rec-1", now)
+	if st.RemainingInvites != -1 {
+		t.Errorf("sem cota: restam %d", st.RemainingInvites)
+	}
+	j := enqueue(t, js, jobs.Job{InviteFrom: "job-1", Account: "rec-1"})
+	if _, b, why := s.pick(j, now); why != "" || b != (Budget{Invites: -1}) {
+		t.Errorf("sem cota: %+v %q", b, why)
+	}
+}
+
+func TestPickReasons(t *testing.T) {
+	s, js, ar := newScheduler(t, &fake{}, accounts.Account{Owner: "ana", Alias: "rec-1"})
+	now := time.Now()
+
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{Owner: "bia"}), now); why != "nenhuma conta cadastrada" {
+		t.Errorf("sem contas: %q", why)
+	}
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{Account: "rec-9"}), now); why != "conta rec-9 não existe" {
+		t.Errorf("conta pedida inexistente: %q", why)
+	}
+
+	// conta ocupada por outro job
+	s.busy["ana/rec-1"] = true
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{}), now); why != "aguardando conta livre" {
+		t.Errorf("conta ocupada: %q", why)
+	}
+	delete(s.busy, "ana/rec-1")
+
+	until, err := ar.StartCooldown("ana", "rec-1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{}), now); !strings.HasPrefix(why, "conta em cooldown até ") {
+		t.Errorf("cooldown: %q", why)
+	}
+	if _, _, why := s.pick(enqueue(t, js, jobs.Job{}), until.Add(time.Minute)); why != "" {
+		t.Errorf("depois do cooldown: %q", why)
+	}
+}
+
+func TestDispatchBusyAccount(t *testing.T) {
+	f := &fake{block: true}
+	s, js, _ := newScheduler(t, f, accounts.Account{Owner: "ana", Alias: "rec-1"})
+	first := enqueue(t, js, jobs.Job{MaxPages: 2})
+	second := enqueue(t, js, jobs.Job{MaxPages: 2})
+
+	s.dispatch(context.Background())
+	f.wait(t, 1)
+	if n := len(f.Calls()); n != 1 {
+		t.Fatalf("%d execuções na mesma conta", n)
+	}
+	running, waiting := first, second
+	if get(t, js, second.ID).Status == jobs.StatusRunning {
+		running, waiting = second, first
+	}
+	if j := get(t, js, running.ID); j.Status != jobs.StatusRunning || j.AssignedAccount != "rec-1" {
+		t.Errorf("1º job: %s na conta %q", j.Status, j.AssignedAccount)
+	}
+	if j := get(t, js, waiting.ID); j.Status != jobs.StatusQueued || j.Message != "aguardando conta livre" {
+		t.Errorf("2º job: %s %q", j.Status, j.Message)
+	}
+
+	if !s.Cancel(running.ID) {
+		t.Fatal("Cancel do job rodando devolveu false")
+	}
+	s.wg.Wait()
+	if j := get(t, js, running.ID); j.Status != jobs.StatusCancelled || j.Message != ErrCancelled.Error() {
+		t.Errorf("depois do Cancel: %s %q", j.Status, j.Message)
+	}
+	if s.Cancel(running.ID) {
+		t.Error("Cancel de job terminado devolveu true")
+	}
+
+	// conta liberada: o 2º sai da fila
+	s.dispatch(context.Background())
+	if j := get(t, js, waiting.ID); j.Status != jobs.StatusRunning {
+		t.Errorf("2º job depois de liberar a conta: %s", j.Status)
+	}
+	s.Cancel(waiting.ID)
+	s.wg.Wait()
+}
+
+func TestCancelQueued(t *testing.T) {
+	var done []jobs.Job
+	s, js, _ := newScheduler(t, &fake{})
+	s.done = func(j jobs.Job) { done = append(done, j) }
+	j := enqueue(t, js, jobs.Job{})
+
+	if !s.Cancel(j.ID) {
+		t.Fatal("Cancel devolveu false")
+	}
+	if got := get(t, js, j.ID); got.Status != jobs.StatusCancelled || got.Message != "cancelado antes de iniciar" || got.EndedAt.IsZero() {
+		t.Errorf("job = %s %q", got.Status, got.Message)
+	}
+	if len(done) != 1 || done[0].ID != j.ID {
+		t.Errorf("done = %+v", done)
+	}
+	if s.Cancel(j.ID) || s.Cancel("nao-existe") {
+		t.Error("Cancel de job terminado ou inexistente devolveu true")
+	}
+}
+
+// TestCancelRace cancela enquanto o dispatch tira o job da fila: em qualquer
+// ordem o job termina cancelado e nunca fica rodando.
+func TestCancelRace(t *testing.T) {
+	for range 50 {
+		f := &fake{block: true}
+		s, js, _ := newScheduler(t, f, accounts.Account{Owner: "ana", Alias: "rec-1"})
+		j := enqueue(t, js, jobs.Job{})
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() { defer wg.Done(); s.dispatch(context.Background()) }()
+		go func() {
+			defer wg.Done()
+			for !s.Cancel(j.ID) {
+				time.Sleep(time.Millisecond)
+			}
+		}()
+		wg.Wait()
+		s.wg.Wait()
+
+		got := get(t, js, j.ID)
+		if got.Status != jobs.StatusCancelled {
+			t.Fatalf("job = %s %q (%d execuções)", got.Status, got.Message, len(f.Calls()))
+		}
+		if len(s.busy) != 0 || len(s.running) != 0 {
+			t.Fatalf("conta presa: busy = %v, running = %d", s.busy, len(s.running))
+		}
+	}
+}
+
+func TestFinishInviteLimit(t *testing.T) {
+	f := &fake{res: Result{Summary: summary.Summary{Pages: 2, Profiles: 20, Invites: 3, InviteLimit: true}}}
+	s, js, ar := newScheduler(t, f, accounts.Account{Owner: "ana", Alias: "rec-1", DailyPages: 10, DailyInvites: 20})
+	h := useHistory(ar, ledger.Quota{})
+	j := enqueue(t, js, jobs.Job{MaxPages: 2})
+	// o que o crawler grava no histórico
+	h.add("rec-1", "sent", time.Now(), 3)
+	h.add("rec-1", ledger.StatusWeeklyLimit, time.Now(), 1)
+
+	s.dispatch(context.Background())
+	s.wg.Wait()
+
+	got := get(t, js, j.ID)
+	if got.Status != jobs.StatusDone || !strings.Contains(got.Message, "limite semanal de convites") {
+		t.Errorf("job = %s %q", got.Status, got.Message)
+	}
+	if got.Pages != 2 || got.Profiles != 20 || got.Invites != 3 {
+		t.Errorf("contadores = %d/%d/%d", got.Pages, got.Profiles, got.Invites)
+	}
+
+	now := time.Now()
+	st, err := ar.Status("ana", "rec-1", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.InvitesPaused || st.RemainingInvites != 0 {
+		t.Errorf("convites não pausados: %+v", st)
+	}
+	if d := st.InvitesPausedUntil.Sub(now); d < 7*24*time.Hour-time.Minute || d > 7*24*time.Hour {
+		t.Errorf("pausa de %v", d)
+	}
+	if st.PagesToday != 2 || st.InvitesToday != 3 {
+		t.Errorf("uso = %d páginas, %d convites", st.PagesToday, st.InvitesToday)
+	}
+	// a pausa não tira a conta das coletas
+	if _, b, why := s.pick(enqueue(t, js, jobs.Job{MaxPages: 3}), now); why != "" || b.Pages != 3 {
+		t.Errorf("pick depois da pausa = %+v, %q", b, why)
+	}
+}
+
+func TestFinishCooldown(t *testing.T) {
+	f := &fake{res: Result{Summary: summary.Summary{Pages: 1, Challenges: []string{"captcha"}}}}
+	s, js, ar := newScheduler(t, f, accounts.Account{Owner: "ana", Alias: "rec-1", CooldownHours: 6})
+	enqueue(t, js, jobs.Job{})
+
+	s.dispatch(context.Background())
+	s.wg.Wait()
+
+	st, err := ar.Status("ana", "rec-1", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.CoolingDown || time.Until(st.CooldownUntil) < 5*time.Hour {
+		t.Errorf("cooldown = %v até %v", st.CoolingDown, st.CooldownUntil)
+	}
+	j := enqueue(t, js, jobs.Job{})
+	s.dispatch(context.Background())
+	if got := get(t, js, j.ID); got.Status != jobs.StatusQueued || !strings.HasPrefix(got.Message, "conta em cooldown") {
+		t.Errorf("job na conta em cooldown = %s %q", got.Status, got.Message)
+	}
+}
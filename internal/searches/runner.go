@@ -0,0 +1,67 @@
+package searches
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"CrawlerLinkedin/internal/jobs"
+)
+
+// EnqueueFunc cria o job de uma busca salva e o coloca na fila.
+type EnqueueFunc func(s Search) (jobs.Job, error)
+
+const tickInterval = 30 * time.Second
+
+// Runner dispara as buscas salvas no horário.
+type Runner struct {
+	store   *Store
+	enqueue EnqueueFunc
+}
+
+func NewRunner(st *Store, enqueue EnqueueFunc) *Runner {
+	return &Runner{store: st, enqueue: enqueue}
+}
+
+// Run verifica os horários até ctx terminar. A primeira rodada é imediata,
+// então horários perdidos com o servidor fora do ar são tratados na subida.
+func (r *Runner) Run(ctx context.Context) {
+	t := time.NewTicker(tickInterval)
+	defer t.Stop()
+	for {
+		r.tick(time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (r *Runner) tick(now time.Time) {
+	for _, s := range r.store.due(now) {
+		run := Run{Trigger: TriggerSchedule, ScheduledFor: s.NextRun, At: now}
+		if now.Sub(s.NextRun) > missedGrace {
+			run.Trigger = TriggerMissed
+			if s.Missed != MissedRunOnce {
+				run.Error = "horário perdido (servidor fora do ar)"
+			}
+		}
+		if run.Error == "" {
+			if j, err := r.enqueue(s); err != nil {
+				run.Error = err.Error()
+			} else {
+				run.JobID = j.ID
+			}
+		}
+		if run.Error != "" {
+			slog.Warn("busca salva não rodou", "search", s.Name, "owner", s.Owner, "reason", run.Error)
+		}
+		if err := r.store.AddRun(s.Owner, s.ID, run); err != nil {
+			slog.Warn("registrando execução da busca", "search_id", s.ID, "err", err)
+		}
+		if err := r.store.reschedule(s.Owner, s.ID, now); err != nil {
+			slog.Warn("reagendando busca", "search_id", s.ID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package searches
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"CrawlerLinkedin/internal/jobs"
+)
+
+func newStore(t *testing.T) *Store {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "searches.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
+
+func TestRunnerFiresOnSchedule(t *testing.T) {
+	st := newStore(t)
+	monday := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	s, err := st.Create(Search{Owner: "ana", Name: "devs", Query: "golang", Cron: "0 9 * * 1", Enabled: true}, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := monday.Add(time.Hour); !s.NextRun.Equal(want) {
+		t.Fatalf("next_run = %v, quero %v", s.NextRun, want)
+	}
+
+	var queued []Search
+	r := NewRunner(st, func(s Search) (jobs.Job, error) {
+		queued = append(queued, s)
+		return jobs.Job{ID: "job1"}, nil
+	})
+	r.tick(monday.Add(30 * time.Minute))
+	if len(queued) != 0 {
+		t.Fatal("disparou antes da hora")
+	}
+	r.tick(monday.Add(time.Hour + 20*time.Second))
+	if len(queued) != 1 || queued[0].Job().SearchID != s.ID {
+		t.Fatalf("queued = %+v", queued)
+	}
+
+	got, _ := st.Get("ana", s.ID)
+	if len(got.Runs) != 1 || got.Runs[0].JobID != "job1" || got.Runs[0].Trigger != TriggerSchedule {
+		t.Errorf("runs = %+v", got.Runs)
+	}
+	if want := monday.AddDate(0, 0, 7).Add(time.Hour); !got.NextRun.Equal(want) {
+		t.Errorf("próximo = %v, quero %v", got.NextRun, want)
+	}
+}
+
+func TestRunnerMissedPolicy(t *testing.T) {
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)
+	back := time.Date(2026, 10, 19, 12, 0, 0, 0, time.Local) // várias segundas perdidas
+
+	for _, policy := range []string{MissedSkip, MissedRunOnce} {
+		st := newStore(t)
+		s, err := st.Create(Search{Owner: "ana", Name: "devs", Query: "golang", Cron: "0 9 * * 1", Missed: policy, Enabled: true}, created)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls := 0
+		r := NewRunner(st, func(Search) (jobs.Job, error) {
+			calls++
+			return jobs.Job{ID: "j"}, nil
+		})
+		r.tick(back)
+		r.tick(back.Add(time.Minute))
+
+		got, _ := st.Get("ana", s.ID)
+		wantCalls := map[string]int{MissedSkip: 0, MissedRunOnce: 1}[policy]
+		if calls != wantCalls {
+			t.Errorf("%s: %d jobs, quero %d", policy, calls, wantCalls)
+		}
+		if len(got.Runs) != 1 || got.Runs[0].Trigger != TriggerMissed {
+			t.Errorf("%s: runs = %+v", policy, got.Runs)
+		}
+		if policy == MissedSkip && got.Runs[0].Error == "" {
+			t.Errorf("skip sem motivo registrado")
+		}
+		if want := time.Date(2026, 10, 26, 9, 0, 0, 0, time.Local); !got.NextRun.Equal(want) {
+			t.Errorf("%s: próximo = %v, quero %v", policy, got.NextRun, want)
+		}
+	}
+}
+
+func TestRunnerJitterAndDisable(t *testing.T) {
+	st := newStore(t)
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	s, err := st.Create(Search{Owner: "ana", Name: "devs", Query: "golang", Cron: "0 9 * * *", JitterMinutes: 30, Enabled: true}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := now.Add(time.Hour)
+	if s.NextRun.Before(base) || !s.NextRun.Before(base.Add(30*time.Minute)) {
+		t.Errorf("jitter fora da janela: %v", s.NextRun)
+	}
+
+	s, err = st.Update("ana", s.ID, now, func(s *Search) { s.Enabled = false })
+	if err != nil || !s.NextRun.IsZero() {
+		t.Fatalf("desativar: %+v %v", s, err)
+	}
+	r := NewRunner(st, func(Search) (jobs.Job, error) { return jobs.Job{}, errors.New("não deveria rodar") })
+	r.tick(now.AddDate(0, 0, 3))
+	if got, _ := st.Get("ana", s.ID); len(got.Runs) != 0 {
+		t.Errorf("busca desativada rodou: %+v", got.Runs)
+	}
+
+	// reativar agenda a partir de agora, sem contar os dias desligada como perdidos
+	later := now.AddDate(0, 0, 3)
+	s, _ = st.Update("ana", s.ID, later, func(s *Search) { s.Enabled = true })
+	if s.NextRun.Before(later) {
+		t.Errorf("reativada com horário no passado: %v", s.NextRun)
+	}
+}
@@ -0,0 +1,286 @@
+// Package searches guarda as buscas salvas de cada usuário (query, filtros,
+// páginas, conta e saídas) e as dispara como jobs conforme a expressão cron
+// de cada uma.
+package searches
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/cron"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+var ErrNotFound = errors.New("busca salva não encontrada")
+
+// Política para execuções perdidas (servidor fora do ar no horário).
+const (
+	MissedSkip    = "skip"     // registra como perdida e espera o próximo horário
+	MissedRunOnce = "run_once" // roda uma vez ao voltar, mesmo que várias tenham passado
+)
+
+// Origem de cada execução.
+const (
+	TriggerSchedule = "schedule"
+	TriggerMissed   = "missed"
+	TriggerManual   = "manual"
+)
+
+const (
+	// MaxRuns é quantas execuções ficam no histórico de cada busca.
+	MaxRuns = 10
+	// MaxJitterMinutes limita o atraso aleatório somado a cada horário.
+	MaxJitterMinutes = 120
+
+	// atraso a partir do qual o horário conta como perdido
+	missedGrace = 2 * time.Minute
+)
+
+type Search struct {
+	ID            string       `json:"id"`
+	Owner         string       `json:"owner"`
+	Name          string       `json:"name"`
+	Query         string       `json:"query"`
+	Facets        *jobs.Facets `json:"facets,omitempty"`
+	MaxPages      int          `json:"max_pages"`
+	Account       string       `json:"account,omitempty"`
+	Headless      bool         `json:"headless"`
+	DumpHTML      bool         `json:"dump_html"`
+	DryRun        bool         `json:"dry_run,omitempty"`
+	Cron          string       `json:"cron"`
+	JitterMinutes int          `json:"jitter_minutes"`
+	Missed        string       `json:"missed"`
+	Enabled       bool         `json:"enabled"`
+	NextRun       time.Time    `json:"next_run,omitzero"` // já com o jitter; zero se desativada
+	Runs          []Run        `json:"runs"`              // mais recente primeiro
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// Run é uma execução (ou horário perdido) da busca.
+type Run struct {
+	Trigger      string    `json:"trigger"`
+	ScheduledFor time.Time `json:"scheduled_for,omitzero"`
+	At           time.Time `json:"at"`
+	JobID        string    `json:"job_id,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Job monta o job da busca (o chamador define Dir ao criar).
+func (s Search) Job() jobs.Job {
+	return jobs.Job{
+		Owner:    s.Owner,
+		Query:    s.Query,
+		MaxPages: s.MaxPages,
+		Headless: s.Headless,
+		DumpHTML: s.DumpHTML,
+		DryRun:   s.DryRun,
+		Account:  s.Account,
+		Facets:   s.Facets,
+		SearchID: s.ID,
+	}
+}
+
+// next calcula o próximo disparo depois de after, com jitter.
+func (s Search) next(after time.Time) (time.Time, error) {
+	if !s.Enabled {
+		return time.Time{}, nil
+	}
+	sched, err := cron.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t := sched.Next(after)
+	if t.IsZero() || s.JitterMinutes <= 0 {
+		return t, nil
+	}
+	return t.Add(rand.N(time.Duration(s.JitterMinutes) * time.Minute)), nil
+}
+
+// =============== Store ===============
+
+type Store struct {
+	mu       sync.Mutex
+	path     string
+	searches []*Search
+}
+
+type fileData struct {
+	Searches []*Search `json:"searches"`
+}
+
+func Open(path string) (*Store, error) {
+	var fd fileData
+	if err := jsonfile.Load(path, &fd); err != nil {
+		return nil, err
+	}
+	return &Store{path: path, searches: fd.Searches}, nil
+}
+
+// List devolve as buscas de owner em ordem de nome.
+func (st *Store) List(owner string) []Search {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	out := []Search{}
+	for _, s := range st.searches {
+		if s.Owner == owner {
+			out = append(out, *s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name) })
+	return out
+}
+
+func (st *Store) Get(owner, id string) (Search, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s := st.findLocked(owner, id)
+	if s == nil {
+		return Search{}, ErrNotFound
+	}
+	return *s, nil
+}
+
+// Create grava uma busca nova e agenda o primeiro disparo.
+func (st *Store) Create(s Search, now time.Time) (Search, error) {
+	if s.Missed == "" {
+		s.Missed = MissedSkip
+	}
+	next, err := s.next(now)
+	if err != nil {
+		return Search{}, err
+	}
+	s.ID = newID()
+	s.NextRun = next
+	s.Runs = []Run{}
+	s.CreatedAt, s.UpdatedAt = now, now
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.searches = append(st.searches, &s)
+	if err := st.saveLocked(); err != nil {
+		st.searches = st.searches[:len(st.searches)-1]
+		return Search{}, err
+	}
+	return s, nil
+}
+
+// Update aplica fn e reagenda se a expressão, o jitter ou o estado mudaram.
+// Reativar não dispara horários que passaram enquanto estava desligada.
+func (st *Store) Update(owner, id string, now time.Time, fn func(s *Search)) (Search, error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	cur := st.findLocked(owner, id)
+	if cur == nil {
+		return Search{}, ErrNotFound
+	}
+	s := *cur
+	fn(&s)
+	s.ID, s.Owner, s.Runs, s.CreatedAt = cur.ID, cur.Owner, cur.Runs, cur.CreatedAt
+	if s.Cron != cur.Cron || s.JitterMinutes != cur.JitterMinutes || s.Enabled != cur.Enabled {
+		next, err := s.next(now)
+		if err != nil {
+			return Search{}, err
+		}
+		s.NextRun = next
+	}
+	s.UpdatedAt = now
+
+	prev := *cur
+	*cur = s
+	if err := st.saveLocked(); err != nil {
+		*cur = prev
+		return Search{}, err
+	}
+	return s, nil
+}
+
+func (st *Store) Delete(owner, id string) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	for i, s := range st.searches {
+		if s.Owner == owner && s.ID == id {
+			st.searches = append(st.searches[:i], st.searches[i+1:]...)
+			return st.saveLocked()
+		}
+	}
+	return ErrNotFound
+}
+
+// AddRun registra uma execução no histórico (as mais antigas saem).
+func (st *Store) AddRun(owner, id string, r Run) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s := st.findLocked(owner, id)
+	if s == nil {
+		return ErrNotFound
+	}
+	s.Runs = append([]Run{r}, s.Runs...)
+	if len(s.Runs) > MaxRuns {
+		s.Runs = s.Runs[:MaxRuns]
+	}
+	return st.saveLocked()
+}
+
+// due devolve as buscas ativas cujo horário chegou.
+func (st *Store) due(now time.Time) []Search {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	var out []Search
+	for _, s := range st.searches {
+		if s.Enabled && !s.NextRun.IsZero() && !s.NextRun.After(now) {
+			out = append(out, *s)
+		}
+	}
+	return out
+}
+
+// reschedule agenda o próximo disparo depois de now; horários que ficaram
+// para trás (servidor fora do ar) não são repetidos.
+func (st *Store) reschedule(owner, id string, now time.Time) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	s := st.findLocked(owner, id)
+	if s == nil {
+		return ErrNotFound
+	}
+	next, err := s.next(now)
+	if err != nil {
+		s.Enabled, next = false, time.Time{}
+	}
+	s.NextRun = next
+	return st.saveLocked()
+}
+
+func (st *Store) findLocked(owner, id string) *Search {
+	for _, s := range st.searches {
+		if s.Owner == owner && s.ID == id {
+			return s
+		}
+	}
+	return nil
+}
+
+func (st *Store) saveLocked() error {
+	return jsonfile.Save(st.path, fileData{Searches: st.searches})
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = crand.Read(b)
+	return hex.EncodeToString(b)
+}
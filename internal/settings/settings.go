@@ -0,0 +1,64 @@
+// Package settings guarda as preferências de cada usuário da UI (valores
+// padrão para novos jobs).
+package settings
+
+import (
+	"sync"
+
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+type Settings struct {
+	MaxPages int    `json:"max_pages"`
+	Headless bool   `json:"headless"`
+	Account  string `json:"account"` // vazio = automático
+}
+
+// Defaults são usados enquanto o usuário não salvar nada.
+var Defaults = Settings{MaxPages: 1, Headless: true}
+
+type Store struct {
+	mu    sync.Mutex
+	path  string
+	users map[string]Settings
+}
+
+type fileData struct {
+	Users map[string]Settings `json:"users"`
+}
+
+func Open(path string) (*Store, error) {
+	var fd fileData
+	if err := jsonfile.Load(path, &fd); err != nil {
+		return nil, err
+	}
+	if fd.Users == nil {
+		fd.Users = map[string]Settings{}
+	}
+	return &Store{path: path, users: fd.Users}, nil
+}
+
+func (s *Store) Get(owner string) Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.users[owner]; ok {
+		return st
+	}
+	return Defaults
+}
+
+func (s *Store) Put(owner string, st Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev, had := s.users[owner]
+	s.users[owner] = st
+	if err := jsonfile.Save(s.path, fileData{Users: s.users}); err != nil {
+		if had {
+			s.users[owner] = prev
+		} else {
+			delete(s.users, owner)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package summary é o resumo que o crawler grava ao fim de cada execução
+// (summary.json na pasta de saída) e que o servidor lê para contabilizar uso.
+package summary
+
+import (
+	"path/filepath"
+	"time"
+
+	"CrawlerLinkedin/internal/jsonfile"
+)
+
+const FileName = "summary.json"
+
+type Summary struct {
+	Query       string    `json:"query"`
+	Pages       int       `json:"pages"`
+	Profiles    int       `json:"profiles"`
+	Invites     int       `json:"invites"`
+	Challenges  []string  `json:"challenges,omitempty"` // "captcha", "checkpoint", "2fa"
+	CSVPath     string    `json:"csv_path,omitempty"`
+	QualityPath string    `json:"quality_path,omitempty"` // relatório de qualidade (internal/quality)
+	Error       string    `json:"error,omitempty"`
+	Cancelled   bool      `json:"cancelled,omitempty"`    // parou por sinal, com resultados parciais
+	DryRun      bool      `json:"dry_run,omitempty"`      // nenhum clique que muda algo foi feito
+	InviteLimit bool      `json:"invite_limit,omitempty"` // o LinkedIn avisou do limite semanal de convites
+	Degraded    bool      `json:"degraded,omitempty"`     // a captura passou de algum limite de qualidade
+	Issues      []string  `json:"quality_issues,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+}
+
+func Write(dir string, s Summary) error {
+	return jsonfile.Save(filepath.Join(dir, FileName), s)
+}
+
+// Read lê o resumo de dir; sem arquivo devolve um Summary vazio.
+func Read(dir string) (Summary, error) {
+	var s Summary
+	err := jsonfile.Load(filepath.Join(dir, FileName), &s)
+	return s, err
+}
+
+// NeedsCooldown diz se o LinkedIn desconfiou da conta (captcha ou checkpoint).
+// 2FA faz parte do login normal de contas protegidas e não conta.
+func (s Summary) NeedsCooldown() bool {
+	for _, c := range s.Challenges {
+		if c != "2fa" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+// Package totp gera códigos TOTP (RFC 6238) como os de apps autenticadores:
+// HMAC-SHA1, passo de 30 segundos, 6 dígitos.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	Period = 30 * time.Second
+	Digits = 6
+)
+
+// DecodeSecret aceita o segredo em base32 do jeito que os sites mostram:
+// minúsculas, espaços/hífens e sem padding.
+func DecodeSecret(secret string) ([]byte, error) {
+	s := strings.ToUpper(strings.NewReplacer(" ", "", "-", "", "=", "").Replace(secret))
+	if s == "" {
+		return nil, errors.New("segredo TOTP vazio")
+	}
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("segredo TOTP não é base32 válido: %w", err)
+	}
+	return key, nil
+}
+
+// Code devolve o código do passo que contém t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := DecodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return HOTP(key, Counter(t), Digits), nil
+}
+
+// Counter é o número do passo de tempo de t (T na RFC 6238).
+func Counter(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(Period/time.Second)
+}
+
+// NextStep devolve o início do passo seguinte ao de t.
+func NextStep(t time.Time) time.Time {
+	return time.Unix(int64(Counter(t)+1)*int64(Period/time.Second), 0)
+}
+
+// HOTP é o algoritmo da RFC 4226 com truncamento dinâmico.
+func HOTP(key []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	off := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+	mod := uint32(1)
+	for range digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, bin%mod)
+}
@@ -0,0 +1,78 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// Vetores do apêndice B da RFC 6238 (SHA-1, 8 dígitos).
+func TestHOTPRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, c := range cases {
+		got := HOTP(key, Counter(time.Unix(c.unix, 0)), 8)
+		if got != c.want {
+			t.Errorf("T=%d: got %s, want %s", c.unix, got, c.want)
+		}
+	}
+}
+
+// Vetores do apêndice D da RFC 4226 (6 dígitos).
+func TestHOTPRFC4226(t *testing.T) {
+	key := []byte("12345678901234567890")
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for i, w := range want {
+		if got := HOTP(key, uint64(i), 6); got != w {
+			t.Errorf("counter %d: got %s, want %s", i, got, w)
+		}
+	}
+}
+
+func TestCodeAcceptsFormattedSecret(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	ts := time.Unix(59, 0)
+	want := "287082"
+
+	for _, s := range []string{
+		secret,
+		"gezd gnbv gy3t qojq gezd gnbv gy3t qojq",
+		"GEZD-GNBV-GY3T-QOJQ-GEZD-GNBV-GY3T-QOJQ",
+	} {
+		got, err := Code(s, ts)
+		if err != nil {
+			t.Fatalf("Code(%q): %v", s, err)
+		}
+		if got != want {
+			t.Errorf("Code(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	for _, s := range []string{"", "   ", "not base32!"} {
+		if _, err := Code(s, time.Now()); err == nil {
+			t.Errorf("Code(%q): esperava erro", s)
+		}
+	}
+}
+
+func TestNextStep(t *testing.T) {
+	got := NextStep(time.Unix(59, 0))
+	if got.Unix() != 60 {
+		t.Errorf("NextStep(59) = %d, want 60", got.Unix())
+	}
+	if Counter(got) != Counter(time.Unix(59, 0))+1 {
+		t.Error("NextStep deveria cair no passo seguinte")
+	}
+}
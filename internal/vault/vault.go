@@ -0,0 +1,232 @@
+// Package vault guarda credenciais do LinkedIn cifradas (AES-256-GCM) com uma
+// chave mestra que nunca vai para o disco junto com os dados.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"CrawlerLinkedin/internal/jsonfile"
+	"CrawlerLinkedin/internal/totp"
+)
+
+var ErrNotFound = errors.New("credencial não encontrada")
+
+// Credential é o segredo guardado e também o formato JSON que o crawler
+// aceita via stdin/arquivo.
+type Credential struct {
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	TOTPSecret string `json:"totp_secret,omitempty"` // base32 do app autenticador (opcional)
+}
+
+// Info é o que pode ser mostrado na UI (sem senha).
+type Info struct {
+	Alias     string    `json:"alias"`
+	Email     string    `json:"email"`
+	HasTOTP   bool      `json:"has_totp"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type entry struct {
+	Alias     string    `json:"alias"`
+	Owner     string    `json:"owner"`
+	Nonce     []byte    `json:"nonce"`
+	Data      []byte    `json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type fileData struct {
+	Entries []entry `json:"entries"`
+}
+
+type Vault struct {
+	mu      sync.Mutex
+	path    string
+	aead    cipher.AEAD
+	entries []entry
+}
+
+// LoadKey decodifica a chave mestra (32 bytes em base64): raw, ou o conteúdo
+// de file quando raw está vazio. Os dois vêm da configuração (vault.master_key
+// / VAULT_MASTER_KEY e vault.master_key_file / VAULT_MASTER_KEY_FILE).
+func LoadKey(raw, file string) ([]byte, error) {
+	if raw == "" && file != "" {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("lendo vault.master_key_file: %w", err)
+		}
+		raw = string(b)
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("chave mestra ausente: defina VAULT_MASTER_KEY ou VAULT_MASTER_KEY_FILE (gere com: head -c 32 /dev/urandom | base64)")
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("chave mestra não é base64 válido: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("chave mestra deve ter 32 bytes, tem %d", len(key))
+	}
+	return key, nil
+}
+
+// Open carrega o cofre em path usando key (32 bytes).
+func Open(path string, key []byte) (*Vault, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	var fd fileData
+	if err := jsonfile.Load(path, &fd); err != nil {
+		return nil, err
+	}
+	v := &Vault{path: path, aead: aead, entries: fd.Entries}
+	// confere a chave já na abertura para não descobrir o erro só no primeiro run
+	if len(v.entries) > 0 {
+		if _, err := v.open(v.entries[0]); err != nil {
+			return nil, fmt.Errorf("cofre %s: %w", path, err)
+		}
+	}
+	return v, nil
+}
+
+// Put cria ou substitui a credencial alias de owner.
+func (v *Vault) Put(owner, alias string, c Credential) error {
+	alias = strings.TrimSpace(alias)
+	if alias == "" {
+		return errors.New("alias vazio")
+	}
+	if c.Email == "" || c.Password == "" {
+		return errors.New("email e senha são obrigatórios")
+	}
+	if c.TOTPSecret != "" {
+		if _, err := totp.DecodeSecret(c.TOTPSecret); err != nil {
+			return err
+		}
+	}
+	plain, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	e := entry{
+		Alias:     alias,
+		Owner:     owner,
+		Nonce:     nonce,
+		Data:      v.aead.Seal(nil, nonce, plain, additionalData(owner, alias)),
+		CreatedAt: time.Now(),
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	prev := slices.Clone(v.entries)
+	if i := v.indexLocked(owner, alias); i >= 0 {
+		v.entries[i] = e
+	} else {
+		v.entries = append(v.entries, e)
+	}
+	if err := v.saveLocked(); err != nil {
+		v.entries = prev
+		return err
+	}
+	return nil
+}
+
+func (v *Vault) Get(owner, alias string) (Credential, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	i := v.indexLocked(owner, alias)
+	if i < 0 {
+		return Credential{}, ErrNotFound
+	}
+	return v.open(v.entries[i])
+}
+
+func (v *Vault) Delete(owner, alias string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	i := v.indexLocked(owner, alias)
+	if i < 0 {
+		return ErrNotFound
+	}
+	prev := slices.Clone(v.entries)
+	v.entries = append(v.entries[:i], v.entries[i+1:]...)
+	if err := v.saveLocked(); err != nil {
+		v.entries = prev
+		return err
+	}
+	return nil
+}
+
+// List devolve as credenciais de owner sem os segredos.
+func (v *Vault) List(owner string) []Info {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := []Info{}
+	for _, e := range v.entries {
+		if e.Owner != owner {
+			continue
+		}
+		c, err := v.open(e)
+		if err != nil {
+			continue
+		}
+		out = append(out, Info{Alias: e.Alias, Email: c.Email, HasTOTP: c.TOTPSecret != "", CreatedAt: e.CreatedAt})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Alias < out[b].Alias })
+	return out
+}
+
+func (v *Vault) open(e entry) (Credential, error) {
+	plain, err := v.aead.Open(nil, e.Nonce, e.Data, additionalData(e.Owner, e.Alias))
+	if err != nil {
+		return Credential{}, errors.New("não foi possível decifrar (chave mestra errada?)")
+	}
+	var c Credential
+	if err := json.Unmarshal(plain, &c); err != nil {
+		return Credential{}, err
+	}
+	return c, nil
+}
+
+func (v *Vault) indexLocked(owner, alias string) int {
+	for i, e := range v.entries {
+		if e.Owner == owner && e.Alias == alias {
+			return i
+		}
+	}
+	return -1
+}
+
+func (v *Vault) saveLocked() error {
+	return jsonfile.Save(v.path, fileData{Entries: v.entries})
+}
+
+// additionalData amarra o texto cifrado ao dono e ao alias: copiar a entrada
+// de outro usuário no arquivo não a torna legível.
+func additionalData(owner, alias string) []byte {
+	return []byte(owner + "\x00" + alias)
+}
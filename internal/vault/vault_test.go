@@ -0,0 +1,246 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	key := newKey(t)
+	v, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ana := Credential{Email: "ana@example.com", Password: "s3nh@-secreta", TOTPSecret: "JBSWY3DPEHPK3PXP"}
+	if err := v.Put("ana", "rec-1", ana); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Put("bia", "rec-1", Credential{Email: "bia@example.com", Password: "outra"}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("s3nh@-secreta")) || bytes.Contains(raw, []byte("ana@example.com")) {
+		t.Fatal("segredo em claro no arquivo do cofre")
+	}
+
+	// reabre do disco com a mesma chave
+	v, err = Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := v.Get("ana", "rec-1"); err != nil || got != ana {
+		t.Errorf("Get = %+v, %v", got, err)
+	}
+	if _, err := v.Get("ana", "rec-2"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("alias inexistente: %v", err)
+	}
+	if list := v.List("ana"); len(list) != 1 || list[0].Email != ana.Email || !list[0].HasTOTP {
+		t.Errorf("List = %+v", list)
+	}
+
+	// Put no mesmo alias substitui
+	if err := v.Put("ana", "rec-1", Credential{Email: "ana@example.com", Password: "nova"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := v.Get("ana", "rec-1"); got.Password != "nova" || len(v.List("ana")) != 1 {
+		t.Errorf("substituição: %+v", got)
+	}
+	if err := v.Delete("ana", "rec-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Get("ana", "rec-1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("depois do Delete: %v", err)
+	}
+	if _, err := v.Get("bia", "rec-1"); err != nil {
+		t.Errorf("Delete levou a credencial de outro dono: %v", err)
+	}
+}
+
+func TestPutValidation(t *testing.T) {
+	v, err := Open(filepath.Join(t.TempDir(), "vault.json"), newKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, c := range map[string]Credential{
+		"sem senha": {Email: "ana@example.com"},
+		"sem email": {Password: "x"},
+		"TOTP ruim": {Email: "ana@example.com", Password: "x", TOTPSecret: "não é base32!"},
+	} {
+		if err := v.Put("ana", "rec-1", c); err == nil {
+			t.Errorf("%s: aceito", name)
+		}
+	}
+	if err := v.Put("ana", "  ", Credential{Email: "a", Password: "b"}); err == nil {
+		t.Error("alias vazio aceito")
+	}
+}
+
+// TestSaveFailure: se o arquivo não pode ser gravado, Put e Delete falham
+// sem mudar o que o cofre devolve.
+func TestSaveFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	v, err := Open(path, newKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ana := Credential{Email: "ana@example.com", Password: "s3nh@"}
+	if err := v.Put("ana", "rec-1", ana); err != nil {
+		t.Fatal(err)
+	}
+	// uma pasta no lugar do arquivo: o rename do save falha
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(path, "x"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Put("ana", "rec-1", Credential{Email: "ana@example.com", Password: "nova"}); err == nil {
+		t.Fatal("Put sem conseguir gravar não falhou")
+	}
+	if err := v.Put("ana", "rec-2", ana); err == nil {
+		t.Fatal("Put sem conseguir gravar não falhou")
+	}
+	if err := v.Delete("ana", "rec-1"); err == nil {
+		t.Fatal("Delete sem conseguir gravar não falhou")
+	}
+	if got, err := v.Get("ana", "rec-1"); err != nil || got != ana {
+		t.Errorf("Get = %+v, %v", got, err)
+	}
+	if list := v.List("ana"); len(list) != 1 {
+		t.Errorf("List = %+v", list)
+	}
+}
+
+func TestWrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	v, err := Open(path, newKey(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Put("ana", "rec-1", Credential{Email: "ana@example.com", Password: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, newKey(t)); err == nil {
+		t.Fatal("cofre abriu com outra chave")
+	}
+	if _, err := Open(path, []byte("curta")); err == nil {
+		t.Fatal("chave de tamanho errado aceita")
+	}
+}
+
+// TestTampering copia entradas entre donos e aliases direto no arquivo: o
+// dado adicional (dono + alias) impede que a cópia seja decifrada.
+func TestTampering(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	key := newKey(t)
+	v, err := Open(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Put("ana", "rec-1", Credential{Email: "ana@example.com", Password: "da-ana"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Put("bia", "rec-2", Credential{Email: "bia@example.com", Password: "da-bia"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tamper := func(fn func(es []entry)) *Vault {
+		t.Helper()
+		var fd fileData
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(b, &fd); err != nil {
+			t.Fatal(err)
+		}
+		fn(fd.Entries)
+		b, _ = json.Marshal(fd)
+		p := filepath.Join(t.TempDir(), "vault.json")
+		if err := os.WriteFile(p, b, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		// a 1ª entrada pode ser a adulterada: Open falha, o que também serve
+		tv, err := Open(p, key)
+		if err != nil {
+			return nil
+		}
+		return tv
+	}
+
+	// a entrada da Ana passa a dizer que é da Bia
+	if tv := tamper(func(es []entry) { es[0].Owner = "bia" }); tv != nil {
+		if c, err := tv.Get("bia", "rec-1"); err == nil {
+			t.Errorf("dono trocado decifrou: %+v", c)
+		}
+	}
+	// mesmo dono, alias trocado
+	if tv := tamper(func(es []entry) { es[0].Alias = "rec-9" }); tv != nil {
+		if c, err := tv.Get("ana", "rec-9"); err == nil {
+			t.Errorf("alias trocado decifrou: %+v", c)
+		}
+	}
+	// o texto cifrado da Ana colado na entrada da Bia
+	tv := tamper(func(es []entry) { es[1].Nonce, es[1].Data = es[0].Nonce, es[0].Data })
+	if tv == nil {
+		t.Fatal("a 1ª entrada não foi mexida; Open devia passar")
+	}
+	if c, err := tv.Get("bia", "rec-2"); err == nil {
+		t.Errorf("dados trocados decifraram: %+v", c)
+	}
+	if list := tv.List("bia"); len(list) != 0 {
+		t.Errorf("List mostrou entrada adulterada: %+v", list)
+	}
+	// bit trocado no texto cifrado
+	if tv := tamper(func(es []entry) { es[1].Data[0] ^= 1 }); tv != nil {
+		if _, err := tv.Get("bia", "rec-2"); err == nil {
+			t.Error("texto cifrado alterado decifrou")
+		}
+	}
+}
+
+func TestLoadKey(t *testing.T) {
+	key := newKey(t)
+	raw := base64.StdEncoding.EncodeToString(key)
+	if got, err := LoadKey(raw, ""); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("LoadKey = %x, %v", got, err)
+	}
+	if _, err := LoadKey(base64.StdEncoding.EncodeToString(key[:16]), ""); err == nil {
+		t.Error("chave de 16 bytes aceita")
+	}
+	if _, err := LoadKey("", ""); err == nil {
+		t.Error("chave ausente aceita")
+	}
+	file := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(file, []byte(raw+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := LoadKey("", file); err != nil || !bytes.Equal(got, key) {
+		t.Errorf("LoadKey (arquivo) = %x, %v", got, err)
+	}
+	// a chave direta vence o arquivo
+	if got, err := LoadKey(base64.StdEncoding.EncodeToString(newKey(t)), file); err != nil || bytes.Equal(got, key) {
+		t.Errorf("LoadKey (as duas) = %x, %v", got, err)
+	}
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/crawler"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/notes"
+)
+
+// invitesCmd agrupa os subcomandos de convites: invites <ação> [flags].
+func invitesCmd(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		fmt.Fprintln(os.Stderr, "uso: golinkedin invites <ação> [flags]\n\nações:\n  send      Convida os perfis de uma lista revisada, um a um\n  preview   Mostra a nota de cada perfil da lista, sem abrir o navegador\n  export    Exporta o histórico de convites para CSV\n  quota     Mostra o consumo das cotas diária e semanal de cada conta\n  sync      Confere no LinkedIn quem aceitou, segue pendente ou teve o convite retirado")
+		if len(args) == 0 {
+			return errors.New("informe a ação")
+		}
+		return nil
+	}
+	switch args[0] {
+	case "send":
+		return invitesSendCmd(args[1:])
+	case "preview":
+		return invitesPreviewCmd(args[1:])
+	case "export":
+		return invitesExportCmd(args[1:])
+	case "quota":
+		return invitesQuotaCmd(args[1:])
+	case "sync":
+		return invitesSyncCmd(args[1:])
+	}
+	return fmt.Errorf("invites: ação desconhecida %q", args[0])
+}
+
+func invitesSendCmd(args []string) error {
+	fs := newFlags("invites send", "--list perfis.csv (--credentials-stdin | --credentials-file F | LINKEDIN_EMAIL/LINKEDIN_PASSWORD) [flags]",
+		"Abre cada perfil da lista (CSV do crawler já revisado, ou \"URL Nome\" por linha), confere o nome e envia o convite.\n"+
+			"Grava invites_<data>.csv com o desfecho de cada perfil e o summary.json na pasta de saída.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	s := addSessionFlags(fs, &cfg)
+	list := fs.String("list", "", "Lista revisada: CSV com colunas url e name, ou texto com \"URL Nome\" por linha (sem nome o perfil não é convidado)")
+	noteFile := addInviteFlags(fs, &cfg)
+	fs.IntVar(&cfg.Defaults.MaxInvites, "max", cfg.Defaults.MaxInvites, "Máximo de convites enviados nesta execução")
+	fs.Parse(args)
+	if *list == "" {
+		fs.Usage()
+		return errors.New("falta --list")
+	}
+	targets, err := crawler.ReadTargets(*list)
+	if err != nil {
+		return err
+	}
+	// a supressão é consultada de novo perfil a perfil; aqui só evita um login à toa
+	check, err := ledger.Load(cfg.Invites.Ledger, cfg.Invites.Suppress)
+	if err != nil {
+		return err
+	}
+	skip, unnamed := 0, 0
+	for _, t := range targets {
+		if _, ok := check.Suppressed(t.URL, t.Company); ok {
+			skip++
+		} else if t.Name == "" {
+			unnamed++
+		}
+	}
+	if skip == len(targets) {
+		return fmt.Errorf("todos os %d perfis da lista estão na lista de não contatar ou já foram convidados", skip)
+	}
+	if skip+unnamed == len(targets) {
+		return fmt.Errorf("nenhum perfil da lista tem nome para conferir antes do convite (%d sem nome): use o CSV do crawler ou \"URL Nome\" por linha", unnamed)
+	}
+	note, err := loadNote(*noteFile)
+	if err != nil {
+		return err
+	}
+	if note != nil {
+		// valida todas antes do login
+		if bad := badNotes(note, targets); len(bad) > 0 {
+			return fmt.Errorf("%d nota(s) não fecham (veja \"invites preview\"):\n  %s", len(bad), strings.Join(bad, "\n  "))
+		}
+	}
+	opts, err := s.options(fs, cfg)
+	if err != nil {
+		return err
+	}
+	opts.Note = note
+	// cota esgotada: recusa antes do login
+	account := cmp.Or(opts.Account, opts.Credentials.Email)
+	if _, err := quotaOf(cfg).Remaining(check.Usage(account, time.Now())); err != nil {
+		return fmt.Errorf("conta %s: %w", account, err)
+	}
+	res, err := crawler.Invite(cfg, opts, targets)
+	counts := map[string]int{}
+	for _, r := range res {
+		counts[r.Status]++
+	}
+	if cfg.DryRun {
+		fmt.Fprintf(os.Stderr, "dry-run: %d convites seriam enviados (nada foi clicado; screenshots em %s)\n",
+			counts[crawler.InviteDryRun], filepath.Join(cfg.Output.Dir, "dryrun"))
+	}
+	if errors.Is(err, crawler.ErrWeeklyLimit) {
+		fmt.Fprintln(os.Stderr, "o LinkedIn avisou do limite semanal: a conta fica sem convites por 7 dias (veja \"invites quota\")")
+	}
+	fmt.Fprintf(os.Stderr, "%d perfis na lista, %d processados: %d enviados, %d pulados (não contatar/já convidados), %d sem nome na lista, %d já pendentes, %d já conexões, %d nome diferente, %d sem botão, %d nota inválida, %d falhas\n",
+		len(targets), len(res), counts[crawler.InviteSent], counts[crawler.InviteSuppressed], counts[crawler.InviteUnconfirmed], counts[crawler.InvitePending], counts[crawler.InviteConnected],
+		counts[crawler.InviteNameMismatch], counts[crawler.InviteNoButton], counts[crawler.InviteNoteError], counts[crawler.InviteFailed])
+	return err
+}
+
+func invitesPreviewCmd(args []string) error {
+	fs := newFlags("invites preview", "--note-template nota.tmpl [--list perfis.csv]",
+		"Monta a nota de cada perfil da lista (ou do perfil de exemplo) e confere o limite de "+fmt.Sprint(notes.MaxLen)+" caracteres.")
+	list := fs.String("list", "", "Lista revisada (como em invites send); vazio = perfil de exemplo")
+	noteFile := fs.String("note-template", "", "Modelo text/template da nota")
+	fs.Parse(args)
+	if *noteFile == "" {
+		fs.Usage()
+		return errors.New("falta --note-template")
+	}
+	note, err := loadNote(*noteFile)
+	if err != nil {
+		return err
+	}
+	fields := []notes.Fields{notes.Sample}
+	if *list != "" {
+		targets, err := crawler.ReadTargets(*list)
+		if err != nil {
+			return err
+		}
+		fields = fields[:0]
+		for _, t := range targets {
+			f := t.NoteFields("")
+			if f.Name == "" {
+				f.Name = t.URL // o nome só aparece na página
+			}
+			fields = append(fields, f)
+		}
+	}
+	failed := 0
+	for _, p := range note.PreviewAll(fields) {
+		status := "ok"
+		if p.Error != "" {
+			status, failed = p.Error, failed+1
+		}
+		fmt.Printf("— %s (%d/%d, %s)\n%s\n\n", p.Name, p.Length, notes.MaxLen, status, p.Note)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d de %d notas não fecham", failed, len(fields))
+	}
+	return nil
+}
+
+func invitesExportCmd(args []string) error {
+	fs := newFlags("invites export", "[--ledger invites.jsonl] [-o convites.csv]", "Exporta o histórico de convites (quem, por qual conta, quando, nota e resultado) para CSV.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.StringVar(&cfg.Invites.Ledger, "ledger", cfg.Invites.Ledger, "Histórico de convites (JSONL)")
+	out := fs.String("o", "-", "Arquivo de saída (- = stdout)")
+	account := fs.String("account", "", "Só os convites desta conta")
+	status := fs.String("status", "", "Só os convites com este resultado (sent, pending, connected, failed...)")
+	fs.Parse(args)
+	entries, err := ledger.Read(cfg.Invites.Ledger)
+	if err != nil {
+		return err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if (*account == "" || e.Account == *account) && (*status == "" || e.Status == *status) {
+			kept = append(kept, e)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d convites no histórico, %d exportados\n", len(entries), len(kept))
+	return writeOutput(*out, func(w io.Writer) error { return ledger.WriteCSV(w, kept) })
+}
+
+func invitesSyncCmd(args []string) error {
+	fs := newFlags("invites sync", "(--credentials-stdin | --credentials-file F | LINKEDIN_EMAIL/LINKEDIN_PASSWORD) [flags]",
+		"Confere os convites em aberto da conta no histórico: lê \"Convites enviados\" e as conexões (e, na dúvida, o perfil)\n"+
+			"e grava no histórico quem aceitou (accepted), segue pendente (pending) ou teve o convite retirado (withdrawn).\n"+
+			"Com --withdraw-after-days, retira os pendentes enviados há mais tempo que isso.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	s := addSessionFlags(fs, &cfg)
+	fs.StringVar(&cfg.Invites.Ledger, "ledger", cfg.Invites.Ledger, "Histórico de convites (JSONL)")
+	fs.IntVar(&cfg.Invites.WithdrawAfterDays, "withdraw-after-days", cfg.Invites.WithdrawAfterDays, "Retirar convites pendentes enviados há mais que estes dias (0 = não retira)")
+	maxWithdraw := fs.Int("max-withdraw", 10, "Máximo de convites retirados nesta execução")
+	fs.Parse(args)
+	opts, err := s.options(fs, cfg)
+	if err != nil {
+		return err
+	}
+	changes, err := crawler.Sync(cfg, opts, *maxWithdraw)
+	counts := map[string]int{}
+	for _, e := range changes {
+		counts[e.Status]++
+	}
+	fmt.Fprintf(os.Stderr, "%d mudanças: %d aceitos, %d pendentes, %d retirados ou expirados\n",
+		len(changes), counts[ledger.StatusAccepted], counts["pending"], counts[ledger.StatusWithdrawn])
+	return err
+}
+
+func invitesQuotaCmd(args []string) error {
+	fs := newFlags("invites quota", "[--ledger invites.jsonl] [--account conta]",
+		"Mostra, por conta do histórico, os convites enviados hoje e nos últimos 7 dias contra as cotas, e o que resta.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.StringVar(&cfg.Invites.Ledger, "ledger", cfg.Invites.Ledger, "Histórico de convites (JSONL)")
+	fs.IntVar(&cfg.Invites.DailyQuota, "daily-quota", cfg.Invites.DailyQuota, "Cota diária (0 = sem cota)")
+	fs.IntVar(&cfg.Invites.WeeklyQuota, "weekly-quota", cfg.Invites.WeeklyQuota, "Cota semanal (0 = sem cota)")
+	account := fs.String("account", "", "Só esta conta")
+	fs.Parse(args)
+	entries, err := ledger.Read(cfg.Invites.Ledger)
+	if err != nil {
+		return err
+	}
+	check := ledger.NewChecker(ledger.Suppression{}, entries)
+	var list []string
+	seen := map[string]bool{}
+	for _, e := range entries {
+		if !seen[e.Account] && (*account == "" || e.Account == *account) {
+			seen[e.Account] = true
+			list = append(list, e.Account)
+		}
+	}
+	if *account != "" && len(list) == 0 {
+		list = []string{*account} // sem convites ainda: cota inteira
+	}
+	sort.Strings(list)
+	q, now := quotaOf(cfg), time.Now()
+	for _, a := range list {
+		u := check.Usage(a, now)
+		fmt.Printf("%s: hoje %s • 7 dias %s • ", a, quotaUse(u.Today, q.Daily), quotaUse(u.Week, q.Weekly))
+		switch left, err := q.Remaining(u); {
+		case err != nil:
+			fmt.Println(strings.TrimPrefix(err.Error(), ledger.ErrQuota.Error()+": "))
+		case left < 0:
+			fmt.Println("sem cota")
+		default:
+			fmt.Printf("restam %d\n", left)
+		}
+	}
+	return nil
+}
+
+func quotaOf(cfg config.Config) ledger.Quota {
+	return ledger.Quota{Daily: cfg.Invites.DailyQuota, Weekly: cfg.Invites.WeeklyQuota}
+}
+
+// quotaUse escreve "usado/cota" ("usado" sem cota).
+func quotaUse(used, quota int) string {
+	if quota <= 0 {
+		return fmt.Sprint(used)
+	}
+	return fmt.Sprintf("%d/%d", used, quota)
+}
+
+// loadNote lê e compila o modelo de nota; path vazio = sem nota.
+func loadNote(path string) (*notes.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	t, err := notes.Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// badNotes lista os perfis com nome na lista cuja nota não fecha (os sem
+// nome não são convidados).
+func badNotes(note *notes.Template, targets []crawler.Target) []string {
+	var bad []string
+	for _, t := range targets {
+		if t.Name == "" {
+			continue
+		}
+		if _, err := note.Render(t.NoteFields("")); err != nil {
+			bad = append(bad, t.Name+": "+err.Error())
+		}
+	}
+	return bad
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"CrawlerLinkedin/internal/classify"
+	"CrawlerLinkedin/internal/crawler"
+	"CrawlerLinkedin/internal/results"
+)
+
+// Subcomandos que trabalham sobre os CSVs (e o HTML) que o crawler grava.
+
+func parseCmd(args []string) error {
+	fs := newFlags("parse", "[flags] results_page_1.html", "Roda a extração da busca num HTML salvo com --dump-html (sem login) e grava o CSV.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	query := fs.String("query", "", "Valor da coluna source_query")
+	out := fs.String("o", "-", "Arquivo de saída (- = stdout)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("informe um arquivo HTML")
+	}
+	cfg.Browser.Headless = true
+	items, err := crawler.ParseHTML(cfg, fs.Arg(0), *query)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*out, func(w io.Writer) error { return results.WriteCSV(w, crawler.Rows(items)) })
+}
+
+func exportCmd(args []string) error {
+	fs := newFlags("export", "[flags] arquivo.csv...", "Converte CSVs do crawler para csv, json ou jsonl, com os mesmos filtros da API.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	var q results.Query
+	format := fs.String("format", "jsonl", "Formato: csv, json ou jsonl")
+	out := fs.String("o", "-", "Arquivo de saída (- = stdout)")
+	fs.StringVar(&q.Q, "q", "", "Texto livre: nome, título, empresa, região ou cargo")
+	fs.StringVar(&q.Company, "company", "", "Empresa contém")
+	fs.StringVar(&q.Location, "location", "", "Região contém")
+	fs.StringVar(&q.Title, "title", "", "Título contém")
+	fs.StringVar(&q.Seniority, "seniority", "", "Senioridade (intern, junior, mid, senior, lead, manager, director, c_level; vírgula separa vários)")
+	fs.StringVar(&q.Function, "function", "", "Área (engineering, data, product, sales, hr...; vírgula separa várias)")
+	fs.StringVar(&q.Sort, "sort", "", "Ordenar por name, title, company, location ou captured_at")
+	fs.BoolVar(&q.Desc, "desc", false, "Ordem decrescente")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("informe ao menos um CSV")
+	}
+	if _, ok := results.SortFields[q.Sort]; q.Sort != "" && !ok {
+		return fmt.Errorf("--sort %q inválido", q.Sort)
+	}
+	rules, err := classify.Load(cfg.Classify.Rules)
+	if err != nil {
+		return fmt.Errorf("regras de classificação: %w", err)
+	}
+	var all []results.Row
+	for _, path := range fs.Args() {
+		rows, err := results.ReadCSV(path, 0)
+		if err != nil {
+			return err
+		}
+		all = append(all, rows...)
+	}
+	rules.Fill(all) // CSVs de antes da classificação
+	rows, _ := results.Apply(all, q)
+
+	var write func(w io.Writer) error
+	switch *format {
+	case "csv":
+		write = func(w io.Writer) error { return results.WriteCSV(w, rows) }
+	case "json":
+		write = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(append([]results.Row{}, rows...))
+		}
+	case "jsonl":
+		write = func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			for _, r := range rows {
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		return fmt.Errorf("--format %q inválido (use csv, json ou jsonl)", *format)
+	}
+	return writeOutput(*out, write)
+}
+
+func mergeCmd(args []string) error {
+	fs := newFlags("merge", "[flags] a.csv b.csv...", "Junta CSVs sem repetir perfis (mesma URL): fica a captura mais recente.")
+	out := fs.String("o", "-", "Arquivo de saída (- = stdout)")
+	fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("informe ao menos dois CSVs")
+	}
+	var lists [][]results.Row
+	total := 0
+	for _, path := range fs.Args() {
+		rows, err := results.ReadCSV(path, 0)
+		if err != nil {
+			return err
+		}
+		lists = append(lists, rows)
+		total += len(rows)
+	}
+	merged := results.Merge(lists...)
+	fmt.Fprintf(os.Stderr, "%d linhas lidas, %d perfis únicos\n", total, len(merged))
+	return writeOutput(*out, func(w io.Writer) error { return results.WriteCSV(w, merged) })
+}
+
+func diffCmd(args []string) error {
+	fs := newFlags("diff", "[flags] antigo.csv novo.csv", "Compara dois CSVs pela URL do perfil: novos (+), que saíram (-) e alterados (~).")
+	format := fs.String("format", "text", "Formato: text ou json")
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("informe dois CSVs")
+	}
+	old, err := results.ReadCSV(fs.Arg(0), 0)
+	if err != nil {
+		return err
+	}
+	cur, err := results.ReadCSV(fs.Arg(1), 0)
+	if err != nil {
+		return err
+	}
+	d := results.Compare(old, cur)
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	case "text":
+		for _, r := range d.Added {
+			fmt.Printf("+ %s — %s (%s)\n", r.Name, r.Title, r.URL)
+		}
+		for _, r := range d.Removed {
+			fmt.Printf("- %s — %s (%s)\n", r.Name, r.Title, r.URL)
+		}
+		for _, c := range d.Changed {
+			fmt.Printf("~ %s (%s)\n", c.Row.Name, c.Row.URL)
+			for _, ch := range c.Changes {
+				fmt.Printf("    %s: %q → %q\n", ch.Field, ch.Old, ch.New)
+			}
+		}
+		fmt.Fprintf(os.Stderr, "%d novos, %d saíram, %d alterados\n", len(d.Added), len(d.Removed), len(d.Changed))
+		return nil
+	default:
+		return fmt.Errorf("--format %q inválido (use text ou json)", *format)
+	}
+}
+
+// writeOutput escreve em path ("-" = stdout).
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "-" || path == "" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,2060 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"CrawlerLinkedin/internal/accounts"
+	"CrawlerLinkedin/internal/api"
+	"CrawlerLinkedin/internal/auth"
+	"CrawlerLinkedin/internal/classify"
+	"CrawlerLinkedin/internal/config"
+	"CrawlerLinkedin/internal/events"
+	"CrawlerLinkedin/internal/health"
+	"CrawlerLinkedin/internal/jobs"
+	"CrawlerLinkedin/internal/ledger"
+	"CrawlerLinkedin/internal/logging"
+	"CrawlerLinkedin/internal/metrics"
+	"CrawlerLinkedin/internal/notify"
+	"CrawlerLinkedin/internal/results"
+	"CrawlerLinkedin/internal/scheduler"
+	"CrawlerLinkedin/internal/searches"
+	"CrawlerLinkedin/internal/settings"
+	"CrawlerLinkedin/internal/summary"
+	"CrawlerLinkedin/internal/vault"
+)
+
+// =================== TYPES ===================
+
+// runResponse é o evento "done" do stream de um job.
+type runResponse struct {
+	Ok        bool        `json:"ok"`
+	Status    jobs.Status `json:"status"`
+	Message   string      `json:"message"`
+	JobID     string      `json:"job_id,omitempty"`
+	CSVPath   string      `json:"csv_path"`
+	StartedAt string      `json:"started_at"`
+	EndedAt   string      `json:"ended_at"`
+}
+
+type streamEvent struct {
+	Type string      `json:"type"` // "log" | "done"
+	Msg  string      `json:"msg,omitempty"`
+	Data interface{} `json:"data,omitempty"`
+
+	// só em "log"
+	Level string         `json:"level,omitempty"` // debug, info, warn, error
+	Time  time.Time      `json:"time,omitzero"`
+	Attrs map[string]any `json:"attrs,omitempty"` // job_id, page, profile_count, duration…
+}
+
+// =================== HTML (template) ===================
+
+var pageTmpl = template.Must(template.New("index").Parse(`<!doctype html>
+<html lang="pt-BR"><head>
+<meta charset="utf-8"><meta name="viewport" content="width=device-width,initial-scale=1"/>
+<title>GoLinkedIn • Crawler UI</title>
+<meta name="csrf-token" content="{{.CSRF}}">
+<link rel="icon" href="data:,">
+<script src="https://cdn.tailwindcss.com"></script>
+<script>
+tailwind.config = { theme: { extend: {
+  colors:{ primary:{DEFAULT:'hsl(200 98% 39%)', glow:'hsl(200 100% 50%)'}, accent:'hsl(158 64% 52%)', warning:'hsl(38 92% 50%)', success:'hsl(142 76% 36%)' },
+  boxShadow:{ card:'0 2px 10px -1px rgba(18,38,63,.12)' }
+}}}
+</script>
+<style>
+.gradient-text{background:linear-gradient(135deg,hsl(200 98% 39%),hsl(200 100% 50%));-webkit-background-clip:text;background-clip:text;color:transparent}
+.table-wrap{max-height:420px;overflow:auto} th,td{white-space:nowrap}
+.log-debug{color:#9ca3af} .log-warn{color:#b45309} .log-error{color:#b91c1c;font-weight:600} .log-attrs{color:#6b7280}
+#logBox.warn-only .log-debug,#logBox.warn-only .log-info{display:none}
+</style>
+</head>
+<body class="bg-gray-50 text-gray-900">
+<div class="max-w-7xl mx-auto px-4 py-8">
+  <header class="relative text-center mb-8">
+    <h1 class="text-3xl font-bold gradient-text">GoLinkedIn</h1>
+    <form method="post" action="/logout" class="absolute right-0 top-0 text-sm text-gray-600 flex items-center gap-2">
+      <span>{{if .Name}}{{.Name}}{{else}}{{.User}}{{end}}</span>
+      <input type="hidden" name="csrf_token" value="{{.CSRF}}">
+      <button class="px-2 py-1 rounded-md border hover:bg-gray-100">Sair</button>
+    </form>
+  </header>
+
+  <div class="grid grid-cols-1 lg:grid-cols-3 gap-6">
+    <!-- Config -->
+    <div class="lg:col-span-1">
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <h2 class="text-lg font-semibold mb-4 flex items-center">
+          <svg class="w-5 h-5 mr-2 text-primary" fill="none" stroke="currentColor" stroke-width="2" viewBox="0 0 24 24"><path d="M12 22s8-4 8-10V5l-8-3-8 3v7c0 6 8 10 8 10z"/></svg>
+          Contas
+        </h2>
+        <div class="space-y-3">
+          <label class="block">
+            <span class="text-sm">Conta</span>
+            <div class="mt-1 flex gap-2">
+              <select id="account" class="w-full border rounded-md px-3 py-2 focus:ring-2 focus:ring-primary"></select>
+              <button id="delAccountBtn" type="button" title="Remover conta" class="px-3 rounded-md border hover:bg-gray-100">✕</button>
+            </div>
+          </label>
+          <ul id="accountsList" class="text-xs text-gray-600 space-y-1"></ul>
+          <details class="text-sm">
+            <summary class="cursor-pointer text-gray-600">Nova conta / editar</summary>
+            <div class="space-y-2 mt-2">
+              <input id="acc-alias" type="text" class="w-full border rounded-md px-3 py-2" placeholder="Alias (ex.: recruiter-1)">
+              <input id="acc-email" type="email" class="w-full border rounded-md px-3 py-2" placeholder="seu.email@exemplo.com">
+              <input id="acc-password" type="password" class="w-full border rounded-md px-3 py-2" placeholder="Senha do LinkedIn (vazio = manter)">
+              <input id="acc-totp" type="password" autocomplete="off" class="w-full border rounded-md px-3 py-2" placeholder="Segredo TOTP do app autenticador (opcional)">
+              <div class="grid grid-cols-4 gap-2">
+                <label class="block text-xs">Páginas/dia<input id="acc-pages" type="number" min="1" value="30" class="w-full border rounded-md px-2 py-1"></label>
+                <label class="block text-xs" title="0 = a cota da configuração (invites.daily_quota)">Convites/dia<input id="acc-invites" type="number" min="0" value="0" class="w-full border rounded-md px-2 py-1"></label>
+                <label class="block text-xs" title="Em 7 dias corridos; 0 = a cota da configuração (invites.weekly_quota)">Convites/semana<input id="acc-weekly" type="number" min="0" value="0" class="w-full border rounded-md px-2 py-1"></label>
+                <label class="block text-xs">Cooldown (h)<input id="acc-cooldown" type="number" min="1" value="24" class="w-full border rounded-md px-2 py-1"></label>
+              </div>
+              <button id="saveAccountBtn" type="button" class="w-full py-1 rounded-md border hover:bg-gray-100">Salvar conta</button>
+            </div>
+          </details>
+        </div>
+        <hr class="my-4">
+        <h2 class="text-lg font-semibold mb-3 flex items-center">
+          <svg class="w-5 h-5 mr-2 text-primary" fill="none" stroke="currentColor" stroke-width="2" viewBox="0 0 24 24"><circle cx="11" cy="11" r="8"></circle><line x1="21" y1="21" x2="16.65" y2="16.65"/></svg>
+          Busca
+        </h2>
+        <div class="space-y-3">
+          <label class="block">
+            <span class="text-sm">Query</span>
+            <input id="query" type="text" class="mt-1 w-full border rounded-md px-3 py-2 focus:ring-2 focus:ring-primary" placeholder='Ex.: "Boticário"'>
+          </label>
+          <label class="block">
+            <span class="text-sm">Páginas</span>
+            <input id="max-pages" type="number" min="1" value="2" class="mt-1 w-full border rounded-md px-3 py-2 focus:ring-2 focus:ring-primary">
+          </label>
+          <label class="block">
+            <span class="text-sm">Localidades (geoUrn)</span>
+            <input id="geo" type="text" value="105871508" class="mt-1 w-full border rounded-md px-3 py-2 focus:ring-2 focus:ring-primary" placeholder="IDs separados por vírgula; vazio = qualquer lugar">
+          </label>
+          <label class="inline-flex items-center text-sm"><input id="first-company" type="checkbox" checked class="mr-2">Filtrar pela 1ª "Empresa atual"</label>
+          <div class="grid grid-cols-3 gap-3 text-sm">
+            <label class="inline-flex items-center"><input id="headless" type="checkbox" class="mr-2">Headless</label>
+            <label class="inline-flex items-center"><input id="dump-html" type="checkbox" class="mr-2">Dump HTML</label>
+            <label class="inline-flex items-center" title="Navega e captura, mas não clica em convites nem filtros: os cliques vão para o log e para screenshots na pasta do job"><input id="dry-run" type="checkbox" class="mr-2">Dry-run</label>
+          </div>
+        </div>
+
+        <button id="runBtn" class="mt-5 w-full py-2 rounded-lg bg-primary text-white font-medium hover:opacity-90">
+          ▶️ Iniciar Crawler
+        </button>
+        <details class="mt-4 text-sm">
+          <summary class="cursor-pointer text-gray-600">Salvar como busca agendada</summary>
+          <div class="space-y-2 mt-2">
+            <input id="ss-name" type="text" class="w-full border rounded-md px-3 py-2" placeholder="Nome (ex.: Devs SP toda segunda)">
+            <input id="ss-cron" type="text" value="0 9 * * 1" class="w-full border rounded-md px-3 py-2 font-mono" placeholder="cron: minuto hora dia mês dia-da-semana">
+            <div class="grid grid-cols-2 gap-2">
+              <label class="block text-xs">Jitter (min)<input id="ss-jitter" type="number" min="0" max="120" value="15" class="w-full border rounded-md px-2 py-1"></label>
+              <label class="block text-xs">Horário perdido
+                <select id="ss-missed" class="w-full border rounded-md px-2 py-1">
+                  <option value="skip">pular</option>
+                  <option value="run_once">rodar ao voltar</option>
+                </select>
+              </label>
+            </div>
+            <button id="saveSearchBtn" type="button" class="w-full py-1 rounded-md border hover:bg-gray-100">Salvar busca</button>
+          </div>
+        </details>
+      </div>
+    </div>
+
+    <!-- Execução -->
+    <div class="lg:col-span-2 space-y-6">
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <div class="flex items-center justify-between mb-3">
+          <h2 class="text-lg font-semibold flex items-center">
+            <svg class="w-5 h-5 mr-2 text-primary" fill="none" stroke="currentColor" stroke-width="2" viewBox="0 0 24 24"><polyline points="22 12 18 12 15 21 9 3 6 12 2 12"/></svg>
+            Execução & Logs
+          </h2>
+          <div class="flex items-center gap-2">
+            <label class="text-xs text-gray-600"><input id="logWarnOnly" type="checkbox"> só avisos e erros</label>
+            <button id="cancelBtn" type="button" class="hidden text-xs px-2 py-1 rounded-md border border-red-300 text-red-700 hover:bg-red-50">⏹️ Cancelar</button>
+            <span id="statusBadge" class="text-xs px-2 py-1 rounded-full bg-gray-100 text-gray-600">Aguardando</span>
+          </div>
+        </div>
+
+        <div class="space-y-3">
+          <div>
+            <div class="flex justify-between text-xs mb-1">
+              <span>Progresso</span><span id="progressLabel">0%</span>
+            </div>
+            <div class="w-full bg-gray-200 rounded-full h-2">
+              <div id="progressBar" class="h-2 rounded-full bg-primary" style="width:0%"></div>
+            </div>
+          </div>
+
+          <div id="challengeBox" class="hidden border border-yellow-300 rounded-md bg-yellow-50 p-3 space-y-2">
+            <div class="text-sm font-medium text-yellow-800">
+              O LinkedIn pediu verificação (<span id="challengeKind">—</span>). Clique na imagem para interagir com a página ou envie o código 2FA.
+            </div>
+            <img id="challengeImg" alt="Tela do navegador" class="w-full border rounded cursor-crosshair">
+            <div class="flex gap-2">
+              <input id="challengeCode" type="text" inputmode="numeric" autocomplete="one-time-code" placeholder="Código de verificação" class="flex-1 rounded-md border-gray-300 text-sm">
+              <button id="challengeSend" type="button" class="px-3 py-1 rounded-md bg-primary text-white text-sm">Enviar código</button>
+            </div>
+          </div>
+
+          <div id="logBox" class="border rounded-md bg-gray-50 h-64 overflow-y-auto p-3 text-xs font-mono text-gray-800">
+            Aguardando logs…
+          </div>
+
+          <div class="flex items-center justify-between">
+            <div class="text-xs text-gray-500">
+              <span id="startedAt">—</span> • <span id="endedAt">—</span>
+            </div>
+            <a id="csvLink" href="#" class="hidden text-sm px-3 py-1 rounded-md border hover:bg-gray-100">Baixar CSV</a>
+          </div>
+        </div>
+      </div>
+
+      <!-- Resultados -->
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <div class="flex items-center justify-between mb-3">
+          <h2 class="text-lg font-semibold flex items-center">
+            <svg class="w-5 h-5 mr-2 text-primary" fill="none" stroke="currentColor" stroke-width="2" viewBox="0 0 24 24"><ellipse cx="12" cy="5" rx="9" ry="3"></ellipse><path d="M21 12c0 1.66-4 3-9 3s-9-1.34-9-3"></path><path d="M3 5v14c0 1.66 4 3 9 3s9-1.34 9-3V5"></path></svg>
+            Resultados
+          </h2>
+          <span id="resultsBadge" class="text-xs px-2 py-1 rounded-full bg-gray-100 text-gray-600">0 itens</span>
+        </div>
+
+        <div id="resultsFilters" class="hidden grid grid-cols-2 md:grid-cols-7 gap-2 mb-3 text-sm">
+          <input id="f-q" type="search" placeholder="Buscar…" class="col-span-2 md:col-span-1 border rounded-md px-2 py-1">
+          <input id="f-title" type="text" placeholder="Título contém" class="border rounded-md px-2 py-1">
+          <input id="f-company" type="text" placeholder="Empresa contém" class="border rounded-md px-2 py-1">
+          <input id="f-location" type="text" placeholder="Região contém" class="border rounded-md px-2 py-1">
+          <select id="f-seniority" class="border rounded-md px-2 py-1">
+            <option value="">Senioridade</option>
+            <option value="intern">Estágio</option>
+            <option value="junior">Júnior</option>
+            <option value="mid">Pleno</option>
+            <option value="senior">Sênior</option>
+            <option value="lead">Liderança técnica</option>
+            <option value="manager">Gerência</option>
+            <option value="director">Diretoria</option>
+            <option value="c_level">C-level</option>
+          </select>
+          <select id="f-function" class="border rounded-md px-2 py-1">
+            <option value="">Área</option>
+            <option value="engineering">Engenharia</option>
+            <option value="data">Dados</option>
+            <option value="product">Produto</option>
+            <option value="design">Design</option>
+            <option value="sales">Vendas</option>
+            <option value="marketing">Marketing</option>
+            <option value="hr">RH</option>
+            <option value="finance">Finanças</option>
+            <option value="legal">Jurídico</option>
+            <option value="support">Suporte</option>
+            <option value="operations">Operações</option>
+          </select>
+          <select id="f-sort" class="border rounded-md px-2 py-1">
+            <option value="">Ordem original</option>
+            <option value="name">Nome (A–Z)</option>
+            <option value="-name">Nome (Z–A)</option>
+            <option value="company">Empresa (A–Z)</option>
+            <option value="-company">Empresa (Z–A)</option>
+            <option value="location">Região (A–Z)</option>
+            <option value="title">Título (A–Z)</option>
+            <option value="-captured_at">Mais recentes</option>
+          </select>
+        </div>
+
+        <div id="qualityBox" class="hidden mb-3 text-sm border rounded-md p-3"></div>
+
+        <div id="noResults" class="text-sm text-gray-500">Nenhum resultado ainda. Execute o crawler.</div>
+
+        <div id="resultsWrap" class="table-wrap hidden border rounded-md">
+          <table class="min-w-full divide-y divide-gray-200 text-sm">
+            <thead class="bg-gray-50">
+              <tr>
+                <th class="px-3 py-2"><input id="selPage" type="checkbox" title="Selecionar os perfis desta página para convidar"></th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Nome</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Título</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Região</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Empresa</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Cargo/Resumo</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Senioridade</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Área</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">URL</th>
+                <th class="px-3 py-2 text-left font-medium text-gray-700">Capturado</th>
+              </tr>
+            </thead>
+            <tbody id="resultsBody" class="divide-y divide-gray-200"></tbody>
+          </table>
+        </div>
+        <div id="pager" class="hidden flex items-center justify-between mt-3 text-sm">
+          <span id="pageInfo" class="text-gray-600"></span>
+          <div class="flex items-center gap-2">
+            <select id="pageSize" class="border rounded-md px-2 py-1">
+              <option>25</option><option selected>50</option><option>100</option>
+            </select>
+            <button id="prevPage" type="button" class="px-3 py-1 rounded-md border hover:bg-gray-100 disabled:opacity-40">Anterior</button>
+            <button id="nextPage" type="button" class="px-3 py-1 rounded-md border hover:bg-gray-100 disabled:opacity-40">Próxima</button>
+          </div>
+        </div>
+
+        <!-- Convites: só para os perfis marcados na tabela -->
+        <div id="inviteBox" class="hidden mt-4 border rounded-md p-3 space-y-2 text-sm">
+          <div class="flex items-center justify-between">
+            <span class="font-medium">Convidar a lista revisada <span id="inviteCount" class="font-normal text-gray-500">(0 perfis marcados)</span></span>
+            <button id="clearSelBtn" type="button" class="text-xs text-primary underline">limpar seleção</button>
+          </div>
+          <label class="block">
+            <span>Nota do convite (opcional)</span>
+            <textarea id="invite-note" rows="4" class="mt-1 w-full border rounded-md px-3 py-2 focus:ring-2 focus:ring-primary" placeholder='{{"Olá {{.FirstName}}, vi que você é {{.Title}}{{if .Company}} na {{.Company}}{{end}}. Vamos conectar?"}}'></textarea>
+          </label>
+          <div class="flex items-center justify-between text-xs text-gray-500">
+            <span>Campos: <code>{{"{{.FirstName}} {{.LastName}} {{.Title}} {{.Company}} {{.Location}}"}}</code></span>
+            <button id="notePreviewBtn" type="button" class="px-2 py-1 rounded-md border hover:bg-gray-100">Prévia</button>
+          </div>
+          <ul id="notePreview" class="text-xs space-y-2"></ul>
+          <button id="inviteBtn" type="button" class="w-full py-2 rounded-lg bg-primary text-white font-medium hover:opacity-90 disabled:opacity-40" disabled>✉️ Convidar marcados</button>
+          <p class="text-xs text-gray-500">Usa a conta, "Headless" e "Dry-run" do formulário de busca. Nenhuma busca convida sozinha.</p>
+        </div>
+      </div>
+
+      <!-- Jobs -->
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <h2 class="text-lg font-semibold mb-3">Meus jobs</h2>
+        <div id="noJobs" class="text-sm text-gray-500">Nenhum job ainda.</div>
+        <ul id="jobsList" class="divide-y divide-gray-200 text-sm"></ul>
+      </div>
+
+      <!-- Buscas salvas -->
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <h2 class="text-lg font-semibold mb-3">Buscas salvas</h2>
+        <div id="noSearches" class="text-sm text-gray-500">Nenhuma busca salva.</div>
+        <ul id="searchesList" class="divide-y divide-gray-200 text-sm"></ul>
+      </div>
+
+      <!-- Convites -->
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <div class="flex items-center justify-between mb-3">
+          <h2 class="text-lg font-semibold">Convites</h2>
+          <a href="/api/v1/invites/export" class="text-sm text-primary underline">Baixar histórico (CSV)</a>
+        </div>
+        <div id="noInvites" class="text-sm text-gray-500">Nenhum convite ainda.</div>
+        <ul id="invitesList" class="divide-y divide-gray-200 text-sm"></ul>
+        <details class="mt-4 text-sm">
+          <summary class="cursor-pointer text-primary">Não contatar</summary>
+          <div class="mt-3 space-y-2">
+            <p class="text-xs text-gray-500">Uma URL de perfil ou <code>empresa: Nome</code> por linha. Quem já foi convidado (histórico acima) é pulado sem estar aqui.</p>
+            <textarea id="suppressText" rows="6" class="w-full border rounded-md px-2 py-1 font-mono text-xs"></textarea>
+            <div class="flex items-center gap-3">
+              <button id="saveSuppressBtn" type="button" class="px-3 py-1 rounded-md border hover:bg-gray-100">Salvar</button>
+              <span id="suppressInfo" class="text-xs text-gray-500"></span>
+            </div>
+          </div>
+        </details>
+      </div>
+
+      <!-- Notificações -->
+      <div class="bg-white border rounded-xl shadow-card p-5">
+        <h2 class="text-lg font-semibold mb-3">Notificações</h2>
+        <div id="noHooks" class="text-sm text-gray-500">Nenhum webhook ou email configurado.</div>
+        <ul id="hooksList" class="divide-y divide-gray-200 text-sm"></ul>
+        <div id="hookSecret" class="hidden mt-3 text-xs bg-yellow-50 border border-yellow-200 rounded-md p-2">
+          Segredo do webhook (guarde agora, ele não aparece de novo): <code id="hookSecretValue" class="break-all"></code>
+        </div>
+        <details class="mt-4 text-sm">
+          <summary class="cursor-pointer text-primary">Adicionar destino</summary>
+          <div class="mt-3 space-y-2">
+            <div class="flex gap-2">
+              <select id="hook-type" class="border rounded-md px-2 py-1">
+                <option value="webhook">Webhook</option>
+                <option value="email">Email</option>
+              </select>
+              <input id="hook-target" type="text" placeholder="https://exemplo.com/webhook" class="flex-1 border rounded-md px-2 py-1">
+            </div>
+            <div class="flex flex-wrap gap-x-4 gap-y-1">
+              <label><input type="checkbox" name="hook-event" value="job.completed" checked> job concluído</label>
+              <label><input type="checkbox" name="hook-event" value="job.failed" checked> job falhou</label>
+              <label><input type="checkbox" name="hook-event" value="job.challenge_required" checked> verificação pendente</label>
+              <label><input type="checkbox" name="hook-event" value="profiles.new"> perfis novos</label>
+            </div>
+            <button id="addHookBtn" type="button" class="px-3 py-1 rounded-md border hover:bg-gray-100">Adicionar</button>
+          </div>
+        </details>
+      </div>
+    </div>
+  </div>
+
+  <footer class="text-center text-xs text-gray-500 mt-8"></footer>
+</div>
+
+<script>
+(function () {
+  const runBtn = document.getElementById('runBtn');
+  const logBox = document.getElementById('logBox');
+  const statusBadge = document.getElementById('statusBadge');
+  const progressBar = document.getElementById('progressBar');
+  const progressLabel = document.getElementById('progressLabel');
+  const csvLink = document.getElementById('csvLink');
+  const startedAt = document.getElementById('startedAt');
+  const endedAt = document.getElementById('endedAt');
+  const resultsBadge = document.getElementById('resultsBadge');
+  const noResults = document.getElementById('noResults');
+  const resultsWrap = document.getElementById('resultsWrap');
+  const resultsBody = document.getElementById('resultsBody');
+  const jobsList = document.getElementById('jobsList');
+  const noJobs = document.getElementById('noJobs');
+  const csrfToken = document.querySelector('meta[name="csrf-token"]').content;
+  const challengeBox = document.getElementById('challengeBox');
+  const challengeImg = document.getElementById('challengeImg');
+  const challengeKind = document.getElementById('challengeKind');
+  const challengeCode = document.getElementById('challengeCode');
+  const cancelBtn = document.getElementById('cancelBtn');
+  let currentJobId = null;
+
+  // cancelJob pede o cancelamento; um job rodando termina a página atual e
+  // grava os resultados parciais antes de parar.
+  async function cancelJob(id) {
+    const resp = await fetch('/api/v1/jobs/' + encodeURIComponent(id) + '/cancel', {
+      method: 'POST',
+      headers: {'X-CSRF-Token': csrfToken}
+    });
+    if (!resp.ok) { appendLog('Erro ao cancelar: ' + (await apiError(resp)), 'error'); return; }
+    appendLog('Cancelamento pedido; aguardando o crawler terminar a página atual…', 'warn');
+    loadJobs();
+  }
+
+  cancelBtn.addEventListener('click', () => {
+    if (currentJobId) cancelJob(currentJobId);
+  });
+
+  async function sendChallenge(cmd) {
+    if (!currentJobId) return;
+    const resp = await fetch('/jobs/' + encodeURIComponent(currentJobId) + '/challenge', {
+      method: 'POST',
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: JSON.stringify(cmd)
+    });
+    if (!resp.ok) appendLog('Erro ao enviar comando: ' + (await resp.text()), 'error');
+  }
+
+  challengeImg.addEventListener('click', (e) => {
+    const r = challengeImg.getBoundingClientRect();
+    sendChallenge({type: 'click', x: (e.clientX - r.left) / r.width, y: (e.clientY - r.top) / r.height});
+  });
+
+  document.getElementById('challengeSend').addEventListener('click', () => {
+    const code = challengeCode.value.trim();
+    if (!code) return;
+    sendChallenge({type: 'code', code});
+    challengeCode.value = '';
+  });
+
+  // appendLog mostra um registro; level (debug, info, warn, error) define a
+  // cor e o filtro "só avisos e erros". attrs são os campos do slog.
+  function appendLog(line, level, attrs, time) {
+    level = level || 'info';
+    if (logBox.textContent.trim() === 'Aguardando logs…') logBox.textContent = '';
+    const p = document.createElement('div');
+    p.className = 'log-' + level;
+    const ts = (time ? new Date(time) : new Date()).toLocaleTimeString();
+    p.textContent = ts + ' ' + (level === 'info' ? '' : level.toUpperCase() + ' ') + line;
+    const fields = Object.entries(attrs || {}).filter(([k]) => k !== 'job_id');
+    if (fields.length) {
+      const a = document.createElement('span');
+      a.className = 'log-attrs';
+      a.textContent = ' ' + fields.map(([k, v]) => k + '=' + (typeof v === 'string' ? v : JSON.stringify(v))).join(' ');
+      p.appendChild(a);
+    }
+    logBox.appendChild(p);
+    logBox.scrollTop = logBox.scrollHeight;
+  }
+
+  document.getElementById('logWarnOnly').addEventListener('change', (e) => {
+    logBox.classList.toggle('warn-only', e.target.checked);
+    logBox.scrollTop = logBox.scrollHeight;
+  });
+
+  function setStatus(txt, color) {
+    statusBadge.textContent = txt;
+    statusBadge.className = 'text-xs px-2 py-1 rounded-full ' + color;
+  }
+
+  function fakeProgressStart() {
+    let v = 0;
+    const id = setInterval(() => {
+      v = Math.min(95, v + Math.random()*8);
+      progressBar.style.width = v.toFixed(0) + '%';
+      progressLabel.textContent = v.toFixed(0) + '%';
+      if (v >= 95) clearInterval(id);
+    }, 600);
+    return () => { progressBar.style.width = '100%'; progressLabel.textContent = '100%'; clearInterval(id); }
+  }
+
+  // =========== Resultados (paginados no servidor) ===========
+  const resultsFilters = document.getElementById('resultsFilters');
+  const pager = document.getElementById('pager');
+  const pageInfo = document.getElementById('pageInfo');
+  const prevPage = document.getElementById('prevPage');
+  const nextPage = document.getElementById('nextPage');
+  const pageSize = document.getElementById('pageSize');
+  let resultsJobId = null;
+  let resultsOffset = 0;
+  const selected = new Set(); // URLs marcadas para convidar (valem entre páginas)
+
+  function renderResults(page) {
+    resultsBody.innerHTML = '';
+    const rows = (page && page.items) || [];
+    pager.classList.toggle('hidden', !page || !page.total);
+    inviteBox.classList.toggle('hidden', !page || !page.total);
+    selPage.checked = false;
+    if (!rows.length) {
+      noResults.classList.remove('hidden');
+      resultsWrap.classList.add('hidden');
+      resultsBadge.textContent = (page ? page.total : 0) + ' itens';
+      if (page && page.total) pageInfo.textContent = 'Nada nesta página';
+      return;
+    }
+    noResults.classList.add('hidden');
+    resultsWrap.classList.remove('hidden');
+    resultsBadge.textContent = page.total + ' itens';
+    pageInfo.textContent = (page.offset + 1) + '–' + (page.offset + rows.length) + ' de ' + page.total;
+    prevPage.disabled = page.offset === 0;
+    nextPage.disabled = page.offset + rows.length >= page.total;
+
+    for (const r of rows) {
+      const tr = document.createElement('tr');
+      const invitable = (r.url || '').includes('linkedin.com/in/');
+      tr.innerHTML =
+        '<td class="px-3 py-2">'+(invitable ? '<input type="checkbox" data-sel="'+escapeHTML(r.url)+'"'+(selected.has(r.url) ? ' checked' : '')+'>' : '')+'</td>'+
+        '<td class="px-3 py-2">'+escapeHTML(r.name||'')+'</td>'+
+        '<td class="px-3 py-2">'+escapeHTML(r.title||'')+'</td>'+
+        '<td class="px-3 py-2">'+escapeHTML(r.location||'')+'</td>'+
+        '<td class="px-3 py-2'+(r.company_source && r.company_confidence < 0.5 ? ' text-gray-400 italic' : '')+'"'+
+          (r.company_source ? ' title="fonte: '+escapeHTML(companySources[r.company_source] || r.company_source)+' • confiança '+Math.round(r.company_confidence*100)+'%"' : '')+'>'+
+          escapeHTML(r.company||'')+'</td>'+
+        '<td class="px-3 py-2">'+escapeHTML(r.role||'')+'</td>'+
+        '<td class="px-3 py-2 whitespace-nowrap">'+escapeHTML(optionLabel('f-seniority', r.seniority))+'</td>'+
+        '<td class="px-3 py-2 whitespace-nowrap">'+escapeHTML(optionLabel('f-function', r.function))+'</td>'+
+        '<td class="px-3 py-2"><a href="'+encodeURI(r.url||'#')+'" target="_blank" class="text-primary underline">abrir</a></td>'+
+        '<td class="px-3 py-2">'+escapeHTML(r.captured_at||'')+'</td>';
+      resultsBody.appendChild(tr);
+    }
+  }
+
+  const companySources = {facet: 'filtro "Empresa atual"', headline: 'título', summary: 'resumo', subtitle: 'subtítulo'};
+
+  // optionLabel mostra o rótulo em português do filtro (rótulos de regras
+  // próprias saem como vieram).
+  function optionLabel(selectId, value) {
+    if (!value) return '';
+    const o = Array.from(document.getElementById(selectId).options).find(o => o.value === value);
+    return o ? o.textContent : value;
+  }
+
+  async function loadResults(offset) {
+    if (!resultsJobId) return;
+    resultsOffset = Math.max(0, offset || 0);
+    const params = new URLSearchParams({offset: resultsOffset, limit: pageSize.value});
+    for (const [k, id] of [['q','f-q'], ['title','f-title'], ['company','f-company'], ['location','f-location'],
+                           ['seniority','f-seniority'], ['function','f-function'], ['sort','f-sort']]) {
+      const v = document.getElementById(id).value.trim();
+      if (v) params.set(k, v);
+    }
+    const resp = await fetch('/api/v1/jobs/' + encodeURIComponent(resultsJobId) + '/results?' + params);
+    if (!resp.ok) { appendLog('Erro ao carregar resultados: ' + (await apiError(resp)), 'error'); return; }
+    resultsFilters.classList.remove('hidden');
+    renderResults(await resp.json());
+  }
+
+  function showJobResults(id) {
+    if (resultsJobId !== id) {
+      selected.clear();
+      updateSelection();
+    }
+    resultsJobId = id;
+    loadResults(0);
+    loadQuality(id);
+  }
+
+  // =========== Qualidade da captura ===========
+  const qualityBox = document.getElementById('qualityBox');
+  const qualityLabels = {
+    empty_pages: 'páginas sem cards', duplicates: 'repetidos descartados', empty_name: 'nome vazio',
+    empty_title: 'título vazio', empty_location: 'região vazia', empty_company: 'empresa vazia',
+    name_from_url: 'nome tirado da URL', location_is_title: 'região igual ao título',
+    suspect_location: 'região suspeita', suspect_company: 'empresa suspeita'
+  };
+
+  // loadQuality mostra o relatório de qualidade do job (sem relatório, some).
+  async function loadQuality(id) {
+    qualityBox.classList.add('hidden');
+    const resp = await fetch('/api/v1/jobs/' + encodeURIComponent(id) + '/quality');
+    if (!resp.ok || resultsJobId !== id) return;
+    const q = await resp.json();
+    qualityBox.className = 'mb-3 text-sm border rounded-md p-3 ' + (q.degraded ? 'border-amber-300 bg-amber-50' : 'bg-gray-50');
+    qualityBox.innerHTML =
+      '<div class="font-medium mb-1 '+(q.degraded ? 'text-amber-800' : 'text-gray-700')+'">'+
+        (q.degraded ? 'Captura degradada: o LinkedIn pode ter mudado a página' : 'Qualidade da captura: ok')+
+        ' <span class="text-xs font-normal text-gray-500">• '+q.profiles+' perfis em '+q.pages+' páginas</span></div>'+
+      '<div class="grid grid-cols-2 md:grid-cols-5 gap-x-4 gap-y-1 text-xs">'+
+      (q.checks || []).map(c =>
+        '<span class="'+(c.over ? 'text-amber-800 font-semibold' : 'text-gray-600')+'" title="limite '+c.max_pct+'%">'+
+          escapeHTML(qualityLabels[c.name] || c.name)+': '+c.count+' ('+c.pct+'%)</span>').join('')+
+      '</div>';
+  }
+
+  let filterTimer = null;
+  for (const id of ['f-q', 'f-title', 'f-company', 'f-location']) {
+    document.getElementById(id).addEventListener('input', () => {
+      clearTimeout(filterTimer);
+      filterTimer = setTimeout(() => loadResults(0), 300);
+    });
+  }
+  for (const id of ['f-seniority', 'f-function', 'f-sort']) {
+    document.getElementById(id).addEventListener('change', () => loadResults(0));
+  }
+  pageSize.addEventListener('change', () => loadResults(0));
+  prevPage.addEventListener('click', () => loadResults(resultsOffset - parseInt(pageSize.value, 10)));
+  nextPage.addEventListener('click', () => loadResults(resultsOffset + parseInt(pageSize.value, 10)));
+
+  async function loadAccounts() {
+    const resp = await fetch('/api/v1/accounts');
+    if (!resp.ok) return;
+    const accounts = (await resp.json()).items;
+    const sel = document.getElementById('account');
+    const prev = sel.value;
+    sel.innerHTML = '<option value="">Automático (conta com orçamento)</option>';
+    const list = document.getElementById('accountsList');
+    list.innerHTML = '';
+    for (const a of accounts) {
+      const o = document.createElement('option');
+      o.value = a.alias;
+      o.textContent = a.alias;
+      sel.appendChild(o);
+
+      const li = document.createElement('li');
+      li.textContent = a.alias + ': páginas ' + a.pages_today + '/' + a.daily_pages +
+        ' • convites hoje ' + a.invites_today + (a.daily_invite_quota ? '/' + a.daily_invite_quota : '') +
+        ' • semana ' + a.invites_week + (a.weekly_invite_quota ? '/' + a.weekly_invite_quota : '') +
+        (a.invites_paused ? ' • convites pausados (limite semanal do LinkedIn) até ' + new Date(a.invites_paused_until).toLocaleString() :
+          a.remaining_invites < 0 ? ' • sem cota de convites' : ' • restam ' + a.remaining_invites) +
+        (a.cooling_down ? ' • cooldown até ' + new Date(a.cooldown_until).toLocaleString() : '');
+      if (a.invites_paused || a.remaining_invites === 0) li.className = 'text-amber-700';
+      list.appendChild(li);
+    }
+    sel.value = prev;
+  }
+
+  document.getElementById('saveAccountBtn').addEventListener('click', async () => {
+    const alias = document.getElementById('acc-alias').value.trim();
+    if (!alias) { alert('Informe o alias da conta.'); return; }
+    const body = {
+      daily_pages:    parseInt(document.getElementById('acc-pages').value || '0', 10),
+      daily_invites:  parseInt(document.getElementById('acc-invites').value || '0', 10),
+      weekly_invites: parseInt(document.getElementById('acc-weekly').value || '0', 10),
+      cooldown_hours: parseInt(document.getElementById('acc-cooldown').value || '0', 10)
+    };
+    const email = document.getElementById('acc-email').value.trim();
+    const password = document.getElementById('acc-password').value;
+    const totp = document.getElementById('acc-totp').value.trim();
+    if (email || password) { body.email = email; body.password = password; }
+    if (totp) body.totp_secret = totp;
+    const resp = await fetch('/api/v1/accounts/' + encodeURIComponent(alias), {
+      method: 'PUT',
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: JSON.stringify(body)
+    });
+    if (!resp.ok) { alert(await apiError(resp)); return; }
+    document.getElementById('acc-password').value = '';
+    document.getElementById('acc-totp').value = '';
+    await loadAccounts();
+    document.getElementById('account').value = alias;
+  });
+
+  document.getElementById('delAccountBtn').addEventListener('click', async () => {
+    const alias = document.getElementById('account').value;
+    if (!alias || !confirm('Remover a conta "' + alias + '" e sua credencial?')) return;
+    const resp = await fetch('/api/v1/accounts/' + encodeURIComponent(alias), {
+      method: 'DELETE',
+      headers: {'X-CSRF-Token': csrfToken}
+    });
+    if (!resp.ok) { alert(await apiError(resp)); return; }
+    loadAccounts();
+  });
+
+  async function loadJobs() {
+    const resp = await fetch('/api/v1/jobs');
+    if (!resp.ok) return;
+    const jobs = (await resp.json()).items;
+    jobsList.innerHTML = '';
+    noJobs.classList.toggle('hidden', jobs.length > 0);
+    for (const j of jobs) {
+      const li = document.createElement('li');
+      li.className = 'py-2 flex items-center justify-between';
+      li.innerHTML =
+        '<span>'+(j.invite_from ? '✉️ convites para '+j.invite_urls.length+' perfis' : escapeHTML(j.query))+' <span class="text-xs text-gray-500">• '+escapeHTML(new Date(j.created_at).toLocaleString())+' • '+escapeHTML(j.status)+
+        (j.assigned_account ? ' • '+escapeHTML(j.assigned_account) : '')+
+        (j.dry_run ? ' • dry-run' : '')+
+        (j.invite_from && j.status === 'done' ? ' • '+j.invites+' enviados' : '')+
+        (j.degraded ? ' • <span class="text-amber-700 font-medium" title="'+escapeHTML((j.quality_issues || []).join('\n'))+'">degradado</span>' : '')+
+        (j.status === 'queued' && j.message ? ' • '+escapeHTML(j.message) : '')+'</span></span>'+
+        '<span class="space-x-2">'+
+        (j.status === 'queued' || j.status === 'running' ? '<button type="button" data-cancel="'+escapeHTML(j.id)+'" class="text-red-700 underline">cancelar</button>' : '')+
+        (j.export_url ? '<button type="button" data-job="'+escapeHTML(j.id)+'" class="text-primary underline">resultados</button>'+
+          '<a href="'+encodeURI(j.export_url)+'" class="text-primary underline">CSV</a>' : '')+'</span>';
+      jobsList.appendChild(li);
+    }
+  }
+
+  // apiError monta a mensagem de um erro da /api/v1 ({error: {message, details}}).
+  async function apiError(resp) {
+    try {
+      const e = (await resp.json()).error;
+      const details = (e.details || []).map(d => d.field + ': ' + d.message);
+      return [e.message].concat(details).join('\n');
+    } catch {
+      return 'Erro HTTP ' + resp.status;
+    }
+  }
+
+  jobsList.addEventListener('click', (e) => {
+    const d = e.target.dataset || {};
+    if (d.job) showJobResults(d.job);
+    if (d.cancel) cancelJob(d.cancel);
+  });
+
+  function escapeHTML(s){return (s||'').replace(/[&<>"']/g,m=>({'&':'&amp;','<':'&lt;','>':'&gt;','"':'&quot;',"'":'&#39;'}[m]));}
+
+  // searchParams lê o formulário de busca (também usado pelas buscas salvas).
+  function searchParams() {
+    return {
+      account:     document.getElementById('account').value,
+      query:       document.getElementById('query').value.trim(),
+      max_pages:   parseInt(document.getElementById('max-pages').value || '1', 10),
+      headless:    document.getElementById('headless').checked,
+      dump_html:   document.getElementById('dump-html').checked,
+      dry_run:     document.getElementById('dry-run').checked,
+      facets: {
+        geo_urns: document.getElementById('geo').value.split(',').map(g => g.trim()).filter(Boolean),
+        first_current_company: document.getElementById('first-company').checked
+      }
+    };
+  }
+
+  // =============== Convites da lista revisada ===============
+  // a lista é o que o usuário marcou na tabela de resultados; a busca em si
+  // nunca convida
+  const inviteBox = document.getElementById('inviteBox');
+  const selPage = document.getElementById('selPage');
+  const inviteBtn = document.getElementById('inviteBtn');
+  const notePreview = document.getElementById('notePreview');
+
+  function updateSelection() {
+    document.getElementById('inviteCount').textContent = '(' + selected.size + ' perfis marcados)';
+    inviteBtn.disabled = selected.size === 0;
+  }
+
+  resultsBody.addEventListener('change', (e) => {
+    const url = e.target.dataset && e.target.dataset.sel;
+    if (!url) return;
+    if (e.target.checked) selected.add(url); else selected.delete(url);
+    updateSelection();
+  });
+
+  selPage.addEventListener('change', () => {
+    for (const c of resultsBody.querySelectorAll('input[data-sel]')) {
+      c.checked = selPage.checked;
+      if (c.checked) selected.add(c.dataset.sel); else selected.delete(c.dataset.sel);
+    }
+    updateSelection();
+  });
+
+  document.getElementById('clearSelBtn').addEventListener('click', () => {
+    selected.clear();
+    for (const c of resultsBody.querySelectorAll('input[data-sel]')) c.checked = false;
+    selPage.checked = false;
+    updateSelection();
+  });
+
+  inviteBtn.addEventListener('click', () => {
+    if (!resultsJobId || !selected.size) return;
+    if (!confirm('Convidar ' + selected.size + ' perfis marcados?')) return;
+    const p = searchParams();
+    startJob({
+      invite_from: resultsJobId,
+      invite_urls: [...selected],
+      invite_note: document.getElementById('invite-note').value.trim(),
+      account:     p.account,
+      headless:    p.headless,
+      dry_run:     p.dry_run
+    }, true);
+  });
+
+  // prévia com os primeiros perfis do job aberto na tabela (ou um exemplo)
+  document.getElementById('notePreviewBtn').addEventListener('click', async () => {
+    const resp = await fetch('/api/v1/invites/preview', {
+      method: 'POST',
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: JSON.stringify({template: document.getElementById('invite-note').value, job_id: resultsJobId || ''})
+    });
+    if (!resp.ok) {
+      notePreview.innerHTML = '<li class="text-red-700 whitespace-pre-wrap">'+escapeHTML(await apiError(resp))+'</li>';
+      return;
+    }
+    const p = await resp.json();
+    notePreview.innerHTML = p.items.map(it =>
+      '<li class="border rounded-md p-2'+(it.error ? ' border-red-300' : '')+'">'+
+        '<div class="flex justify-between text-gray-500"><b>'+escapeHTML(it.name)+'</b><span>'+it.length+'/'+p.max_len+'</span></div>'+
+        '<div class="whitespace-pre-wrap">'+escapeHTML(it.note)+'</div>'+
+        (it.error ? '<div class="text-red-700">'+escapeHTML(it.error)+'</div>' : '')+
+      '</li>').join('');
+  });
+
+  // =============== Histórico de convites ===============
+  const invitesList = document.getElementById('invitesList');
+  const inviteLabels = {sent: 'enviado', pending: 'já pendente', connected: 'já conexão', name_mismatch: 'nome diferente',
+    no_button: 'sem botão', note_error: 'nota inválida', dry_run: 'simulado', weekly_limit: 'limite semanal',
+    accepted: 'aceito', withdrawn: 'retirado', failed: 'falhou'};
+
+  async function loadInvites() {
+    const resp = await fetch('/api/v1/invites?limit=20');
+    if (!resp.ok) return;
+    const page = await resp.json();
+    document.getElementById('noInvites').classList.toggle('hidden', page.total > 0);
+    invitesList.innerHTML = page.items.map(e =>
+      '<li class="py-1 flex justify-between gap-2">'+
+        '<span><a href="'+encodeURI(e.url)+'" target="_blank" class="text-primary underline">'+escapeHTML(e.name || e.url)+'</a>'+
+        (e.note ? ' <span class="text-xs text-gray-500" title="'+escapeHTML(e.note)+'">✉️</span>' : '')+'</span>'+
+        '<span class="text-xs text-gray-500 whitespace-nowrap">'+escapeHTML(inviteLabels[e.status] || e.status)+' • '+
+          escapeHTML(e.account)+' • '+escapeHTML(new Date(e.at).toLocaleString())+'</span>'+
+      '</li>').join('') +
+      (page.total > page.items.length ? '<li class="py-1 text-xs text-gray-500">… e mais '+(page.total - page.items.length)+' no CSV</li>' : '');
+  }
+
+  function showSuppression(sup) {
+    document.getElementById('suppressText').value = sup.text;
+    document.getElementById('suppressInfo').textContent = sup.urls.length + ' perfis, ' + sup.companies.length + ' empresas';
+  }
+
+  async function loadSuppression() {
+    const resp = await fetch('/api/v1/invites/suppression');
+    if (resp.ok) showSuppression(await resp.json());
+  }
+
+  document.getElementById('saveSuppressBtn').addEventListener('click', async () => {
+    const resp = await fetch('/api/v1/invites/suppression', {
+      method: 'PUT',
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: JSON.stringify({text: document.getElementById('suppressText').value})
+    });
+    if (!resp.ok) { alert(await apiError(resp)); return; }
+    showSuppression(await resp.json());
+  });
+
+  // =============== Buscas salvas ===============
+  const searchesList = document.getElementById('searchesList');
+  const noSearches = document.getElementById('noSearches');
+
+  const runLabels = {schedule: 'agendada', missed: 'atrasada', manual: 'manual'};
+
+  async function loadSearches() {
+    const resp = await fetch('/api/v1/searches');
+    if (!resp.ok) return;
+    const list = (await resp.json()).items;
+    searchesList.innerHTML = '';
+    noSearches.classList.toggle('hidden', list.length > 0);
+    for (const s of list) {
+      const li = document.createElement('li');
+      li.className = 'py-2 space-y-1';
+      const next = s.enabled && s.next_run ? 'próxima: ' + new Date(s.next_run).toLocaleString() : 'desativada';
+      const runs = s.runs.map(r =>
+        '<li>'+escapeHTML(new Date(r.at).toLocaleString())+' • '+escapeHTML(runLabels[r.trigger] || r.trigger)+' • '+
+        (r.error ? '<span class="text-red-700">'+escapeHTML(r.error)+'</span>' : escapeHTML(r.status || '—')+' • '+r.profiles+' perfis')+
+        (r.export_url ? ' <button type="button" data-job="'+escapeHTML(r.job_id)+'" class="text-primary underline">resultados</button>'+
+          ' <a href="'+encodeURI(r.export_url)+'" class="text-primary underline">CSV</a>' : '')+'</li>').join('');
+      li.innerHTML =
+        '<div class="flex items-center justify-between">'+
+          '<span><b>'+escapeHTML(s.name)+'</b> <span class="text-xs text-gray-500">• '+escapeHTML(s.query)+' • <code>'+escapeHTML(s.cron)+'</code> • '+escapeHTML(next)+
+            (s.dry_run ? ' • dry-run' : '')+'</span></span>'+
+          '<span class="space-x-2 whitespace-nowrap">'+
+            '<label class="text-xs"><input type="checkbox" data-toggle="'+escapeHTML(s.id)+'"'+(s.enabled ? ' checked' : '')+'> ativa</label>'+
+            '<button type="button" data-run="'+escapeHTML(s.id)+'" class="text-primary underline">rodar agora</button>'+
+            '<button type="button" data-delsearch="'+escapeHTML(s.id)+'" class="text-red-700 underline">excluir</button>'+
+          '</span>'+
+        '</div>'+
+        (runs ? '<ul class="text-xs text-gray-600 pl-3">'+runs+'</ul>' : '');
+      searchesList.appendChild(li);
+    }
+  }
+
+  async function searchRequest(method, path, body) {
+    const resp = await fetch('/api/v1/searches' + path, {
+      method,
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: body ? JSON.stringify(body) : undefined
+    });
+    if (!resp.ok) { alert(await apiError(resp)); return false; }
+    return true;
+  }
+
+  document.getElementById('saveSearchBtn').addEventListener('click', async () => {
+    const body = Object.assign(searchParams(), {
+      name:           document.getElementById('ss-name').value.trim(),
+      cron:           document.getElementById('ss-cron').value.trim(),
+      jitter_minutes: parseInt(document.getElementById('ss-jitter').value || '0', 10),
+      missed:         document.getElementById('ss-missed').value
+    });
+    if (await searchRequest('POST', '', body)) {
+      document.getElementById('ss-name').value = '';
+      loadSearches();
+    }
+  });
+
+  searchesList.addEventListener('click', async (e) => {
+    const d = e.target.dataset || {};
+    if (d.job) showJobResults(d.job);
+    if (d.run && await searchRequest('POST', '/' + encodeURIComponent(d.run) + '/run')) {
+      loadSearches();
+      loadJobs();
+    }
+    if (d.delsearch && confirm('Excluir a busca salva? Os jobs já feitos ficam.') &&
+        await searchRequest('DELETE', '/' + encodeURIComponent(d.delsearch))) {
+      loadSearches();
+    }
+  });
+
+  searchesList.addEventListener('change', async (e) => {
+    const id = e.target.dataset && e.target.dataset.toggle;
+    if (id) {
+      await searchRequest('PUT', '/' + encodeURIComponent(id), {enabled: e.target.checked});
+      loadSearches();
+    }
+  });
+
+  // =============== Notificações ===============
+  const hooksList = document.getElementById('hooksList');
+  const noHooks = document.getElementById('noHooks');
+  const hookType = document.getElementById('hook-type');
+  const hookTarget = document.getElementById('hook-target');
+
+  const deliveryLabels = {pending: 'pendente', delivered: 'entregue', failed: 'falhou'};
+
+  async function hookRequest(method, path, body) {
+    const resp = await fetch('/api/v1/notifications' + path, {
+      method,
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: body ? JSON.stringify(body) : undefined
+    });
+    if (!resp.ok) { alert(await apiError(resp)); return null; }
+    return resp.status === 204 ? {} : resp.json();
+  }
+
+  async function loadHooks() {
+    const resp = await fetch('/api/v1/notifications');
+    if (!resp.ok) return;
+    const list = (await resp.json()).items;
+    hooksList.innerHTML = '';
+    noHooks.classList.toggle('hidden', list.length > 0);
+    for (const h of list) {
+      const li = document.createElement('li');
+      li.className = 'py-2 space-y-1';
+      li.innerHTML =
+        '<div class="flex items-center justify-between">'+
+          '<span><b>'+escapeHTML(h.type)+'</b> <span class="text-xs text-gray-500">• '+escapeHTML(h.url || h.email)+' • '+escapeHTML(h.events.join(', '))+'</span></span>'+
+          '<span class="space-x-2 whitespace-nowrap">'+
+            '<label class="text-xs"><input type="checkbox" data-hooktoggle="'+escapeHTML(h.id)+'"'+(h.enabled ? ' checked' : '')+'> ativo</label>'+
+            '<button type="button" data-hooktest="'+escapeHTML(h.id)+'" class="text-primary underline">testar</button>'+
+            '<button type="button" data-hooklog="'+escapeHTML(h.id)+'" class="text-primary underline">entregas</button>'+
+            '<button type="button" data-hookdel="'+escapeHTML(h.id)+'" class="text-red-700 underline">excluir</button>'+
+          '</span>'+
+        '</div>'+
+        '<ul class="hidden text-xs text-gray-600 pl-3" data-log="'+escapeHTML(h.id)+'"></ul>';
+      hooksList.appendChild(li);
+    }
+  }
+
+  async function showDeliveries(id) {
+    const box = hooksList.querySelector('[data-log="'+CSS.escape(id)+'"]');
+    const list = await hookRequest('GET', '/' + encodeURIComponent(id) + '/deliveries');
+    if (!box || !list) return;
+    box.innerHTML = list.items.length ? list.items.map(d => {
+      const last = d.attempts[d.attempts.length - 1];
+      return '<li>'+escapeHTML(new Date(d.created_at).toLocaleString())+' • '+escapeHTML(d.event)+' • '+
+        escapeHTML(deliveryLabels[d.status] || d.status)+' • '+d.attempts.length+' tentativa(s)'+
+        (last && last.error ? ' • <span class="text-red-700">'+escapeHTML(last.error)+'</span>' : '')+
+        (d.status === 'pending' && d.next_attempt ? ' • próxima: '+escapeHTML(new Date(d.next_attempt).toLocaleTimeString()) : '')+'</li>';
+    }).join('') : '<li>Nenhuma entrega ainda.</li>';
+    box.classList.remove('hidden');
+  }
+
+  hookType.addEventListener('change', () => {
+    hookTarget.placeholder = hookType.value === 'email' ? 'voce@exemplo.com' : 'https://exemplo.com/webhook';
+  });
+
+  document.getElementById('addHookBtn').addEventListener('click', async () => {
+    const body = {
+      type: hookType.value,
+      events: [...document.querySelectorAll('input[name="hook-event"]:checked')].map(c => c.value)
+    };
+    body[hookType.value === 'email' ? 'email' : 'url'] = hookTarget.value.trim();
+    const h = await hookRequest('POST', '', body);
+    if (!h) return;
+    hookTarget.value = '';
+    document.getElementById('hookSecretValue').textContent = h.secret || '';
+    document.getElementById('hookSecret').classList.toggle('hidden', !h.secret);
+    loadHooks();
+  });
+
+  hooksList.addEventListener('click', async (e) => {
+    const d = e.target.dataset || {};
+    if (d.hooktest && await hookRequest('POST', '/' + encodeURIComponent(d.hooktest) + '/test')) {
+      setTimeout(() => showDeliveries(d.hooktest), 1500);
+    }
+    if (d.hooklog) showDeliveries(d.hooklog);
+    if (d.hookdel && confirm('Excluir o destino e o log de entregas?') &&
+        await hookRequest('DELETE', '/' + encodeURIComponent(d.hookdel))) {
+      loadHooks();
+    }
+  });
+
+  hooksList.addEventListener('change', async (e) => {
+    const id = e.target.dataset && e.target.dataset.hooktoggle;
+    if (id) {
+      await hookRequest('PUT', '/' + encodeURIComponent(id), {enabled: e.target.checked});
+      loadHooks();
+    }
+  });
+
+  runBtn.addEventListener('click', () => startJob(searchParams(), false));
+
+  // startJob cria o job e acompanha os eventos até o fim. keepResults mantém
+  // a tabela aberta (job de convites, que não gera resultados novos).
+  async function startJob(payload, keepResults) {
+    csvLink.classList.add('hidden');
+    startedAt.textContent = '—';
+    endedAt.textContent = '—';
+    progressBar.style.width = '0%';
+    progressLabel.textContent = '0%';
+    logBox.textContent = 'Aguardando logs…';
+    if (!keepResults) {
+      resultsJobId = null;
+      resultsFilters.classList.add('hidden');
+      renderResults(null);
+    }
+
+    setStatus('Iniciando', 'bg-primary/10 text-primary');
+
+    const stopProgress = fakeProgressStart();
+
+    const created = await fetch('/api/v1/jobs', {
+      method: 'POST',
+      headers: {'Content-Type':'application/json', 'X-CSRF-Token': csrfToken},
+      body: JSON.stringify(payload)
+    });
+    if (!created.ok) {
+      setStatus('Erro', 'bg-red-100 text-red-700');
+      appendLog('Erro: ' + (await apiError(created)), 'error');
+      stopProgress();
+      return;
+    }
+    currentJobId = (await created.json()).id;
+    cancelBtn.classList.remove('hidden');
+    loadJobs();
+
+    const resp = await fetch('/jobs/' + encodeURIComponent(currentJobId) + '/events');
+    if (!resp.ok) {
+      setStatus('Erro HTTP', 'bg-red-100 text-red-700');
+      appendLog('Erro: ' + resp.status + ' ' + resp.statusText, 'error');
+      stopProgress();
+      return;
+    }
+
+    const reader = resp.body.getReader();
+    const decoder = new TextDecoder();
+    let buffer = '';
+    let finalData = null;
+
+    startedAt.textContent = new Date().toLocaleTimeString();
+
+    while (true) {
+      const {value, done} = await reader.read();
+      if (done) break;
+      buffer += decoder.decode(value, {stream:true});
+      const parts = buffer.split('\n');
+      buffer = parts.pop();
+
+      for (const line of parts) {
+        if (!line) continue;
+        try {
+          const ev = JSON.parse(line);
+          if (ev.type === 'log') {
+            appendLog(ev.msg, ev.level, ev.attrs, ev.time);
+          } else if (ev.type === 'challenge') {
+            challengeKind.textContent = ev.data.kind || 'desafio';
+            challengeImg.src = 'data:image/jpeg;base64,' + ev.data.image;
+            challengeBox.classList.remove('hidden');
+            setStatus('Aguardando verificação', 'bg-yellow-100 text-yellow-700');
+          } else if (ev.type === 'challenge_end') {
+            challengeBox.classList.add('hidden');
+            setStatus('Executando', 'bg-primary/10 text-primary');
+          } else if (ev.type === 'done') {
+            finalData = ev.data;
+          }
+        } catch {
+          appendLog(line);
+        }
+      }
+    }
+
+    stopProgress();
+    challengeBox.classList.add('hidden');
+    cancelBtn.classList.add('hidden');
+    currentJobId = null;
+    endedAt.textContent = new Date().toLocaleTimeString();
+
+    if (finalData) {
+      if (finalData.csv_path && finalData.job_id) {
+        csvLink.href = '/api/v1/exports/' + encodeURIComponent(finalData.job_id);
+        csvLink.classList.remove('hidden');
+      }
+      if (finalData.csv_path && finalData.job_id) {
+        showJobResults(finalData.job_id);
+      }
+      if (finalData.status === 'cancelled') {
+        setStatus('Cancelado', 'bg-gray-100 text-gray-600');
+        appendLog((finalData.message || 'Cancelado') + '. Resultados parciais mantidos.', 'warn');
+      } else if (finalData.ok) {
+        setStatus('Concluído', 'bg-green-100 text-green-700');
+        appendLog('Finalizado com sucesso.');
+      } else {
+        setStatus('Concluído (com avisos)', 'bg-yellow-100 text-yellow-700');
+        appendLog('Execução terminou com avisos/erro. Verifique logs e resultados exibidos.', 'warn');
+      }
+    } else {
+      setStatus('Falhou', 'bg-red-100 text-red-700');
+      appendLog('Erro ao executar. Veja logs acima.', 'error');
+    }
+    loadJobs();
+    loadAccounts();
+    loadSearches();
+    loadInvites();
+  }
+
+  loadAccounts();
+  loadJobs();
+  loadSearches();
+  loadInvites();
+  loadSuppression();
+  loadHooks();
+})();
+</script>
+</body></html>`))
+
+// =================== SERVER ===================
+
+type server struct {
+	dataDir  string
+	cfg      config.Config
+	cfgPath  string // repassado ao crawler
+	auth     *auth.Authenticator
+	jobs     *jobs.Store
+	vault    *vault.Vault
+	accounts *accounts.Registry
+	sched    *scheduler.Scheduler
+	notifier *notify.Notifier
+	metrics  *metrics.Metrics
+
+	hubsMu sync.Mutex
+	hubs   map[string]*jobHub
+
+	// keys guarda as chaves (results.Key) dos perfis do CSV de cada job já
+	// lido por newProfiles: o CSV de um job terminado não muda.
+	keysMu sync.Mutex
+	keys   map[string]map[string]bool // id do job -> chaves
+}
+
+// serveCmd sobe a UI, a API e o agendador; cada job roda "golinkedin crawl"
+// num processo filho.
+func serveCmd(args []string) error {
+	fs := newFlags("serve", "[flags]", "Sobe a UI web, a API REST e o agendador de jobs.")
+	cfg, cfgPath, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.StringVar(&cfg.LogFormat, "log-format", cfg.LogFormat, "Formato do log no stderr: text ou json (env LOG_FORMAT)")
+	fs.StringVar(&cfg.Server.Addr, "addr", cfg.Server.Addr, "Endereço HTTP (env GOLINKEDIN_ADDR)")
+	fs.StringVar(&cfg.Server.DataDir, "data-dir", cfg.Server.DataDir, "Pasta de dados (env DATA_DIR)")
+	fs.Parse(args)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("configuração inválida: %w", err)
+	}
+	if err := logging.Setup(cfg.LogFormat); err != nil {
+		return err
+	}
+
+	dataDir := cfg.Server.DataDir
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		logging.Fatal("criando pasta de dados", "err", err)
+	}
+
+	authCfg := auth.Config{UsersFile: cfg.Server.UsersFile}
+	if authCfg.UsersFile == "" {
+		authCfg.UsersFile = filepath.Join(dataDir, "users.json")
+	}
+	if cfg.OIDC.Issuer != "" {
+		authCfg.OIDC = &auth.OIDCConfig{
+			Issuer:        cfg.OIDC.Issuer,
+			ClientID:      cfg.OIDC.ClientID,
+			ClientSecret:  cfg.OIDC.ClientSecret,
+			RedirectURL:   cfg.OIDC.RedirectURL,
+			UsernameClaim: cfg.OIDC.UsernameClaim,
+		}
+	}
+	a, err := auth.New(context.Background(), authCfg)
+	if err != nil {
+		logging.Fatal("auth", "err", err)
+	}
+	js, err := jobs.Open(filepath.Join(dataDir, "jobs.json"))
+	if err != nil {
+		logging.Fatal("abrindo jobs", "err", err)
+	}
+	key, err := vault.LoadKey(cfg.Vault.MasterKey, cfg.Vault.MasterKeyFile)
+	if err != nil {
+		logging.Fatal("cofre", "err", err)
+	}
+	v, err := vault.Open(filepath.Join(dataDir, "vault.json"), key)
+	if err != nil {
+		logging.Fatal("cofre", "err", err)
+	}
+	ar, err := accounts.Open(filepath.Join(dataDir, "accounts.json"))
+	if err != nil {
+		logging.Fatal("abrindo contas", "err", err)
+	}
+	st, err := settings.Open(filepath.Join(dataDir, "settings.json"))
+	if err != nil {
+		logging.Fatal("abrindo configurações", "err", err)
+	}
+	ss, err := searches.Open(filepath.Join(dataDir, "searches.json"))
+	if err != nil {
+		logging.Fatal("abrindo buscas salvas", "err", err)
+	}
+	ns, err := notify.Open(filepath.Join(dataDir, "notifications.json"))
+	if err != nil {
+		logging.Fatal("abrindo notificações", "err", err)
+	}
+	rules, err := classify.Load(cfg.Classify.Rules)
+	if err != nil {
+		logging.Fatal("regras de classificação", "err", err)
+	}
+	// email só com smtp.addr; sem ele os destinos de email ficam indisponíveis
+	var mailer notify.Mailer
+	if cfg.SMTP.Addr != "" {
+		mailer = &notify.SMTP{
+			Addr:     cfg.SMTP.Addr,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		}
+	}
+	notifier := notify.New(ns, mailer, cfg.Server.PublicURL)
+	s := &server{dataDir: dataDir, cfg: cfg, cfgPath: cfgPath, auth: a, jobs: js, vault: v, accounts: ar, notifier: notifier, hubs: map[string]*jobHub{}, keys: map[string]map[string]bool{}}
+	// convites restantes das contas = o que o crawler aceitaria enviar: mesmo
+	// histórico (o do usuário) e mesmas cotas
+	ar.UseLedger(quotaOf(cfg), func(owner string) (*ledger.Checker, error) {
+		return ledger.Load(filepath.Join(s.userDir(owner), api.LedgerFile), "")
+	})
+	s.metrics = metrics.New(js.CountByStatus)
+	s.sched = scheduler.New(js, ar, s.runJob, s.jobDone)
+
+	// SIGINT/SIGTERM: para de aceitar jobs, cancela os que estão rodando (eles
+	// gravam o parcial e fecham o Chrome) e só então sai.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	schedDone := make(chan struct{})
+	go func() {
+		s.sched.Run(ctx)
+		close(schedDone)
+	}()
+
+	apiSrv := &api.Server{
+		Jobs:            js,
+		Accounts:        ar,
+		Vault:           v,
+		Settings:        st,
+		Searches:        ss,
+		Classify:        rules,
+		UserDir:         s.userDir,
+		Require:         func(h http.Handler) http.Handler { return a.RequireWith(h, api.Fail) },
+		JobCreated:      s.jobCreated,
+		AccountsChanged: s.sched.Wake,
+		CancelJob:       s.sched.Cancel,
+
+		Notifications:    ns,
+		TestNotification: notifier.Test,
+		EmailEnabled:     notifier.EmailEnabled(),
+	}
+	go searches.NewRunner(ss, apiSrv.EnqueueSearch).Run(ctx)
+	go notifier.Run(ctx)
+
+	mux := http.NewServeMux()
+	a.Routes(mux)
+	apiSrv.Routes(mux)
+	mux.Handle("/", a.Require(http.HandlerFunc(s.handleIndex)))
+	mux.Handle("/jobs/{id}/events", a.Require(http.HandlerFunc(s.handleJobEvents)))
+	mux.Handle("POST /jobs/{id}/challenge", a.Require(http.HandlerFunc(s.handleChallenge)))
+	// fora do login da UI (Prometheus não tem sessão); server.metrics_token exige Bearer
+	mux.Handle("GET /metrics", s.metrics.Handler(cfg.Server.MetricsToken))
+	// também públicos: são usados pelo healthcheck do container
+	ready := &health.Checker{
+		ChromePath:    cfg.Browser.ChromePath,
+		ChromeTimeout: cfg.Timeouts.ReadyChrome,
+		ChromeTTL:     cfg.Timeouts.ReadyTTL,
+		DataDir:       dataDir,
+		MinFreeBytes:  uint64(cfg.Server.MinFreeMB) << 20,
+		Stores: []string{
+			authCfg.UsersFile,
+			filepath.Join(dataDir, "jobs.json"),
+			filepath.Join(dataDir, "vault.json"),
+			filepath.Join(dataDir, "accounts.json"),
+			filepath.Join(dataDir, "settings.json"),
+			filepath.Join(dataDir, "searches.json"),
+			filepath.Join(dataDir, "notifications.json"),
+		},
+	}
+	mux.HandleFunc("GET /healthz", health.Healthz)
+	mux.HandleFunc("GET /readyz", ready.Readyz)
+
+	srv := &http.Server{Addr: cfg.Server.Addr, Handler: mux}
+	go func() {
+		slog.Info("servidor rodando", "addr", "http://localhost"+srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("servidor HTTP", "err", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop() // um segundo Ctrl-C mata na hora
+	slog.Info("encerrando: aguardando jobs em execução gravarem resultados parciais")
+	<-schedDone
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		_ = srv.Close()
+	}
+	slog.Info("servidor encerrado")
+	return nil
+}
+
+// hashPasswordCmd lê uma senha do stdin e imprime o hash bcrypt para o arquivo de usuários.
+func hashPasswordCmd(args []string) error {
+	fs := newFlags("hash-password", "< senha", "Lê uma senha do stdin e imprime o hash bcrypt para o arquivo de usuários.")
+	fs.Parse(args)
+	fmt.Fprint(os.Stderr, "Senha: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return fmt.Errorf("lendo senha: %w", err)
+	}
+	h, err := auth.HashPassword(strings.TrimRight(line, "\r\n"))
+	if err != nil {
+		return fmt.Errorf("gerando hash: %w", err)
+	}
+	fmt.Println(h)
+	return nil
+}
+
+// healthcheckCmd consulta o /readyz do servidor local (porta de server.addr)
+// e falha se não vier 200; é o healthcheck do container (a imagem não tem curl).
+func healthcheckCmd(args []string) error {
+	fs := newFlags("healthcheck", "[flags] [url]", "Consulta o /readyz do servidor local e sai com 0 (pronto) ou 1.")
+	cfg, _, err := loadConfig(fs, args)
+	if err != nil {
+		return err
+	}
+	fs.Parse(args)
+	url := "http://127.0.0.1:8080/readyz"
+	if _, port, err := net.SplitHostPort(cfg.Server.Addr); err == nil {
+		url = "http://127.0.0.1:" + port + "/readyz"
+	}
+	if fs.NArg() > 0 {
+		url = fs.Arg(0)
+	}
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(os.Stdout, resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s respondeu %s", url, resp.Status)
+	}
+	return nil
+}
+
+// userDir é a pasta de saída exclusiva de cada usuário da UI
+// (data/users/<auth.DirName>).
+func (s *server) userDir(owner string) string {
+	return filepath.Join(s.dataDir, "users", auth.DirName(owner))
+}
+
+func (s *server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	sess := auth.FromContext(r.Context())
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = pageTmpl.Execute(w, map[string]string{
+		"User": sess.Username,
+		"Name": sess.Name,
+		"CSRF": sess.CSRF,
+	})
+}
+
+func writeEvent(w http.ResponseWriter, ev streamEvent) {
+	b, _ := json.Marshal(ev)
+	_, _ = w.Write(b)
+	_, _ = w.Write([]byte("\n"))
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// =================== JOBS (fila + eventos) ===================
+
+const (
+	// hubMaxEvents é quantos eventos um job guarda para quem reconectar; os
+	// mais antigos saem primeiro.
+	hubMaxEvents = 2000
+	// hubLinger é quanto o stream de um job terminado fica em memória para
+	// quem reconectar; depois, /jobs/{id}/events só devolve o estado final.
+	hubLinger = 10 * time.Minute
+)
+
+// jobHub guarda os eventos de um job e repassa para quem estiver ouvindo
+// (a aba que disparou o run ou quem reconectar em /jobs/{id}/events).
+// Também segura o stdin do crawler para os comandos de desafio.
+type jobHub struct {
+	mu        sync.Mutex
+	events    []streamEvent // até hubMaxEvents
+	dropped   int           // eventos descartados do início
+	challenge *streamEvent  // último screenshot; não entra no histórico
+	subs      map[chan streamEvent]struct{}
+	closed    bool
+	input     io.Writer
+}
+
+func (h *jobHub) publish(ev streamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	switch ev.Type {
+	case events.TypeChallenge:
+		h.challenge = &ev
+	case events.TypeChallengeEnd:
+		h.challenge = nil
+		h.keep(ev)
+	default:
+		h.keep(ev)
+	}
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default: // ouvinte lento perde o evento, mas não trava o job
+		}
+	}
+}
+
+// keep guarda ev para o replay. Cheio, descarta o quarto mais antigo de uma
+// vez (em vez de um a um) para não copiar a fatia a cada evento.
+func (h *jobHub) keep(ev streamEvent) {
+	if len(h.events) >= hubMaxEvents {
+		n := copy(h.events, h.events[hubMaxEvents/4:])
+		clear(h.events[n:])
+		h.dropped += len(h.events) - n
+		h.events = h.events[:n]
+	}
+	h.events = append(h.events, ev)
+}
+
+func (h *jobHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	h.challenge = nil
+	h.input = nil
+	for ch := range h.subs {
+		close(ch)
+	}
+	h.subs = nil
+}
+
+func (h *jobHub) setInput(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.input = w
+}
+
+// send repassa um comando da UI ao crawler (uma linha JSON no stdin).
+func (h *jobHub) send(c events.Command) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.input == nil {
+		return errors.New("job não está aguardando comandos")
+	}
+	_, err = h.input.Write(append(b, '\n'))
+	return err
+}
+
+// subscribe devolve os eventos já emitidos e um canal com os próximos
+// (fechado quando o job termina).
+func (h *jobHub) subscribe() ([]streamEvent, chan streamEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var replay []streamEvent
+	if h.dropped > 0 {
+		replay = append(replay, streamEvent{Type: "log", Level: "warn", Time: h.events[0].Time,
+			Msg: fmt.Sprintf("%d eventos anteriores omitidos", h.dropped)})
+	}
+	replay = append(replay, h.events...)
+	if h.challenge != nil {
+		replay = append(replay, *h.challenge)
+	}
+	ch := make(chan streamEvent, 256)
+	if h.closed {
+		close(ch)
+		return replay, ch, func() {}
+	}
+	h.subs[ch] = struct{}{}
+	return replay, ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// hub devolve o stream do job id, criando se preciso. Só quem produz
+// eventos (o job na fila ou rodando) cria; quem só lê usa liveHub.
+func (s *server) hub(id string) *jobHub {
+	s.hubsMu.Lock()
+	defer s.hubsMu.Unlock()
+	h, ok := s.hubs[id]
+	if !ok {
+		h = &jobHub{subs: map[chan streamEvent]struct{}{}}
+		s.hubs[id] = h
+	}
+	return h
+}
+
+// liveHub devolve o stream do job id, se ainda estiver em memória.
+func (s *server) liveHub(id string) (*jobHub, bool) {
+	s.hubsMu.Lock()
+	defer s.hubsMu.Unlock()
+	h, ok := s.hubs[id]
+	return h, ok
+}
+
+// dropHub tira o stream h do job id da memória (se ainda for o mesmo).
+func (s *server) dropHub(id string, h *jobHub) {
+	s.hubsMu.Lock()
+	defer s.hubsMu.Unlock()
+	if s.hubs[id] == h {
+		delete(s.hubs, id)
+	}
+}
+
+// logJob registra uma mensagem do servidor sobre o job: vai para o stream
+// (UI) e para o log do servidor. args são pares chave/valor do slog.
+func (s *server) logJob(id string, level slog.Level, msg string, args ...any) {
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.Add(args...)
+	attrs := map[string]any{}
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Value.Kind() == slog.KindDuration {
+			attrs[a.Key] = a.Value.Duration().Round(time.Millisecond).String()
+		} else {
+			attrs[a.Key] = a.Value.Any()
+		}
+		return true
+	})
+	s.publishLog(id, logging.Record{Time: r.Time, Level: logging.Level(level.String()), Msg: msg, Attrs: attrs})
+	slog.Log(context.Background(), level, msg, append([]any{logging.KeyJobID, id}, args...)...)
+}
+
+// relayLog repassa uma linha do crawler. Registros JSON (--log-format=json)
+// mantêm nível e campos; o resto (panic, saída do Chrome) vira info.
+func (s *server) relayLog(id, line string) {
+	rec, ok := logging.Parse(line)
+	if !ok {
+		rec = logging.Record{Time: time.Now(), Level: "info", Msg: line}
+	}
+	s.publishLog(id, rec)
+
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(rec.Level))
+	args := []any{logging.KeyJobID, id}
+	for _, k := range slices.Sorted(maps.Keys(rec.Attrs)) {
+		if k != logging.KeyJobID {
+			args = append(args, k, rec.Attrs[k])
+		}
+	}
+	slog.Log(context.Background(), level, rec.Msg, args...)
+}
+
+func (s *server) publishLog(id string, rec logging.Record) {
+	if len(rec.Attrs) == 0 {
+		rec.Attrs = nil
+	}
+	s.hub(id).publish(streamEvent{Type: "log", Msg: rec.Msg, Level: rec.Level, Time: rec.Time, Attrs: rec.Attrs})
+}
+
+// jobCreated é chamado pela API ao enfileirar: abre o stream do job (que
+// pode ser acompanhado em /jobs/{id}/events) e acorda o agendador.
+func (s *server) jobCreated(j jobs.Job) {
+	s.logJob(j.ID, slog.LevelInfo, "job na fila", logging.KeyQuery, j.Query)
+	s.sched.Wake()
+}
+
+// handleJobEvents reconecta ao stream de um job do usuário.
+func (s *server) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	sess := auth.FromContext(r.Context())
+	j, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok || j.Owner != sess.Username {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson; charset=utf-8")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	h, live := s.liveHub(j.ID)
+	if !live {
+		if j.Status.Finished() {
+			// terminou há mais de hubLinger (ou antes do último restart): só o estado final
+			writeEvent(w, streamEvent{Type: "done", Data: s.jobResponse(j)})
+			return
+		}
+		h = s.hub(j.ID) // na fila desde antes do restart: o stream começa quando rodar
+	}
+	s.streamJob(w, r, h)
+}
+
+func (s *server) streamJob(w http.ResponseWriter, r *http.Request, h *jobHub) {
+	replay, ch, unsubscribe := h.subscribe()
+	defer unsubscribe()
+	for _, ev := range replay {
+		writeEvent(w, ev)
+	}
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			writeEvent(w, ev)
+		}
+	}
+}
+
+// handleChallenge recebe um comando da UI (clique no screenshot ou código
+// 2FA) e repassa ao crawler do job.
+func (s *server) handleChallenge(w http.ResponseWriter, r *http.Request) {
+	sess := auth.FromContext(r.Context())
+	j, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok || j.Owner != sess.Username {
+		http.NotFound(w, r)
+		return
+	}
+	var c events.Command
+	if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+		http.Error(w, "payload inválido", http.StatusBadRequest)
+		return
+	}
+	switch c.Type {
+	case events.CmdCode:
+		if strings.TrimSpace(c.Code) == "" {
+			http.Error(w, "código vazio", http.StatusBadRequest)
+			return
+		}
+	case events.CmdClick:
+		if c.X < 0 || c.X > 1 || c.Y < 0 || c.Y > 1 {
+			http.Error(w, "coordenadas fora do screenshot", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "comando desconhecido", http.StatusBadRequest)
+		return
+	}
+	h, ok := s.liveHub(j.ID)
+	if !ok {
+		http.Error(w, "job não está aguardando comandos", http.StatusConflict)
+		return
+	}
+	if err := h.send(c); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// crawlerStopGrace é quanto o crawler tem para fechar a página atual depois do SIGTERM.
+const crawlerStopGrace = 90 * time.Second
+
+// runJob é o scheduler.RunFunc: roda o crawler com a conta escolhida. Uma
+// busca vira "golinkedin crawl"; um job de convites, "golinkedin invites send"
+// com a lista revisada.
+func (s *server) runJob(ctx context.Context, job jobs.Job, acct accounts.Account, budget scheduler.Budget) scheduler.Result {
+	logf := func(level slog.Level, msg string, args ...any) { s.logJob(job.ID, level, msg, args...) }
+
+	cred, err := s.vault.Get(job.Owner, acct.Credential)
+	if err != nil {
+		logf(slog.LevelError, "credencial da conta indisponível", "account", acct.Alias, "credential", acct.Credential, "err", err)
+		return scheduler.Result{Err: err}
+	}
+	credLine, _ := json.Marshal(cred)
+
+	if job.IsInvite() {
+		logf(slog.LevelInfo, "iniciando convites", "account", acct.Alias, "from_job", job.InviteFrom, "profiles", len(job.InviteURLs), "max_invites", budget.Invites)
+	} else {
+		logf(slog.LevelInfo, "iniciando crawler", logging.KeyQuery, job.Query, "account", acct.Alias, "max_pages", budget.Pages)
+	}
+
+	// o crawler é o próprio binário ("golinkedin crawl"); server.crawler_bin
+	// (CRAWLER_BIN) aponta outro golinkedin, se preciso
+	crawlerBin := s.cfg.Server.CrawlerBin
+	if crawlerBin == "" {
+		if crawlerBin, err = os.Executable(); err != nil {
+			logf(slog.LevelError, "localizando o executável do crawler", "err", err)
+			return scheduler.Result{Err: err}
+		}
+	}
+
+	args := []string{
+		"--credentials-stdin",
+		"--interactive",
+		"--out-dir", job.Dir,
+		"--user-data-dir", acct.SessionDir,
+		"--metrics",
+		"--log-format=json",
+		"--job-id", job.ID,
+		// explícito: o job vence o que a configuração do crawler disser
+		"--headless=" + strconv.FormatBool(job.Headless),
+	}
+	if s.cfgPath != "" {
+		args = append(args, "--config", s.cfgPath)
+	}
+	if job.DryRun {
+		// só liga: dry_run na configuração vale mesmo para jobs sem a opção
+		args = append(args, "--dry-run")
+	}
+	if job.IsInvite() {
+		inviteArgs, err := s.inviteArgs(job, acct, budget)
+		if err != nil {
+			logf(slog.LevelError, "preparando a lista de convites", "err", err)
+			return scheduler.Result{Err: err}
+		}
+		args = append(inviteArgs, args...)
+	} else {
+		args = append([]string{
+			"crawl",
+			"--query", job.Query,
+			"--max-pages", fmt.Sprint(budget.Pages),
+			"--dump-html=" + strconv.FormatBool(job.DumpHTML),
+		}, args...)
+		if f := job.Facets; f != nil {
+			args = append(args, "--geo="+strings.Join(f.GeoURNs, ","), "--first-company="+strconv.FormatBool(f.FirstCompany))
+		}
+	}
+
+	logf(slog.LevelDebug, "runner", "cmd", crawlerBin+" "+strings.Join(args, " "))
+	cmd := exec.CommandContext(ctx, crawlerBin, args...)
+
+	// cancelamento (botão ou desligamento do servidor): SIGTERM faz o crawler
+	// terminar a página atual, gravar o parcial e fechar o Chrome; se não sair
+	// a tempo, SIGKILL.
+	cmd.Cancel = func() error {
+		logf(slog.LevelWarn, "cancelando: o crawler termina a página atual e grava o parcial")
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = crawlerStopGrace
+
+	// senha vai pelo stdin, nunca pela linha de comando; depois dela o stdin
+	// segue aberto para os comandos de desafio vindos da UI
+	stdin, _ := cmd.StdinPipe()
+	stdout, _ := cmd.StdoutPipe()
+	stderr, _ := cmd.StderrPipe()
+
+	if err := cmd.Start(); err != nil {
+		logf(slog.LevelError, "erro ao iniciar o crawler", "err", err)
+		return scheduler.Result{Err: err}
+	}
+	if _, err := stdin.Write(append(credLine, '\n')); err != nil {
+		logf(slog.LevelWarn, "enviando credenciais", "err", err)
+	}
+	h := s.hub(job.ID)
+	h.setInput(stdin)
+	defer func() {
+		h.setInput(nil)
+		_ = stdin.Close()
+	}()
+
+	outReader := bufio.NewScanner(stdout)
+	outReader.Buffer(make([]byte, 64*1024), 16<<20) // screenshots em base64
+	errReader := bufio.NewScanner(stderr)
+
+	doneCh := make(chan struct{})
+	go func() {
+		notified := map[string]bool{} // um aviso por tipo de desafio
+		for outReader.Scan() {
+			line := outReader.Text()
+			if raw, typ, ok := events.Parse(line); ok {
+				if typ == events.TypeMetric {
+					s.metrics.Record(raw)
+					continue
+				}
+				h.publish(streamEvent{Type: typ, Data: raw})
+				var ev events.Event
+				if typ == events.TypeChallenge && json.Unmarshal(raw, &ev) == nil && !notified[ev.Kind] {
+					notified[ev.Kind] = true
+					s.notifier.Publish(notify.Event{Type: notify.EventChallenge, Owner: job.Owner, Job: job, Challenge: ev.Kind})
+				}
+				continue
+			}
+			s.relayLog(job.ID, line)
+		}
+		// linha grande demais (ou erro de leitura) encerra o Scan: o resto é
+		// descartado para o crawler não travar com o pipe cheio
+		if err := outReader.Err(); err != nil {
+			logf(slog.LevelWarn, "lendo o stdout do crawler", "err", err)
+		}
+		_, _ = io.Copy(io.Discard, stdout)
+		doneCh <- struct{}{}
+	}()
+	go func() {
+		for errReader.Scan() {
+			s.relayLog(job.ID, errReader.Text())
+		}
+		if err := errReader.Err(); err != nil {
+			logf(slog.LevelWarn, "lendo o stderr do crawler", "err", err)
+		}
+		_, _ = io.Copy(io.Discard, stderr)
+		doneCh <- struct{}{}
+	}()
+
+	<-doneCh
+	<-doneCh
+	waitErr := cmd.Wait()
+
+	sum, err := summary.Read(job.Dir)
+	if err != nil {
+		logf(slog.LevelWarn, "resumo da execução ilegível", "err", err)
+	}
+	if sum.CSVPath == "" {
+		sum.CSVPath = findLatestCSV(job.Dir)
+	}
+	if waitErr != nil && sum.Error != "" {
+		waitErr = errors.New(sum.Error)
+	}
+	return scheduler.Result{Summary: sum, Err: waitErr}
+}
+
+// inviteArgs monta o "invites send" do job de convites: grava na pasta do
+// job a lista revisada (as linhas de InviteURLs no CSV do job de origem) e o
+// modelo da nota. Histórico e supressão são do usuário, valem para todas as
+// contas dele.
+func (s *server) inviteArgs(job jobs.Job, acct accounts.Account, budget scheduler.Budget) ([]string, error) {
+	src, ok := s.jobs.Get(job.InviteFrom)
+	if !ok || src.Owner != job.Owner || src.CSVPath == "" {
+		return nil, fmt.Errorf("job de origem %s sem resultados", job.InviteFrom)
+	}
+	rows, err := results.ReadCSV(src.CSVPath, 0)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]results.Row{}
+	for _, r := range rows {
+		byKey[results.Key(r)] = r
+	}
+	var list []results.Row
+	for _, u := range job.InviteURLs {
+		if r, ok := byKey[results.Key(results.Row{URL: u})]; ok {
+			list = append(list, r)
+		}
+	}
+	if len(list) == 0 {
+		return nil, errors.New("nenhum perfil da lista está nos resultados do job de origem")
+	}
+	if err := os.MkdirAll(job.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	listPath := filepath.Join(job.Dir, "invite_list.csv")
+	var buf bytes.Buffer
+	if err := results.WriteCSV(&buf, list); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(listPath, buf.Bytes(), 0o600); err != nil {
+		return nil, err
+	}
+
+	// as mesmas cotas que o registro de contas usou para o orçamento
+	q := acct.InviteQuota(quotaOf(s.cfg))
+	args := []string{
+		"invites", "send",
+		"--list", listPath,
+		"--account", acct.Alias,
+		"--daily-quota", fmt.Sprint(q.Daily),
+		"--weekly-quota", fmt.Sprint(q.Weekly),
+		"--ledger", filepath.Join(s.userDir(job.Owner), api.LedgerFile),
+		"--suppress", filepath.Join(s.userDir(job.Owner), api.SuppressFile),
+	}
+	if budget.Invites >= 0 {
+		// sem cota (-1) vale o defaults.max_invites do crawler
+		args = append(args, "--max", fmt.Sprint(budget.Invites))
+	}
+	if job.InviteNote != "" {
+		// o modelo vai num arquivo da pasta do job (fica junto do relatório)
+		notePath := filepath.Join(job.Dir, "invite_note.tmpl")
+		if err := os.WriteFile(notePath, []byte(job.InviteNote), 0o600); err != nil {
+			return nil, fmt.Errorf("gravando o modelo da nota: %w", err)
+		}
+		args = append(args, "--note-template", notePath)
+	}
+	return args, nil
+}
+
+// jobDone é o scheduler.DoneFunc: publica o evento final, fecha o stream
+// (que some da memória depois de hubLinger) e dispara as notificações.
+func (s *server) jobDone(j jobs.Job) {
+	h := s.hub(j.ID)
+	h.publish(streamEvent{Type: "done", Data: s.jobResponse(j)})
+	h.close()
+	time.AfterFunc(hubLinger, func() { s.dropHub(j.ID, h) })
+
+	switch j.Status {
+	case jobs.StatusDone:
+		s.notifier.Publish(notify.Event{Type: notify.EventJobCompleted, Owner: j.Owner, Job: j})
+	case jobs.StatusFailed:
+		s.notifier.Publish(notify.Event{Type: notify.EventJobFailed, Owner: j.Owner, Job: j})
+	}
+	if rows := s.newProfiles(j); len(rows) > 0 {
+		s.notifier.Publish(notify.Event{Type: notify.EventNewProfiles, Owner: j.Owner, Job: j, NewProfiles: rows})
+	}
+}
+
+// newProfiles devolve os perfis do CSV de j que não aparecem em nenhum outro
+// job do mesmo usuário.
+func (s *server) newProfiles(j jobs.Job) []results.Row {
+	if j.CSVPath == "" {
+		return nil
+	}
+	rows, err := results.ReadCSV(j.CSVPath, 0)
+	if err != nil || len(rows) == 0 {
+		return nil
+	}
+	var old []map[string]bool
+	for _, other := range s.jobs.List(j.Owner) {
+		if other.ID != j.ID && other.CSVPath != "" {
+			old = append(old, s.profileKeys(other))
+		}
+	}
+	seen := map[string]bool{}
+	var out []results.Row
+	for _, r := range rows {
+		k := results.Key(r)
+		if k == "" || seen[k] || slices.ContainsFunc(old, func(keys map[string]bool) bool { return keys[k] }) {
+			continue
+		}
+		seen[k] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+// profileKeys devolve as chaves dos perfis do CSV de j, lendo o arquivo só
+// na primeira vez.
+func (s *server) profileKeys(j jobs.Job) map[string]bool {
+	s.keysMu.Lock()
+	keys, ok := s.keys[j.ID]
+	s.keysMu.Unlock()
+	if ok {
+		return keys
+	}
+	rows, err := results.ReadCSV(j.CSVPath, 0)
+	if err != nil {
+		return nil
+	}
+	keys = make(map[string]bool, len(rows))
+	for _, r := range rows {
+		if k := results.Key(r); k != "" {
+			keys[k] = true
+		}
+	}
+	s.keysMu.Lock()
+	s.keys[j.ID] = keys
+	s.keysMu.Unlock()
+	return keys
+}
+
+func (s *server) jobResponse(j jobs.Job) runResponse {
+	resp := runResponse{
+		Ok:        j.Status == jobs.StatusDone,
+		Status:    j.Status,
+		Message:   j.Message,
+		JobID:     j.ID,
+		CSVPath:   j.CSVPath,
+		StartedAt: j.StartedAt.Format(time.RFC3339),
+		EndedAt:   j.EndedAt.Format(time.RFC3339),
+	}
+	return resp
+}
+
+func findLatestCSV(outDir string) string {
+	entries, err := filepath.Glob(filepath.Join(outDir, "linkedin_*.csv"))
+	if err != nil || len(entries) == 0 {
+		return ""
+	}
+	var best string
+	for _, e := range entries {
+		if best == "" || e > best {
+			best = e
+		}
+	}
+	return best
+}